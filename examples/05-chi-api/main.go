@@ -0,0 +1,146 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yoockh/go-api-utils/pkg-chi/middleware"
+	chirequest "github.com/yoockh/go-api-utils/pkg-chi/request"
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+	"github.com/yoockh/go-api-utils/pkg/config"
+	stdmiddleware "github.com/yoockh/go-api-utils/pkg/middleware"
+	"github.com/yoockh/go-api-utils/pkg/request"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// Book model
+type Book struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title" sanitize:"trim"`
+	Author string `json:"author" sanitize:"trim"`
+}
+
+var (
+	mu     sync.Mutex
+	books  = map[int]Book{}
+	nextID = 1
+	secret = "your-secret"
+)
+
+func main() {
+	cfg := config.LoadEnv()
+
+	r := chi.NewRouter()
+	// The library's net/http middleware plugs straight into chi
+	r.Use(stdmiddleware.Recover, stdmiddleware.Logger, stdmiddleware.CORS)
+
+	// Public routes
+	r.Post("/login", handleLogin)
+	r.Get("/books", listBooks)
+	r.Get("/books/{id}", getBook)
+
+	// Authenticated group
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.JWTMiddleware(middleware.JWTConfig{SecretKey: secret}))
+		r.Get("/me", handleMe)
+
+		// Admin-only sub-routes
+		r.Route("/admin/books", func(r chi.Router) {
+			r.Use(middleware.RequireRoles("admin"))
+			r.Post("/", createBook)
+			r.Delete("/{id}", deleteBook)
+		})
+	})
+
+	log.Printf("Server running on port %s", cfg.Port)
+	log.Fatal(http.ListenAndServe(":"+cfg.Port, r))
+}
+
+// POST /login - issue a token (demo: every email gets the admin role)
+func handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email" sanitize:"trim,lower"`
+	}
+	if err := request.ParseJSON(r, &req); err != nil || req.Email == "" {
+		response.BadRequest(w, "email is required")
+		return
+	}
+	token, err := auth.GenerateToken(1, req.Email, "admin", secret, 24*time.Hour)
+	if err != nil {
+		response.InternalServerError(w, "Failed to issue token")
+		return
+	}
+	response.Success(w, "Login successful", map[string]string{"token": token})
+}
+
+// GET /me - current user from the JWT
+func handleMe(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, "Profile", map[string]any{
+		"user_id": middleware.CurrentUserID(r),
+		"email":   middleware.CurrentEmail(r),
+		"role":    middleware.CurrentRole(r),
+	})
+}
+
+// GET /books
+func listBooks(w http.ResponseWriter, r *http.Request) {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]Book, 0, len(books))
+	for _, b := range books {
+		list = append(list, b)
+	}
+	response.Success(w, "Books retrieved successfully", list)
+}
+
+// GET /books/{id}
+func getBook(w http.ResponseWriter, r *http.Request) {
+	id, err := chirequest.GetIDFromURL(r)
+	if err != nil {
+		response.BadRequest(w, "Invalid book ID")
+		return
+	}
+	mu.Lock()
+	b, ok := books[id]
+	mu.Unlock()
+	if !ok {
+		response.NotFound(w, "Book not found")
+		return
+	}
+	response.Success(w, "Book retrieved successfully", b)
+}
+
+// POST /admin/books
+func createBook(w http.ResponseWriter, r *http.Request) {
+	var b Book
+	if err := request.ParseJSON(r, &b); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+	if b.Title == "" || b.Author == "" {
+		response.BadRequest(w, "title and author are required")
+		return
+	}
+	mu.Lock()
+	b.ID = nextID
+	nextID++
+	books[b.ID] = b
+	mu.Unlock()
+	response.Created(w, "Book created successfully", b)
+}
+
+// DELETE /admin/books/{id}
+func deleteBook(w http.ResponseWriter, r *http.Request) {
+	id, err := chirequest.GetIDFromURL(r)
+	if err != nil {
+		response.BadRequest(w, "Invalid book ID")
+		return
+	}
+	mu.Lock()
+	delete(books, id)
+	mu.Unlock()
+	response.NoContent(w)
+}
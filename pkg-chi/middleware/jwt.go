@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+	stdmiddleware "github.com/yoockh/go-api-utils/pkg/middleware"
+	"github.com/yoockh/go-api-utils/pkg/options"
+)
+
+// JWTConfig configures JWT middleware behavior.
+// Example:
+//
+//	r.Group(func(r chi.Router) {
+//	    r.Use(middleware.JWTMiddleware(middleware.JWTConfig{SecretKey: "secret", UseCustomToken: true}))
+//	    r.Get("/profile", profileHandler)
+//	})
+type JWTConfig = stdmiddleware.JWTConfig
+
+// JWTMiddleware validates Bearer token from Authorization header and stores claims in the request context.
+// It is pkg/middleware.JWT, so the accessors of both packages read the same values.
+func JWTMiddleware(config JWTConfig, opts ...options.Option) func(http.Handler) http.Handler {
+	return stdmiddleware.JWT(config, opts...)
+}
+
+// GetTokenData returns custom token data from the request context or empty map if not present.
+// Example:
+//
+//	data := middleware.GetTokenData(r)
+//	userID := request.GetInt(data, "user_id")
+func GetTokenData(r *http.Request) map[string]interface{} {
+	return stdmiddleware.TokenDataFromContext(r.Context())
+}
+
+// GetClaims returns the basic token claims, nil when a custom token (or none) was used.
+func GetClaims(r *http.Request) *auth.Claims {
+	c, _ := stdmiddleware.ClaimsFromContext(r.Context())
+	return c
+}
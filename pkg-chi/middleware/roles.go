@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	stdmiddleware "github.com/yoockh/go-api-utils/pkg/middleware"
+)
+
+// RequireRoles allows only requests whose role is included in the allowed list.
+// It reads the role stored by JWTMiddleware (custom token or basic claims).
+// Example:
+//
+//	r.Route("/admin", func(r chi.Router) {
+//	    r.Use(middleware.JWTMiddleware(cfg), middleware.RequireRoles("admin"))
+//	    r.Get("/stats", adminStatsHandler)
+//	})
+func RequireRoles(allowed ...string) func(http.Handler) http.Handler {
+	return stdmiddleware.RequireRoles(allowed...)
+}
+
+// RequirePermissions allows only requests whose custom token grants every listed permission
+// (see pkg/middleware.RequirePermissions).
+func RequirePermissions(required ...string) func(http.Handler) http.Handler {
+	return stdmiddleware.RequirePermissions(required...)
+}
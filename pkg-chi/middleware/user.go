@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+
+	stdmiddleware "github.com/yoockh/go-api-utils/pkg/middleware"
+)
+
+// CurrentUserID returns user ID from the request context (custom token or basic claims), 0 if not found.
+// Example:
+//
+//	uid := middleware.CurrentUserID(r)
+func CurrentUserID(r *http.Request) uint { return stdmiddleware.CurrentUserID(r) }
+
+// CurrentEmail returns email from the request context or empty string.
+func CurrentEmail(r *http.Request) string { return stdmiddleware.CurrentEmail(r) }
+
+// CurrentRole returns role from the request context or empty string.
+func CurrentRole(r *http.Request) string { return stdmiddleware.CurrentRole(r) }
@@ -0,0 +1,80 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	echorequest "github.com/yoockh/go-api-utils/pkg-echo/request"
+)
+
+// URLParam returns the chi route parameter key, trimmed, or "" if missing.
+// Example:
+//
+//	r.Get("/books/{slug}", func(w http.ResponseWriter, r *http.Request) {
+//	    slug := request.URLParam(r, "slug")
+//	})
+func URLParam(r *http.Request, key string) string {
+	return strings.TrimSpace(chi.URLParam(r, key))
+}
+
+// GetIDFromURL returns the {id} route parameter as int
+// This is the chi counterpart of pkg/request.GetIDFromURL: it reads the named
+// parameter instead of the last path segment, so it also works for /books/{id}/reviews.
+// Example:
+//
+//	r.Get("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+//	    id, err := request.GetIDFromURL(r)
+//	    if err != nil {
+//	        response.BadRequest(w, "Invalid product ID")
+//	        return
+//	    }
+//	})
+func GetIDFromURL(r *http.Request) (int, error) {
+	return URLParamInt(r, "id")
+}
+
+// URLParamInt returns the chi route parameter key as int
+// Example:
+//
+//	bookID, err := request.URLParamInt(r, "bookID")
+func URLParamInt(r *http.Request, key string) (int, error) {
+	v := URLParam(r, key)
+	if v == "" {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.Atoi(v)
+}
+
+// URLParamUint parses a route param (e.g., {id}) into uint, 0 if invalid.
+// Example:
+//
+//	id := request.URLParamUint(r, "id")
+func URLParamUint(r *http.Request, key string) uint {
+	n, err := URLParamInt(r, key)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return uint(n)
+}
+
+// GetInt, GetUint, GetString, GetBool and GetFloat read values from custom token data
+// Returns the zero value if the key is missing or has another type.
+// Example:
+//
+//	data := middleware.GetTokenData(r)
+//	userID := request.GetInt(data, "user_id")
+func GetInt(data map[string]interface{}, key string) int { return echorequest.GetInt(data, key) }
+
+func GetUint(data map[string]interface{}, key string) uint { return echorequest.GetUint(data, key) }
+
+func GetString(data map[string]interface{}, key string) string {
+	return echorequest.GetString(data, key)
+}
+
+func GetBool(data map[string]interface{}, key string) bool { return echorequest.GetBool(data, key) }
+
+func GetFloat(data map[string]interface{}, key string) float64 {
+	return echorequest.GetFloat(data, key)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/yoockh/go-api-utils/pkg/options"
 )
 
 // Claims represents JWT payload structure (basic fields)
@@ -27,18 +28,19 @@ var (
 )
 
 // GenerateToken creates JWT token for user (basic version)
-// Use this after successful login
+// Use this after successful login; options.WithClock sets the issue time (default clock.Now)
 // Example:
 //
 //	token, err := auth.GenerateToken(1, "user@example.com", "admin", secretKey, 24*time.Hour)
-func GenerateToken(userID int, email, role, secretKey string, expiry time.Duration) (string, error) {
+func GenerateToken(userID int, email, role, secretKey string, expiry time.Duration, opts ...options.Option) (string, error) {
+	now := options.Apply(opts...).Now()
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -58,12 +60,13 @@ func GenerateToken(userID int, email, role, secretKey string, expiry time.Durati
 //	    "role": "admin",
 //	}
 //	token, err := auth.GenerateCustomToken(data, secretKey, 24*time.Hour)
-func GenerateCustomToken(data map[string]interface{}, secretKey string, expiry time.Duration) (string, error) {
+func GenerateCustomToken(data map[string]interface{}, secretKey string, expiry time.Duration, opts ...options.Option) (string, error) {
+	now := options.Apply(opts...).Now()
 	claims := &CustomClaims{
 		Data: data,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -72,17 +75,19 @@ func GenerateCustomToken(data map[string]interface{}, secretKey string, expiry t
 }
 
 // ValidateToken validates JWT token and returns claims
-// Use this in middleware to check token validity
+// Use this in middleware to check token validity; options.WithClock sets the time expiry
+// is checked against (default clock.Now)
 // Example:
 //
 //	claims, err := auth.ValidateToken(tokenString, secretKey)
-func ValidateToken(tokenString, secretKey string) (*Claims, error) {
+func ValidateToken(tokenString, secretKey string, opts ...options.Option) (*Claims, error) {
+	o := options.Apply(opts...)
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidToken
 		}
 		return []byte(secretKey), nil
-	})
+	}, jwt.WithTimeFunc(o.Now))
 
 	// Map parsing errors to domain errors
 	if err != nil {
@@ -99,7 +104,7 @@ func ValidateToken(tokenString, secretKey string) (*Claims, error) {
 	}
 
 	// Additional safety (clock skew edge cases)
-	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
+	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(o.Now()) {
 		return nil, ErrExpiredToken
 	}
 
@@ -113,13 +118,14 @@ func ValidateToken(tokenString, secretKey string) (*Claims, error) {
 //	data, err := auth.ValidateCustomToken(tokenString, secretKey)
 //	userID := int(data["user_id"].(float64))
 //	email := data["email"].(string)
-func ValidateCustomToken(tokenString, secretKey string) (map[string]interface{}, error) {
+func ValidateCustomToken(tokenString, secretKey string, opts ...options.Option) (map[string]interface{}, error) {
+	o := options.Apply(opts...)
 	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, ErrInvalidToken
 		}
 		return []byte(secretKey), nil
-	})
+	}, jwt.WithTimeFunc(o.Now))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -132,7 +138,7 @@ func ValidateCustomToken(tokenString, secretKey string) (map[string]interface{},
 	if !ok || !token.Valid {
 		return nil, ErrInvalidToken
 	}
-	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(time.Now()) {
+	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(o.Now()) {
 		return nil, ErrExpiredToken
 	}
 	return claims.Data, nil
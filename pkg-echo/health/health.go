@@ -1,10 +1,12 @@
 package health
 
 import (
+	"database/sql"
 	"net/http"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg/debug"
 	"gorm.io/gorm"
 )
 
@@ -31,3 +33,20 @@ func NewHandler(db *gorm.DB) echo.HandlerFunc {
 		})
 	}
 }
+
+// NewStatsHandler returns runtime stats (goroutines, memory, GC, uptime) and the DB pool stats as JSON.
+// Protect the route; the output is meant for operators.
+// Example:
+//
+//	admin.GET("/stats", health.NewStatsHandler(db))
+func NewStatsHandler(db *gorm.DB) echo.HandlerFunc {
+	dbs := map[string]*sql.DB{}
+	if db != nil {
+		if sqlDB, err := db.DB(); err == nil {
+			dbs["main"] = sqlDB
+		}
+	}
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, debug.ReadStats(dbs))
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg/auditlog"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+)
+
+// Audit records successful POST, PUT, PATCH and DELETE requests in the audit log
+// The actor is the user_id set by JWTMiddleware, so register Audit after it.
+// Handlers call auditlog.Track(c.Request().Context(), ...) to add the resource ID and diff.
+// Example:
+//
+//	store := auditlog.NewPostgresStore(sqlDB)
+//	api := e.Group("/api", middleware.JWTMiddleware(jwtConfig), middleware.Audit(store))
+func Audit(store auditlog.Store) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions {
+				return next(c)
+			}
+			c.SetRequest(req.WithContext(auditlog.Begin(req.Context())))
+
+			err := next(c)
+			status := c.Response().Status
+			if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			} else if err != nil {
+				status = http.StatusInternalServerError
+			}
+			if status >= 400 {
+				return err
+			}
+
+			e := auditlog.Entry{
+				Action:     auditlog.ActionForMethod(req.Method),
+				Resource:   c.Path(),
+				ResourceID: c.Param("id"),
+				IP:         c.RealIP(),
+				UserAgent:  req.UserAgent(),
+				RequestID:  logging.RequestID(c.Request().Context()),
+				Status:     status,
+			}
+			if uid := c.Get("user_id"); uid != nil {
+				e.ActorID = fmt.Sprint(uid)
+			}
+			if _, auditErr := auditlog.Complete(c.Request().Context(), store, e); auditErr != nil {
+				log.Printf("audit log error: %v", auditErr)
+			}
+			return err
+		}
+	}
+}
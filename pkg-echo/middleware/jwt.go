@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/yoockh/go-api-utils/pkg-echo/auth"
 	"github.com/yoockh/go-api-utils/pkg-echo/response"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/options"
 )
 
 // JWTConfig configures JWT middleware behavior.
@@ -22,7 +26,8 @@ type JWTConfig struct {
 // JWTMiddleware validates Bearer token from Authorization header and injects claims into context.
 // For custom token: stores map data under "token_data".
 // For basic token: stores user_id, email, role, and "claims".
-func JWTMiddleware(config JWTConfig) echo.MiddlewareFunc {
+// opts are passed to the auth token validation (options.WithClock).
+func JWTMiddleware(config JWTConfig, opts ...options.Option) echo.MiddlewareFunc {
 	if config.SecretKey == "" {
 		panic("JWT secret key cannot be empty")
 	}
@@ -44,7 +49,7 @@ func JWTMiddleware(config JWTConfig) echo.MiddlewareFunc {
 			tokenString := parts[1]
 
 			if config.UseCustomToken {
-				data, err := auth.ValidateCustomToken(tokenString, config.SecretKey)
+				data, err := auth.ValidateCustomToken(tokenString, config.SecretKey, opts...)
 				if err != nil {
 					if err == auth.ErrExpiredToken {
 						return response.Unauthorized(c, "token expired")
@@ -63,7 +68,7 @@ func JWTMiddleware(config JWTConfig) echo.MiddlewareFunc {
 					c.Set("role", v)
 				}
 			} else {
-				claims, err := auth.ValidateToken(tokenString, config.SecretKey)
+				claims, err := auth.ValidateToken(tokenString, config.SecretKey, opts...)
 				if err != nil {
 					if err == auth.ErrExpiredToken {
 						return response.Unauthorized(c, "token expired")
@@ -78,6 +83,15 @@ func JWTMiddleware(config JWTConfig) echo.MiddlewareFunc {
 				}
 			}
 
+			// Enrich the request-scoped logger (no-op if RequestLogger is not used)
+			// and the error-reporting scope
+			ctx := errs.WithScope(c.Request().Context())
+			logging.With(ctx, "user_id", c.Get("user_id"))
+			if uid := c.Get("user_id"); uid != nil {
+				errs.SetUser(ctx, fmt.Sprint(uid))
+			}
+			c.SetRequest(c.Request().WithContext(ctx))
+
 			return next(c)
 		}
 	}
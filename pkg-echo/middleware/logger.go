@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+)
+
+// RequestLogger attaches a request-scoped logger (request_id, method, route) to the request context.
+// JWTMiddleware adds user_id to the same logger once the token is validated.
+// Example:
+//
+//	e.Use(middleware.RequestLogger(logger))
+//
+//	// inside handlers
+//	logging.FromContext(c.Request().Context()).Info("book created", "id", book.ID)
+func RequestLogger(base *slog.Logger) echo.MiddlewareFunc {
+	if base == nil {
+		base = slog.Default()
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(logging.RequestIDHeader)
+			if !logging.ValidRequestID(id) {
+				id = logging.NewRequestID()
+			}
+			c.Response().Header().Set(logging.RequestIDHeader, id)
+			c.Set("request_id", id)
+
+			logger := base.With(
+				slog.String("request_id", id),
+				slog.String("method", req.Method),
+				slog.String("route", c.Path()),
+			)
+			ctx := logging.WithRequestID(req.Context(), id)
+			ctx = logging.NewContext(ctx, logger)
+			c.SetRequest(req.WithContext(ctx))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg-echo/response"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+)
+
+// RecoverConfig configures RecoverWithConfig
+type RecoverConfig struct {
+	// RePanic panics again after reporting and responding, so development servers and
+	// debuggers stop on it. Leave it off in production.
+	RePanic bool
+}
+
+// Recover turns panics into 500 responses and sends them to the error reporter
+// Register it first so the reported event includes the user ID set by JWTMiddleware.
+// Example:
+//
+//	e.Use(middleware.Recover())
+//	e.Use(middleware.RequestLogger(logger))
+func Recover() echo.MiddlewareFunc {
+	return RecoverWithConfig(RecoverConfig{})
+}
+
+// RecoverWithConfig is Recover with options
+// The reported panic carries the request ID, route and user ID; the 500 envelope includes the
+// request ID so users can quote it to support. The ID comes from RequestLogger, otherwise
+// from X-Request-ID or a new one, and is echoed in the X-Request-ID header.
+// Example:
+//
+//	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
+//		RePanic: os.Getenv("APP_ENV") == "development",
+//	}))
+func RecoverWithConfig(config RecoverConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			req := c.Request()
+			ctx := errs.WithScope(req.Context())
+			errs.SetRoute(ctx, c.Path())
+			c.SetRequest(req.WithContext(ctx))
+
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				id := response.RequestID(c)
+				if id == "" {
+					id = c.Request().Header.Get(logging.RequestIDHeader)
+					if !logging.ValidRequestID(id) {
+						id = logging.NewRequestID()
+					}
+					c.Response().Header().Set(logging.RequestIDHeader, id)
+				}
+				req := c.Request().WithContext(logging.WithRequestID(c.Request().Context(), id))
+				log.Printf("panic (request %s): %v\n%s", id, rec, debug.Stack())
+				errs.ReportPanic(req, rec)
+				if !c.Response().Committed {
+					err = c.JSON(http.StatusInternalServerError, response.Response{
+						Error:     "internal server error",
+						Code:      "internal",
+						RequestID: id,
+					})
+				}
+				if config.RePanic {
+					panic(rec)
+				}
+			}()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	stdmiddleware "github.com/yoockh/go-api-utils/pkg/middleware"
+)
+
+// StaticConfig is an alias of the net/http static config so both packages share options
+type StaticConfig = stdmiddleware.StaticConfig
+
+// Static serves an embedded or on-disk frontend build with SPA fallback.
+// Unmatched requests continue to the Echo router, so register API routes as usual.
+// Example:
+//
+//	sub, _ := fs.Sub(dist, "dist")
+//	e.Use(middleware.Static(middleware.StaticConfig{Root: sub, SPA: true, ImmutablePrefixes: []string{"/assets/"}}))
+func Static(config StaticConfig) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(stdmiddleware.Static(config))
+}
@@ -0,0 +1,25 @@
+package orm
+
+import (
+	"github.com/yoockh/go-api-utils/pkg/repository"
+	"gorm.io/gorm"
+)
+
+// ApplyFilter adds a compiled filter condition to the WHERE clause; an empty condition leaves db unchanged
+// Use repository.FilterSpec to compile ?filter= and filter[field][op] parameters.
+// Example:
+//
+//	var bookFilter = repository.FilterSpec{"price": "books.price", "author": "authors.name"}
+//
+//	cond, err := bookFilter.FromQuery(c.QueryParams())
+//	if err != nil {
+//		return err
+//	}
+//	base := orm.ApplyFilter(db.Joins("JOIN authors ON authors.id = books.author_id"), cond)
+//	meta, err := orm.Paginate(base, &Book{}, request.Pagination(c), &books)
+func ApplyFilter(db *gorm.DB, cond repository.Condition) *gorm.DB {
+	if cond.Empty() {
+		return db
+	}
+	return db.Where(cond.Query, cond.Args...)
+}
@@ -0,0 +1,68 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+const metricsStartKey = "metrics:start"
+
+// RegisterMetrics records GORM query durations and connection pool stats in reg
+// Exposes gorm_query_duration_seconds{operation,table} and the db_* pool metrics labeled db=name.
+// Example:
+//
+//	db, _ := orm.ConnectGORM(dsn)
+//	orm.RegisterMetrics(db, metrics.Default, "main")
+//	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+func RegisterMetrics(db *gorm.DB, reg *metrics.Registry, name string) error {
+	durations := metrics.NewHistogram("gorm_query_duration_seconds", "GORM query duration in seconds.", nil, "db", "operation", "table")
+
+	before := func(string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			tx.InstanceSet(metricsStartKey, time.Now())
+		}
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			v, ok := tx.InstanceGet(metricsStartKey)
+			if !ok {
+				return
+			}
+			start, ok := v.(time.Time)
+			if !ok {
+				return
+			}
+			durations.Observe(time.Since(start).Seconds(), name, operation, tx.Statement.Table)
+		}
+	}
+	if err := registerAround(db, "metrics", before, after); err != nil {
+		return fmt.Errorf("failed to register metrics callbacks: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+	reg.MustRegister(durations, metrics.NewDBStatsCollector(sqlDB, name))
+	return nil
+}
+
+// MonitorPool samples the GORM connection pool on a ticker (see metrics.MonitorDBPool)
+// Example:
+//
+//	stop, err := orm.MonitorPool(ctx, db, metrics.Default, metrics.DBPoolConfig{Name: "main"})
+//	if err != nil {
+//		return err
+//	}
+//	defer stop()
+func MonitorPool(ctx context.Context, db *gorm.DB, reg *metrics.Registry, cfg metrics.DBPoolConfig) (stop func(), err error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
+	}
+	return metrics.MonitorDBPool(ctx, reg, sqlDB, cfg), nil
+}
@@ -1,28 +1,83 @@
 package orm
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
+	"github.com/yoockh/go-api-utils/pkg/database"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/options"
+	"github.com/yoockh/go-api-utils/pkg/retry"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// openWithRetry opens a GORM connection, retrying while the database is unreachable
+// Permanent failures such as a wrong password are returned after the first attempt.
+func openWithRetry(dsn string, o options.Options) (*gorm.DB, error) {
+	gormLogger := logger.Default.LogMode(logger.Info)
+	if o.Logger != nil {
+		gormLogger = logger.NewSlogLogger(o.Logger, logger.Config{
+			SlowThreshold: 200 * time.Millisecond,
+			LogLevel:      logger.Info,
+		})
+	}
+	var db *gorm.DB
+	err := retry.Do(context.Background(), func(ctx context.Context) error {
+		var err error
+		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger: gormLogger,
+		})
+		return err
+	}, retry.Attempts(5), retry.ExponentialBackoff(500*time.Millisecond, 5*time.Second),
+		retry.RetryIf(errs.IsConnectionFailure))
+	if err != nil {
+		return nil, err
+	}
+	if o.Pool != (options.Pool{}) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		database.ApplyPool(sqlDB, o.Pool)
+	}
+	if d := cmp.Or(o.Timeout, database.QueryTimeout()); d > 0 {
+		if err := SetQueryTimeout(db, d); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// logConnected reports a new connection to the configured logger, the log package by default
+func logConnected(o options.Options, msg string) {
+	if o.Logger != nil {
+		o.Logger.Info(msg)
+		return
+	}
+	log.Println(msg)
+}
+
 // ConnectGORM connects to PostgreSQL using GORM
+// Options: options.WithLogger (GORM's SQL log and connection messages), options.WithPool
+// and options.WithTimeout (per-statement timeout, see SetQueryTimeout).
 // Example:
 //
 //	db, err := orm.ConnectGORM("host=localhost port=5432 user=postgres password=secret dbname=mydb sslmode=disable")
-func ConnectGORM(dsn string) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+//	db, err = orm.ConnectGORM(dsn, options.WithLogger(logger), options.WithPool(options.Pool{MaxOpen: 50}))
+func ConnectGORM(dsn string, opts ...options.Option) (*gorm.DB, error) {
+	o := options.Apply(opts...)
+	db, err := openWithRetry(dsn, o)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	log.Println("GORM connected to PostgreSQL")
+	logConnected(o, "GORM connected to PostgreSQL")
 	return db, nil
 }
 
@@ -40,7 +95,9 @@ func AutoMigrate(db *gorm.DB, models ...interface{}) error {
 
 // Init initializes GORM connection from a database URL while respecting SKIP_DB
 // If SKIP_DB=1, returns (nil, nil). If databaseURL is empty it will try SUPABASE_URL or DATABASE_URL env.
-func Init(databaseURL string) (*gorm.DB, error) {
+// It accepts the same options as ConnectGORM.
+func Init(databaseURL string, opts ...options.Option) (*gorm.DB, error) {
+    o := options.Apply(opts...)
     // respect SKIP_DB
     if os.Getenv("SKIP_DB") == "1" {
         log.Println("SKIP_DB=1 set, skipping DB initialization")
@@ -62,13 +119,11 @@ func Init(databaseURL string) (*gorm.DB, error) {
         return nil, fmt.Errorf("no database URL provided")
     }
 
-    db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{
-        Logger: logger.Default.LogMode(logger.Info),
-    })
+    db, err := openWithRetry(databaseURL, o)
     if err != nil {
         return nil, fmt.Errorf("failed to connect to database: %w", err)
     }
 
-    log.Println("GORM connected to PostgreSQL (Init)")
+    logConnected(o, "GORM connected to PostgreSQL (Init)")
     return db, nil
 }
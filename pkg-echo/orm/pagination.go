@@ -1,21 +1,39 @@
 package orm
 
-import "gorm.io/gorm"
+import (
+	"context"
+
+	"github.com/yoockh/go-api-utils/pkg/pagination"
+	"github.com/yoockh/go-api-utils/pkg/repository"
+	"gorm.io/gorm"
+)
 
 // ApplyPagination applies LIMIT/OFFSET to a query based on page and perPage.
-// Page starts from 1. Invalid values fall back to page=1, perPage=10.
+// Page starts from 1. Invalid values fall back to page=1, perPage=10 (see pagination.New).
 // Example:
 //
 //	db = orm.ApplyPagination(db, page, perPage)
 func ApplyPagination(db *gorm.DB, page, perPage int) *gorm.DB {
-	if page < 1 {
-		page = 1
-	}
-	if perPage <= 0 || perPage > 1000 {
-		perPage = 10
+	p := pagination.New(page, perPage)
+	return db.Limit(p.Limit()).Offset(p.Offset())
+}
+
+// CountOption configures how CountAndPaginate and Paginate count rows
+type CountOption func(*countConfig)
+
+type countConfig struct {
+	estimateAbove int64
+}
+
+// EstimateAbove uses the Postgres planner estimate instead of COUNT(*) when it expects more
+// than threshold rows (see repository.CountRows); Paginate then sets Meta.Estimated
+// Example:
+//
+//	meta, err := orm.Paginate(db.Where("kind = ?", kind), &Event{}, p, &events, orm.EstimateAbove(100000))
+func EstimateAbove(threshold int64) CountOption {
+	return func(c *countConfig) {
+		c.estimateAbove = threshold
 	}
-	offset := (page - 1) * perPage
-	return db.Limit(perPage).Offset(offset)
 }
 
 // CountAndPaginate counts rows for the given model and fetches the paginated records into out.
@@ -24,20 +42,62 @@ func ApplyPagination(db *gorm.DB, page, perPage int) *gorm.DB {
 //
 //	var books []Book
 //	total, err := orm.CountAndPaginate(db.Where("author_id = ?", id), &Book{}, page, perPage, &books)
-func CountAndPaginate(base *gorm.DB, model interface{}, page, perPage int, out interface{}) (int64, error) {
-	if page < 1 {
-		page = 1
+func CountAndPaginate(base *gorm.DB, model interface{}, page, perPage int, out interface{}, opts ...CountOption) (int64, error) {
+	total, _, err := countAndPaginate(base, model, page, perPage, out, opts)
+	return total, err
+}
+
+// Paginate is CountAndPaginate for pagination.Params, returning the response meta
+// Example:
+//
+//	var books []Book
+//	meta, err := orm.Paginate(db.Where("author_id = ?", id), &Book{}, request.Pagination(c), &books)
+//	if err != nil {
+//		return err
+//	}
+//	return response.Paginated(c, "books retrieved", books, meta)
+func Paginate(base *gorm.DB, model interface{}, p pagination.Params, out interface{}, opts ...CountOption) (pagination.Meta, error) {
+	total, estimated, err := countAndPaginate(base, model, p.Page, p.PerPage, out, opts)
+	if err != nil {
+		return pagination.Meta{}, err
 	}
-	if perPage <= 0 || perPage > 1000 {
-		perPage = 10
+	meta := p.Meta(total)
+	meta.Estimated = estimated
+	return meta, nil
+}
+
+func countAndPaginate(base *gorm.DB, model interface{}, page, perPage int, out interface{}, opts []CountOption) (int64, bool, error) {
+	var cfg countConfig
+	for _, opt := range opts {
+		opt(&cfg)
 	}
-	var total int64
-	if err := base.Session(&gorm.Session{}).Model(model).Count(&total).Error; err != nil {
-		return 0, err
+	total, estimated, err := count(base, model, cfg)
+	if err != nil {
+		return 0, false, err
 	}
 	q := ApplyPagination(base, page, perPage)
 	if err := q.Find(out).Error; err != nil {
-		return 0, err
+		return 0, false, err
+	}
+	return total, estimated, nil
+}
+
+func count(base *gorm.DB, model interface{}, cfg countConfig) (int64, bool, error) {
+	if cfg.estimateAbove > 0 {
+		// Build the row query without running it, then ask the planner how many rows it returns
+		var rows []map[string]interface{}
+		stmt := base.Session(&gorm.Session{DryRun: true}).Model(model).Select("1").Find(&rows).Statement
+		ctx := stmt.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if est, err := repository.EstimateRows(ctx, base.Statement.ConnPool, stmt.SQL.String(), stmt.Vars...); err == nil && est > cfg.estimateAbove {
+			return est, true, nil
+		}
+	}
+	var total int64
+	if err := base.Session(&gorm.Session{}).Model(model).Count(&total).Error; err != nil {
+		return 0, false, err
 	}
-	return total, nil
+	return total, false, nil
 }
@@ -0,0 +1,17 @@
+package orm
+
+import (
+	"github.com/yoockh/go-api-utils/pkg/repository"
+	"gorm.io/gorm"
+)
+
+// ApplySearch adds the condition of a free-text search; a blank q leaves db unchanged
+// Example:
+//
+//	var bookSearch = repository.Search{Columns: []string{"books.title", "authors.name"}}
+//
+//	base := orm.ApplySearch(db.Joins("JOIN authors ON authors.id = books.author_id"), bookSearch, c.QueryParam("q"))
+//	meta, err := orm.Paginate(base, &Book{}, request.Pagination(c), &books)
+func ApplySearch(db *gorm.DB, s repository.Search, q string) *gorm.DB {
+	return ApplyFilter(db, s.Condition(q))
+}
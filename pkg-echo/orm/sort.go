@@ -0,0 +1,24 @@
+package orm
+
+import (
+	"github.com/yoockh/go-api-utils/pkg/repository"
+	"gorm.io/gorm"
+)
+
+// ApplySort adds the ORDER BY of a validated order; an empty order leaves db unchanged
+// Use repository.SortSpec to turn the request's sort parameter into an Order.
+// Example:
+//
+//	var bookSort = repository.SortSpec{"created": "books.created_at", "title": "books.title"}
+//
+//	order, err := bookSort.Parse(c.QueryParam("sort"), "-created")
+//	if err != nil {
+//		return err
+//	}
+//	meta, err := orm.Paginate(orm.ApplySort(db, order), &Book{}, request.Pagination(c), &books)
+func ApplySort(db *gorm.DB, order repository.Order) *gorm.DB {
+	if len(order) == 0 {
+		return db
+	}
+	return db.Order(order.SQL())
+}
@@ -0,0 +1,29 @@
+package orm
+
+import (
+	"github.com/yoockh/go-api-utils/pkg/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TenantScope restricts a query on shared tables to the tenant of the statement context
+// (db.WithContext), matching the tenant_id column of the model's table; without a tenant the
+// query fails with tenant.ErrNoTenant instead of returning every tenant's rows. Schema-per-tenant
+// setups use tenant.SetSearchPath inside WithTransaction instead.
+// Example:
+//
+//	e.Use(echo.WrapMiddleware(tenant.Middleware(tenantConfig)))
+//
+//	var orders []Order
+//	err := db.WithContext(c.Request().Context()).Scopes(orm.TenantScope).Find(&orders).Error
+func TenantScope(db *gorm.DB) *gorm.DB {
+	t, ok := tenant.FromContext(db.Statement.Context)
+	if !ok {
+		db.AddError(tenant.ErrNoTenant)
+		return db
+	}
+	return db.Where(clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"},
+		Value:  t.ID,
+	})
+}
@@ -0,0 +1,52 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/database"
+	"gorm.io/gorm"
+)
+
+const timeoutCancelKey = "timeout:cancel"
+
+// SetQueryTimeout bounds every GORM statement by d, so runaway queries are cancelled instead of
+// holding a connection. Contexts with an earlier deadline keep it, so per-request sessions
+// (db.WithContext(c.Request().Context())) are also cancelled when the client goes away.
+// Rows() results are left unbounded because they are read after the statement returns.
+// ConnectGORM and Init apply database.QueryTimeout() (DB_QUERY_TIMEOUT) automatically.
+// Example:
+//
+//	db, _ := orm.ConnectGORM(dsn)
+//	orm.SetQueryTimeout(db, 3*time.Second)
+//	db.WithContext(c.Request().Context()).Find(&books) // cancelled after 3s or on disconnect
+func SetQueryTimeout(db *gorm.DB, d time.Duration) error {
+	before := func(op string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			if op == "row" {
+				return
+			}
+			ctx := tx.Statement.Context
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			ctx, cancel := database.WithTimeout(ctx, d)
+			tx.Statement.Context = ctx
+			tx.InstanceSet(timeoutCancelKey, cancel)
+		}
+	}
+	after := func(string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			if v, ok := tx.InstanceGet(timeoutCancelKey); ok {
+				if cancel, ok := v.(context.CancelFunc); ok {
+					cancel()
+				}
+			}
+		}
+	}
+	if err := registerAround(db, "timeout", before, after); err != nil {
+		return fmt.Errorf("failed to register timeout callbacks: %w", err)
+	}
+	return nil
+}
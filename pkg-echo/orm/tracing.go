@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yoockh/go-api-utils/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracingSpanKey = "tracing:span"
+
+// RegisterTracing emits an OpenTelemetry span for every GORM statement
+// Spans include the sanitized SQL, table and rows affected, and are children of the request
+// span when queries run with db.WithContext(c.Request().Context()).
+// Example:
+//
+//	db, _ := orm.ConnectGORM(dsn)
+//	orm.RegisterTracing(db, "main")
+//	db.WithContext(ctx).Find(&users)
+func RegisterTracing(db *gorm.DB, name string) error {
+	before := func(op string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			// The statement is not built yet; name and query text are set in after
+			_, span := tracing.StartQuerySpan(tx.Statement.Context, name, op)
+			tx.InstanceSet(tracingSpanKey, span)
+		}
+	}
+	after := func(op string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			v, ok := tx.InstanceGet(tracingSpanKey)
+			if !ok {
+				return
+			}
+			span, ok := v.(trace.Span)
+			if !ok {
+				return
+			}
+			defer span.End()
+
+			stmt := tracing.SanitizeSQL(tx.Statement.SQL.String())
+			span.SetName(op + " " + tx.Statement.Table)
+			span.SetAttributes(
+				attribute.String("db.query.text", stmt),
+				attribute.String("db.collection.name", tx.Statement.Table),
+				attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+			)
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				tracing.RecordError(span, tx.Error)
+			}
+		}
+	}
+	if err := registerAround(db, "tracing", before, after); err != nil {
+		return fmt.Errorf("failed to register tracing callbacks: %w", err)
+	}
+	return nil
+}
@@ -1,14 +1,32 @@
 package orm
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yoockh/go-api-utils/pkg/database"
+	"gorm.io/gorm"
+)
 
 // WithTransaction runs fn inside a database transaction using gorm.DB.Transaction.
-// It commits on nil error, otherwise rolls back.
+// It commits on nil error, otherwise rolls back. Serialization failures and deadlocks run
+// fn again in a new transaction (3 attempts by default, see database.TxConfig.Retry), so fn
+// must not have side effects outside the database.
 // Example:
 //
 //	err := orm.WithTransaction(db, func(tx *gorm.DB) error { ...; return nil })
-func WithTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
-	return db.Transaction(func(tx *gorm.DB) error {
-		return fn(tx)
+//	err := orm.WithTransaction(db.WithContext(ctx), transfer, database.Serializable(), database.TxAttempts(5))
+func WithTransaction(db *gorm.DB, fn func(tx *gorm.DB) error, opts ...database.TxOption) error {
+	cfg := database.NewTxConfig(opts...)
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var txOpts []*sql.TxOptions
+	if o := cfg.SQLOptions(); o != nil {
+		txOpts = append(txOpts, o)
+	}
+	return cfg.Retry(ctx, func(ctx context.Context) error {
+		return db.WithContext(ctx).Transaction(fn, txOpts...)
 	})
 }
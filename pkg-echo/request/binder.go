@@ -0,0 +1,83 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg-echo/response"
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	"github.com/yoockh/go-api-utils/pkg/sanitize"
+)
+
+// Binder is an echo.Binder that sanitizes and validates after binding
+// c.Bind fills the struct from path params, query and body (echo.DefaultBinder), applies
+// `sanitize` tags and checks `validate` tags. Invalid input returns an *echo.HTTPError:
+// 400 for a malformed body, 422 with the envelope and a field -> messages map for validation errors.
+// Example:
+//
+//	e := echo.New()
+//	request.Register(e)
+//
+//	func createBook(c echo.Context) error {
+//		var req CreateBookRequest
+//		if err := c.Bind(&req); err != nil {
+//			return err // 400 / 422 envelope
+//		}
+//		return response.Created(c, "book created", req)
+//	}
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind implements echo.Binder
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	if err := b.DefaultBinder.Bind(i, c); err != nil {
+		// Keep the parser detail for logs only; echo's default handler would render a nested HTTPError
+		var he *echo.HTTPError
+		if errors.As(err, &he) && he.Internal != nil {
+			err = he.Internal
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body").SetInternal(err)
+	}
+	sanitize.Struct(i)
+	return validationError(validator.StructAll(i))
+}
+
+// Validator is an echo.Validator running the `validate` struct tags, for c.Validate
+// Errors have the same shape as Binder's.
+type Validator struct{}
+
+// Validate implements echo.Validator
+func (Validator) Validate(i interface{}) error {
+	return validationError(validator.StructAll(i))
+}
+
+// Register installs Binder and Validator on e
+func Register(e *echo.Echo) {
+	e.Binder = &Binder{}
+	e.Validator = Validator{}
+}
+
+// validationError converts validator errors into a 422 *echo.HTTPError whose message is the
+// response envelope, so both echo's default error handler and response.ErrorHandler render it
+func validationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	fields := map[string][]string{}
+	var verrs validator.ValidationErrors
+	var ferr *validator.FieldError
+	switch {
+	case errors.As(err, &verrs):
+		fields = verrs.Fields()
+	case errors.As(err, &ferr):
+		fields[ferr.Field] = []string{ferr.Message}
+	}
+	return echo.NewHTTPError(http.StatusUnprocessableEntity, response.Response{
+		Success: false,
+		Error:   "validation failed",
+		Code:    "validation_failed",
+		Errors:  fields,
+	}).SetInternal(err)
+}
@@ -0,0 +1,38 @@
+package request
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+	stdrequest "github.com/yoockh/go-api-utils/pkg/request"
+	"gorm.io/gorm"
+)
+
+// ContextWithTimeout derives a context from the request that expires after the named timeout
+// (see pkg/request.SetTimeouts). It is also cancelled when the client disconnects. Always call cancel.
+// Example:
+//
+//	ctx, cancel := request.ContextWithTimeout(c, "payments")
+//	defer cancel()
+//	resp, err := paymentsClient.Charge(ctx, req)
+func ContextWithTimeout(c echo.Context, name string) (context.Context, context.CancelFunc) {
+	return stdrequest.ContextWithTimeout(c.Request(), name)
+}
+
+// ClientGone reports whether the client has disconnected
+func ClientGone(c echo.Context) bool {
+	return stdrequest.ClientGone(c.Request())
+}
+
+// DB returns db bound to a request context with the named timeout, for GORM calls in handlers
+// Example:
+//
+//	tx, cancel := request.DB(c, db, "db")
+//	defer cancel()
+//	if err := tx.First(&book, id).Error; err != nil {
+//	    return err // context.DeadlineExceeded when the query took too long
+//	}
+func DB(c echo.Context, db *gorm.DB, name string) (*gorm.DB, context.CancelFunc) {
+	ctx, cancel := ContextWithTimeout(c, name)
+	return db.WithContext(ctx), cancel
+}
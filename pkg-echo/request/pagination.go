@@ -0,0 +1,15 @@
+package request
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg/pagination"
+)
+
+// Pagination reads page, per_page and cursor from the query string (see pagination.FromQuery)
+// Example:
+//
+//	p := request.Pagination(c) // /books?page=2&per_page=20
+//	meta, err := orm.Paginate(db, &Book{}, p, &books)
+func Pagination(c echo.Context) pagination.Params {
+	return pagination.FromQuery(c.QueryParams())
+}
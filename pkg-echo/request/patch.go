@@ -0,0 +1,52 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	stdrequest "github.com/yoockh/go-api-utils/pkg/request"
+)
+
+// Patch applies the request body to target as a JSON Patch or JSON Merge Patch (by Content-Type),
+// then sanitizes and validates it, returning the changed top-level JSON fields.
+// allowed lists the patchable JSON fields; "id" is never patchable (see request.ParsePatch).
+// Errors are *echo.HTTPError: 415 unsupported type, 400 invalid patch or forbidden field,
+// 409 failed test op, 413 body over 1 MiB, 422 validation envelope.
+// Example:
+//
+//	func patchBook(c echo.Context) error {
+//		book, err := repo.Find(ctx, id)
+//		if err != nil {
+//			return err
+//		}
+//		fields, err := request.Patch(c, &book, "title", "price")
+//		if err != nil {
+//			return err
+//		}
+//		cols, _, err := repository.PartialColumns(&book, fields)
+//		...
+//		return db.Model(&book).Select(cols).Updates(&book).Error
+//	}
+func Patch(c echo.Context, target interface{}, allowed ...string) ([]string, error) {
+	fields, err := stdrequest.ParsePatch(c.Request(), target, allowed...)
+	switch {
+	case err == nil:
+		return fields, nil
+	case errors.Is(err, stdrequest.ErrUnsupportedPatch):
+		return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType, "unsupported patch content type").SetInternal(err)
+	case errors.Is(err, stdrequest.ErrPatchTestFailed):
+		return nil, echo.NewHTTPError(http.StatusConflict, "patch test failed").SetInternal(err)
+	case errors.Is(err, stdrequest.ErrPatchTooLarge):
+		return nil, echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error()).SetInternal(err)
+	case errors.Is(err, stdrequest.ErrInvalidPatch):
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	var verrs validator.ValidationErrors
+	var ferr *validator.FieldError
+	if errors.As(err, &verrs) || errors.As(err, &ferr) {
+		return nil, validationError(err)
+	}
+	return nil, err
+}
@@ -0,0 +1,30 @@
+package request
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	stdrequest "github.com/yoockh/go-api-utils/pkg/request"
+)
+
+// CheckIfMatch compares If-Match with the resource's current ETag (see pkg/request.CheckIfMatch)
+// On failure the current ETag is set on the response and a 428 or 412 *echo.HTTPError is returned.
+// Example:
+//
+//	etag := stdrequest.ETag(book.ID, book.UpdatedAt)
+//	if err := request.CheckIfMatch(c, etag); err != nil {
+//		return err
+//	}
+func CheckIfMatch(c echo.Context, current string) error {
+	err := stdrequest.CheckIfMatch(c.Request(), current)
+	if err == nil {
+		return nil
+	}
+	c.Response().Header().Set("ETag", current)
+	if errors.Is(err, errs.ErrPreconditionRequired) {
+		return echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header is required").SetInternal(err)
+	}
+	return echo.NewHTTPError(http.StatusPreconditionFailed, "resource was modified").SetInternal(err)
+}
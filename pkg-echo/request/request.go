@@ -1,6 +1,8 @@
 package request
 
 import (
+	"errors"
+	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
@@ -8,6 +10,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/yoockh/go-api-utils/pkg-echo/response"
 	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	"github.com/yoockh/go-api-utils/pkg/sanitize"
 )
 
 // BindAndValidate binds request body and validates required fields.
@@ -37,7 +40,8 @@ func BindAndValidate(c echo.Context, v interface{}, requiredFields map[string]st
 
 // BindAndRequireFields binds JSON request body into v and validates required JSON fields
 // by their json tag names (e.g., "email", "password"). This avoids the zero-value pitfall
-// of passing a map before binding.
+// of passing a map before binding. `sanitize` tags are applied, then `validate` tags are checked;
+// when Register installed Binder, c.Bind already did both and its 422 is sent as is.
 // Example:
 //
 //	var req LoginRequest
@@ -45,59 +49,129 @@ func BindAndValidate(c echo.Context, v interface{}, requiredFields map[string]st
 //	    return nil // error response already sent
 //	}
 func BindAndRequireFields(c echo.Context, v interface{}, requiredJSON ...string) bool {
-	if err := c.Bind(v); err != nil {
-		response.BadRequest(c, "invalid request body")
+	if !bind(c, v) {
 		return false
 	}
+	validated := registered(c)
+	if !validated {
+		sanitize.Struct(v)
+	}
 
 	if ok, msg := RequireFields(v, requiredJSON...); !ok {
 		response.BadRequest(c, msg)
 		return false
 	}
+	if validated {
+		return true
+	}
+	if err := validator.Struct(v); err != nil {
+		response.BadRequest(c, err.Error())
+		return false
+	}
 	return true
 }
 
+// BindJSON binds the request body into a new T, applies `sanitize` tags and validates its `validate` struct tags.
+// On failure a response is sent and ok is false: 400 for malformed JSON, 422 listing
+// every invalid field for validation errors. With Binder registered, c.Bind does the sanitizing
+// and validation once.
+// Example:
+//
+//	type CreateBookRequest struct {
+//		Title string `json:"title" validate:"required,max=200"`
+//		Pages int    `json:"pages" validate:"required,min=1"`
+//	}
+//
+//	req, ok := request.BindJSON[CreateBookRequest](c)
+//	if !ok {
+//	    return nil // error response already sent
+//	}
+func BindJSON[T any](c echo.Context) (T, bool) {
+	var v T
+	if !bind(c, &v) {
+		return v, false
+	}
+	if registered(c) {
+		return v, true
+	}
+	sanitize.Struct(&v)
+	if err := validator.StructAll(&v); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			response.ValidationError(c, verrs.Fields())
+		} else {
+			response.BadRequest(c, err.Error())
+		}
+		return v, false
+	}
+	return v, true
+}
+
+// bind runs c.Bind and sends the error response on failure: Binder's 422 validation envelope
+// unchanged, 400 for anything else
+func bind(c echo.Context, v interface{}) bool {
+	err := c.Bind(v)
+	if err == nil {
+		return true
+	}
+	var he *echo.HTTPError
+	if errors.As(err, &he) && he.Code == http.StatusUnprocessableEntity {
+		c.JSON(he.Code, he.Message)
+		return false
+	}
+	response.BadRequest(c, "invalid request body")
+	return false
+}
+
+// registered reports whether Register installed Binder, which sanitizes and validates in c.Bind
+func registered(c echo.Context) bool {
+	_, ok := c.Echo().Binder.(*Binder)
+	return ok
+}
+
 // RequireFields validates required JSON fields on an already-bound struct.
 // It does not write any HTTP response, only returns (ok, message) so you can decide
 // how to handle the error in higher layers.
+// Blank strings, zero numbers, false, zero time.Time, nil pointers and empty slices/maps
+// count as missing. Nested fields use dotted json paths like "address.city".
+// To accept zero values, use a pointer field (only nil is missing) or tag the field
+// with `validate:"allowzero"`.
 // Example:
 //
 //	var req LoginRequest
 //	if err := c.Bind(&req); err != nil { return response.BadRequest(c, "invalid body") }
-//	if ok, msg := request.RequireFields(&req, "email", "password"); !ok {
+//	if ok, msg := request.RequireFields(&req, "email", "password", "address.city"); !ok {
 //	    return response.BadRequest(c, msg)
 //	}
 func RequireFields(v interface{}, requiredJSON ...string) (bool, string) {
-	if len(requiredJSON) == 0 {
-		return true, ""
-	}
-
-	// Collect string fields by json tag name
-	values := map[string]string{}
 	rv := reflect.Indirect(reflect.ValueOf(v))
-	if rv.IsValid() && rv.Kind() == reflect.Struct {
-		rt := rv.Type()
-		for i := 0; i < rt.NumField(); i++ {
-			sf := rt.Field(i)
-			tag := sf.Tag.Get("json")
-			if tag == "" || tag == "-" {
-				continue
-			}
-			name := strings.Split(tag, ",")[0]
-			fv := rv.Field(i)
-			// Only validate string fields. Extend as needed.
-			if fv.IsValid() && fv.Kind() == reflect.String {
-				values[name] = fv.String()
-			}
+	for _, path := range requiredJSON {
+		fv, allowZero, ok := lookupJSONPath(rv, path)
+		if !ok {
+			return false, path + " is required"
+		}
+		if validator.IsMissing(fv, allowZero) {
+			return false, path + " is required"
 		}
 	}
+	return true, ""
+}
 
-	fieldsToCheck := map[string]string{}
-	for _, key := range requiredJSON {
-		fieldsToCheck[key] = values[key]
+// lookupJSONPath walks a dotted json path ("address.city") through nested structs and pointers
+// Fields are found through the validator's cached type metadata (validator.JSONField).
+func lookupJSONPath(rv reflect.Value, path string) (fv reflect.Value, allowZero bool, ok bool) {
+	for name := range strings.SplitSeq(path, ".") {
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return rv, false, false
+			}
+			rv = rv.Elem()
+		}
+		if rv, allowZero, ok = validator.JSONField(rv, name); !ok {
+			return rv, false, false
+		}
 	}
-
-	return validator.ValidateRequired(fieldsToCheck)
+	return rv, allowZero, true
 }
 
 // ValidateEmail validates email and sends error response if invalid
@@ -0,0 +1,123 @@
+package response
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// ErrorHandler is an echo.HTTPErrorHandler that renders every error in the standard envelope
+// - *errs.Error (errs.NotFound, errs.Validation, ...) keeps its status, code, message and fields
+// - *echo.HTTPError keeps its status and message
+// - validator.ValidationErrors / *validator.FieldError become 422 with the field map
+// - errs.ErrNotFound, sql.ErrNoRows, gorm.ErrRecordNotFound become 404
+// - errs.ErrConflict, gorm.ErrDuplicatedKey and unique violations become 409
+// - context.DeadlineExceeded becomes 504; nothing is written when the client disconnected
+// - errors implementing errs.StatusCoder use their own status
+// - anything else is reported (see errs.SetReporter) and answered with a generic 500
+// Example:
+//
+//	e := echo.New()
+//	e.HTTPErrorHandler = response.ErrorHandler
+//
+//	func getBook(c echo.Context) error {
+//		book, err := repo.Find(c.Request().Context(), id)
+//		if err != nil {
+//			return err // 404 for gorm.ErrRecordNotFound, 500 + report otherwise
+//		}
+//		return response.Success(c, "book retrieved", book)
+//	}
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var (
+		he    *echo.HTTPError
+		ae    *errs.Error
+		verrs validator.ValidationErrors
+		ferr  *validator.FieldError
+	)
+	status, message := http.StatusInternalServerError, "internal server error"
+	var (
+		code   string
+		fields map[string][]string
+	)
+	switch {
+	// Checked first: they may be wrapped in an *echo.HTTPError (see request.Binder)
+	case errors.As(err, &verrs):
+		sendError(c, ValidationError(c, verrs.Fields()))
+		return
+	case errors.As(err, &ferr):
+		sendError(c, ValidationError(c, map[string][]string{ferr.Field: {ferr.Message}}))
+		return
+	case errors.As(err, &ae):
+		status, message, code, fields = ae.Status, ae.Message, ae.Code, ae.Fields
+	case errors.As(err, &he):
+		status = he.Code
+		message = http.StatusText(status)
+		if m, ok := he.Message.(string); ok && m != "" {
+			message = m
+		} else if he.Message != nil {
+			message = fmt.Sprint(he.Message)
+		}
+		if he.Internal != nil {
+			err = he.Internal
+		}
+	case errors.Is(err, context.Canceled) && c.Request().Context().Err() != nil:
+		return // the client disconnected; nobody is waiting for the response
+	case errors.Is(err, context.DeadlineExceeded):
+		status, message = http.StatusGatewayTimeout, "request timed out"
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, sql.ErrNoRows):
+		// Driver messages are not meant for clients
+		status, message = http.StatusNotFound, "resource not found"
+	case errors.Is(err, gorm.ErrDuplicatedKey), errs.IsUniqueViolation(err):
+		status, message = http.StatusConflict, "resource already exists"
+	default:
+		e := errs.From(err)
+		status, message, code = e.Status, e.Message, e.Code
+	}
+
+	if code == "" {
+		code = errs.StatusCodeName(status)
+	}
+	errs.CheckCode(code)
+	if status >= http.StatusInternalServerError {
+		errs.ReportRequest(c.Request(), err)
+	}
+	if c.Request().Method == http.MethodHead {
+		sendError(c, c.NoContent(status))
+		return
+	}
+	resp := Response{Error: message, Code: code, Errors: fields}
+	if status >= http.StatusInternalServerError {
+		resp.RequestID = RequestID(c)
+	}
+	sendError(c, c.JSON(status, resp))
+}
+
+// RequestID returns the request ID set by RequestLogger (or found in the request context or
+// X-Request-ID response header), "" if there is none
+func RequestID(c echo.Context) string {
+	if id, ok := c.Get("request_id").(string); ok && id != "" {
+		return id
+	}
+	if id := logging.RequestID(c.Request().Context()); id != "" {
+		return id
+	}
+	return c.Response().Header().Get(logging.RequestIDHeader)
+}
+
+func sendError(c echo.Context, err error) {
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
@@ -0,0 +1,34 @@
+package response
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	stdresponse "github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// File streams r for inline display (images, PDFs in browser)
+// Pass size -1 when the length is unknown.
+// Example:
+//
+//	rc, _ := store.Get(ctx, "avatars/42.png")
+//	defer rc.Close()
+//	return response.File(c, rc, "avatar.png", "image/png", -1)
+func File(c echo.Context, r io.Reader, filename, contentType string, size int64) error {
+	return streamFile(c, r, "inline", filename, contentType, size)
+}
+
+// Attachment streams r as a download ("Save as" dialog)
+// Example:
+//
+//	return response.Attachment(c, &buf, "orders.csv", "text/csv", int64(buf.Len()))
+func Attachment(c echo.Context, r io.Reader, filename, contentType string, size int64) error {
+	return streamFile(c, r, "attachment", filename, contentType, size)
+}
+
+func streamFile(c echo.Context, r io.Reader, disposition, filename, contentType string, size int64) error {
+	h := c.Response().Header()
+	stdresponse.SetFileHeaders(h, disposition, filename, contentType, size)
+	return c.Stream(http.StatusOK, h.Get(echo.HeaderContentType), r)
+}
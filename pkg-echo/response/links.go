@@ -0,0 +1,58 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg/pagination"
+	stdresponse "github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// RouteLink builds a link to a named Echo route (see e.Reverse)
+// Example:
+//
+//	e.GET("/products/:id", getProduct).Name = "product"
+//	link := response.RouteLink(c, "product", p.ID) // {"href": "/products/42"}
+func RouteLink(c echo.Context, name string, params ...interface{}) stdresponse.Link {
+	return stdresponse.Link{Href: c.Echo().Reverse(name, params...)}
+}
+
+// PageLinks builds self, first, last, prev and next links for the current request
+func PageLinks(c echo.Context, page, perPage int, total int64) stdresponse.Links {
+	return stdresponse.PageLinks(c.Request(), page, perPage, total)
+}
+
+// SetPageHeaders sets the Link and X-Total-Count headers for a page of a collection
+func SetPageHeaders(c echo.Context, meta pagination.Meta) {
+	stdresponse.SetPageHeaders(c.Response(), c.Request(), meta)
+}
+
+// PaginatedWithHeaders is Paginated that also sets the Link and X-Total-Count headers
+// Example:
+//
+//	meta, err := orm.Paginate(db, &Book{}, request.Pagination(c), &books)
+//	if err != nil {
+//		return err
+//	}
+//	return response.PaginatedWithHeaders(c, "books retrieved", books, meta)
+func PaginatedWithHeaders(c echo.Context, message string, data interface{}, meta pagination.Meta) error {
+	SetPageHeaders(c, meta)
+	return Paginated(c, message, data, meta)
+}
+
+// SuccessWithLinks sends 200 OK with {success, message, data, meta, _links}
+// Example:
+//
+//	items := stdresponse.WithLinksEach(books, func(b Book) stdresponse.Links {
+//		return stdresponse.Links{"self": response.RouteLink(c, "book", b.ID)}
+//	})
+//	return response.SuccessWithLinks(c, "books retrieved", items, meta, response.PageLinks(c, page, perPage, total))
+func SuccessWithLinks(c echo.Context, message string, data, meta interface{}, links stdresponse.Links) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": message,
+		"data":    data,
+		"meta":    meta,
+		"_links":  links,
+	})
+}
@@ -1,9 +1,11 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg/errs"
 )
 
 // Response represents standard API response structure
@@ -12,6 +14,12 @@ type Response struct {
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Code is the machine-readable error code of errs.Error values (see ErrorHandler)
+	Code string `json:"code,omitempty"`
+	// RequestID is set on 5xx errors so clients can quote it to support
+	RequestID string `json:"request_id,omitempty"`
+	// Errors maps field names to validation messages (see ValidationError)
+	Errors map[string][]string `json:"errors,omitempty"`
 }
 
 // Success sends a standardized 200 OK JSON response with message and data.
@@ -36,11 +44,12 @@ func SuccessData(c echo.Context, data interface{}) error {
 }
 
 // Paginated sends a standardized 200 OK response with pagination metadata.
-// "meta" can be any struct/map with fields like page, per_page, total, total_pages.
+// "meta" is usually pagination.Meta, but any struct/map with page, per_page, total, total_pages works.
 // Example:
 //
-//	meta := map[string]interface{}{"page": 1, "per_page": 10, "total": 42, "total_pages": 5}
-//	return response.Paginated(c, "books retrieved", books, meta)
+//	p := request.Pagination(c)
+//	total, err := orm.CountAndPaginate(db, &Book{}, p.Page, p.PerPage, &books)
+//	return response.Paginated(c, "books retrieved", books, p.Meta(total))
 func Paginated(c echo.Context, message string, data interface{}, meta interface{}) error {
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"success": true,
@@ -92,7 +101,29 @@ func NotFound(c echo.Context, message string) error {
 	return Error(c, http.StatusNotFound, message)
 }
 
-// InternalServerError sends 500
+// PreconditionFailed sends 412, e.g. when If-Match does not match the current ETag
+func PreconditionFailed(c echo.Context, message string) error {
+	return Error(c, http.StatusPreconditionFailed, message)
+}
+
+// InternalServerError sends 500 and reports message to the error reporter with the request context
 func InternalServerError(c echo.Context, message string) error {
+	errs.ReportRequest(c.Request(), errors.New(message))
 	return Error(c, http.StatusInternalServerError, message)
 }
+
+// ValidationError sends 422 with a field -> messages map.
+// Example:
+//
+//	var verrs validator.ValidationErrors
+//	if errors.As(validator.StructAll(&req), &verrs) {
+//		return response.ValidationError(c, verrs.Fields())
+//	}
+func ValidationError(c echo.Context, fields map[string][]string) error {
+	return c.JSON(http.StatusUnprocessableEntity, Response{
+		Success: false,
+		Error:   "validation failed",
+		Code:    "validation_failed",
+		Errors:  fields,
+	})
+}
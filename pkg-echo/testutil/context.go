@@ -0,0 +1,192 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+	"github.com/yoockh/go-api-utils/pkg-echo/request"
+	"github.com/yoockh/go-api-utils/pkg-echo/response"
+)
+
+// Option customizes the context built by NewContext
+type Option func(*builder)
+
+type builder struct {
+	echo        *echo.Echo
+	route       string
+	paramNames  []string
+	paramValues []string
+	query       url.Values
+	header      http.Header
+	values      map[string]interface{}
+	ctx         context.Context
+}
+
+// WithEcho uses e instead of a new instance, e.g. to get its binder, validator and error handler
+func WithEcho(e *echo.Echo) Option {
+	return func(b *builder) { b.echo = e }
+}
+
+// WithRoute sets the route pattern returned by c.Path(), e.g. "/books/:id"
+func WithRoute(pattern string) Option {
+	return func(b *builder) { b.route = pattern }
+}
+
+// WithParam sets a path parameter read by c.Param
+func WithParam(name, value string) Option {
+	return func(b *builder) {
+		b.paramNames = append(b.paramNames, name)
+		b.paramValues = append(b.paramValues, value)
+	}
+}
+
+// WithQuery adds a query parameter
+func WithQuery(key, value string) Option {
+	return func(b *builder) { b.query.Add(key, value) }
+}
+
+// WithHeader sets a request header
+func WithHeader(key, value string) Option {
+	return func(b *builder) { b.header.Set(key, value) }
+}
+
+// WithValue stores a value in the context with c.Set
+func WithValue(key string, value interface{}) Option {
+	return func(b *builder) { b.values[key] = value }
+}
+
+// WithClaims sets the keys middleware.JWTMiddleware stores for a basic token
+// ("claims", "user_id", "email", "role"), so handlers see an authenticated user.
+func WithClaims(userID int, email, role string) Option {
+	return func(b *builder) {
+		b.values["claims"] = &auth.Claims{UserID: userID, Email: email, Role: role}
+		b.values["user_id"] = userID
+		b.values["email"] = email
+		if role != "" {
+			b.values["role"] = role
+		}
+	}
+}
+
+// WithTokenData sets the keys middleware.JWTMiddleware stores for a custom token
+// ("token_data" and its user_id, email and role entries).
+func WithTokenData(data map[string]interface{}) Option {
+	return func(b *builder) {
+		b.values["token_data"] = data
+		for _, key := range []string{"user_id", "email", "role"} {
+			if v, ok := data[key]; ok {
+				b.values[key] = v
+			}
+		}
+	}
+}
+
+// WithContext sets the request's context.Context
+func WithContext(ctx context.Context) Option {
+	return func(b *builder) { b.ctx = ctx }
+}
+
+// NewContext builds an echo.Context for unit testing a handler or middleware
+// body may be nil, a string, []byte, an io.Reader, or any other value, which is sent as JSON.
+// The query string in path is kept; without WithEcho the instance has request.Register's
+// binder and validator and response.ErrorHandler installed.
+// Example:
+//
+//	c, rec := testutil.NewContext(http.MethodPut, "/books/1", UpdateBookRequest{Title: "Go"},
+//		testutil.WithRoute("/books/:id"),
+//		testutil.WithParam("id", "1"),
+//		testutil.WithClaims(7, "admin@example.com", "admin"),
+//	)
+//	if err := handler.UpdateBook(c); err != nil {
+//		t.Fatal(err)
+//	}
+//	// rec.Code, rec.Body
+func NewContext(method, path string, body interface{}, opts ...Option) (echo.Context, *httptest.ResponseRecorder) {
+	b := &builder{query: url.Values{}, header: http.Header{}, values: map[string]interface{}{}}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.echo == nil {
+		b.echo = echo.New()
+		request.Register(b.echo)
+		b.echo.HTTPErrorHandler = response.ErrorHandler
+	}
+
+	reader, contentType := bodyReader(body)
+	if len(b.query) > 0 {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		path += sep + b.query.Encode()
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if contentType != "" {
+		req.Header.Set(echo.HeaderContentType, contentType)
+	}
+	for key, values := range b.header {
+		req.Header[key] = values
+	}
+	if b.ctx != nil {
+		req = req.WithContext(b.ctx)
+	}
+
+	rec := httptest.NewRecorder()
+	c := b.echo.NewContext(req, rec)
+	if b.route != "" {
+		c.SetPath(b.route)
+	}
+	c.SetParamNames(b.paramNames...)
+	c.SetParamValues(b.paramValues...)
+	for key, value := range b.values {
+		c.Set(key, value)
+	}
+	return c, rec
+}
+
+// Handle runs h behind the middleware (outermost first) and passes a returned error to the
+// instance's HTTPErrorHandler, like echo does for routed requests, so rec holds the final response.
+// The error is returned as well.
+// Example:
+//
+//	c, rec := testutil.NewContext(http.MethodGet, "/admin", nil)
+//	testutil.Handle(c, handler, middleware.RequireRoles("admin"))
+//	// rec.Code == http.StatusForbidden
+func Handle(c echo.Context, h echo.HandlerFunc, middleware ...echo.MiddlewareFunc) error {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	err := h(c)
+	if err != nil {
+		c.Echo().HTTPErrorHandler(err, c)
+	}
+	return err
+}
+
+func bodyReader(body interface{}) (io.Reader, string) {
+	switch v := body.(type) {
+	case nil:
+		return nil, ""
+	case string:
+		return strings.NewReader(v), echo.MIMEApplicationJSON
+	case []byte:
+		return bytes.NewReader(v), echo.MIMEApplicationJSON
+	case io.Reader:
+		return v, ""
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			panic(fmt.Sprintf("testutil: failed to encode body: %v", err))
+		}
+		return bytes.NewReader(data), echo.MIMEApplicationJSON
+	}
+}
@@ -0,0 +1,169 @@
+package validator
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CrossRuleFunc is a rule that also sees the struct containing the field
+type CrossRuleFunc func(v, parent reflect.Value, param string) bool
+
+type crossRule struct {
+	fn      CrossRuleFunc
+	message string
+}
+
+// crossRules compare a field with a sibling field named in the parameter (Go or json name)
+var crossRules = map[string]crossRule{
+	"eqfield":  {compareField(func(c int) bool { return c == 0 }), "{field} must match {param}"},
+	"nefield":  {compareField(func(c int) bool { return c != 0 }), "{field} must differ from {param}"},
+	"gtfield":  {compareField(func(c int) bool { return c > 0 }), "{field} must be greater than {param}"},
+	"gtefield": {compareField(func(c int) bool { return c >= 0 }), "{field} must be greater than or equal to {param}"},
+	"ltfield":  {compareField(func(c int) bool { return c < 0 }), "{field} must be less than {param}"},
+	"ltefield": {compareField(func(c int) bool { return c <= 0 }), "{field} must be less than or equal to {param}"},
+}
+
+// RegisterCrossRule adds or replaces a rule that compares a field with its siblings
+// Example:
+//
+//	validator.RegisterCrossRule("after_start", func(v, parent reflect.Value, param string) bool {
+//		return v.Interface().(time.Time).After(parent.FieldByName("Start").Interface().(time.Time))
+//	}, "{field} must be after the start date")
+func RegisterCrossRule(name string, fn CrossRuleFunc, message string) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	crossRules[name] = crossRule{fn: fn, message: message}
+}
+
+// conditionalRequired reports whether a required_* rule makes the field required:
+//
+//	required_if=Type card          required when Type equals "card" (several values: any match)
+//	required_unless=Type cash      required unless Type equals "cash"
+//	required_with=Phone            required when Phone is set
+//	required_without=Email         required when Email is missing
+func conditionalRequired(ruleName, param string, parent reflect.Value) (bool, bool) {
+	switch ruleName {
+	case "required_if", "required_unless":
+		parts := strings.Fields(param)
+		if len(parts) < 2 {
+			return false, true
+		}
+		other, ok := siblingField(parent, parts[0])
+		if !ok {
+			return false, true
+		}
+		match := OneOf(valueString(other), parts[1:]...)
+		if ruleName == "required_unless" {
+			match = !match
+		}
+		return match, true
+	case "required_with", "required_without":
+		other, ok := siblingField(parent, param)
+		if !ok {
+			return false, true
+		}
+		set := !isEmptyValue(other)
+		if ruleName == "required_without" {
+			set = !set
+		}
+		return set, true
+	}
+	return false, false
+}
+
+// siblingField finds a field of parent by Go name or json name
+func siblingField(parent reflect.Value, name string) (reflect.Value, bool) {
+	if !parent.IsValid() || parent.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	i, ok := metaOf(parent.Type()).byName[name]
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return parent.Field(i), true
+}
+
+// siblingName returns the json name of the sibling field, for error messages
+func siblingName(parent reflect.Value, name string) string {
+	if parent.IsValid() && parent.Kind() == reflect.Struct {
+		if sf, ok := parent.Type().FieldByName(name); ok {
+			return jsonName(sf)
+		}
+	}
+	return name
+}
+
+// compareField builds a rule comparing the field with the sibling named by param
+func compareField(ok func(cmp int) bool) CrossRuleFunc {
+	return func(v, parent reflect.Value, param string) bool {
+		other, found := siblingField(parent, param)
+		if !found {
+			return false
+		}
+		c, comparable := compareValues(deref(v), deref(other))
+		return comparable && ok(c)
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// compareValues orders numbers, strings and time.Time values; bools only support equality
+func compareValues(a, b reflect.Value) (int, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+	if x, ok := number(a); ok {
+		if y, ok := number(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+	switch {
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), true
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		if a.Bool() == b.Bool() {
+			return 0, true
+		}
+		return 1, true
+	}
+	return 0, false
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// valueString renders strings, numbers and bools for required_if comparisons
+func valueString(v reflect.Value) string {
+	v = deref(v)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	}
+	if n, ok := number(v); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return ""
+}
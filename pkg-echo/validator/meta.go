@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// typeMeta is what validation needs to know about a struct type, built once per type
+// so requests don't re-read tags and re-split rule lists
+type typeMeta struct {
+	fields []fieldMeta         // exported fields not tagged validate:"-", in order
+	byJSON map[string]jsonMeta // json name (Go name when untagged) of exported fields; json:"-" excluded
+	byName map[string]int      // Go name and jsonName of every field -> field index, for sibling lookups
+}
+
+// fieldMeta describes one field to validate
+type fieldMeta struct {
+	index     int
+	name      string // jsonName, used in error paths
+	tag       string // validate tag
+	rules     []ruleSpec
+	required  bool // plain "required"
+	allowZero bool
+	nested    bool // the field's type may hold structs to walk into
+}
+
+type ruleSpec struct {
+	name, param string
+	conditional bool // required_if, required_unless, required_with, required_without
+}
+
+type jsonMeta struct {
+	index     int
+	allowZero bool
+}
+
+var metas sync.Map // reflect.Type -> *typeMeta
+
+// metaOf returns the cached metadata of struct type t
+func metaOf(t reflect.Type) *typeMeta {
+	if m, ok := metas.Load(t); ok {
+		return m.(*typeMeta)
+	}
+	m := &typeMeta{byJSON: map[string]jsonMeta{}, byName: map[string]int{}}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		for _, name := range []string{sf.Name, jsonName(sf)} {
+			if _, ok := m.byName[name]; !ok {
+				m.byName[name] = i
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("validate")
+		allowZero := HasRule(tag, "allowzero")
+		if jn := strings.Split(sf.Tag.Get("json"), ",")[0]; jn != "-" {
+			if jn == "" {
+				jn = sf.Name
+			}
+			if _, ok := m.byJSON[jn]; !ok {
+				m.byJSON[jn] = jsonMeta{index: i, allowZero: allowZero}
+			}
+		}
+		if tag == "-" {
+			continue
+		}
+
+		f := fieldMeta{
+			index:     i,
+			name:      jsonName(sf),
+			tag:       tag,
+			required:  HasRule(tag, "required"),
+			allowZero: allowZero,
+			nested:    mayHoldStructs(sf.Type),
+		}
+		if tag != "" {
+			for _, s := range strings.Split(tag, ",") {
+				name, param, _ := strings.Cut(strings.TrimSpace(s), "=")
+				if name != "" {
+					f.rules = append(f.rules, ruleSpec{name: name, param: param, conditional: isConditional(name)})
+				}
+			}
+		}
+		m.fields = append(m.fields, f)
+	}
+	actual, _ := metas.LoadOrStore(t, m)
+	return actual.(*typeMeta)
+}
+
+func isConditional(rule string) bool {
+	switch rule {
+	case "required_if", "required_unless", "required_with", "required_without":
+		return true
+	}
+	return false
+}
+
+// JSONField returns the field of struct value rv named name in JSON (the Go name for
+// untagged fields; json:"-" fields are never found) and whether it is tagged
+// validate:"allowzero"; it uses the cached type metadata, so it is cheap per request
+func JSONField(rv reflect.Value, name string) (fv reflect.Value, allowZero bool, ok bool) {
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return reflect.Value{}, false, false
+	}
+	f, ok := metaOf(rv.Type()).byJSON[name]
+	if !ok {
+		return reflect.Value{}, false, false
+	}
+	return rv.Field(f.index), f.allowZero, true
+}
@@ -0,0 +1,433 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldError describes a failed validation rule on a single field
+type FieldError struct {
+	Field   string `json:"field"` // json name of the field
+	Rule    string `json:"rule"`  // e.g. "required", "min"
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// RuleFunc reports whether v satisfies the rule with the given parameter (text after "=")
+type RuleFunc func(v reflect.Value, param string) bool
+
+type rule struct {
+	fn      RuleFunc
+	message string // e.g. "{field} must be at least {param}"
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]rule{
+		"required": {ruleRequired, "{field} is required"},
+		"email":    {ruleEmail, "{field} must be a valid email address"},
+		"min":      {ruleMin, "{field} must be at least {param}"},
+		"max":      {ruleMax, "{field} must be at most {param}"},
+		"len":      {ruleLen, "{field} must be exactly {param}"},
+		"url":      {ruleURL, "{field} must be a valid URL"},
+		"uuid":     {ruleUUID, "{field} must be a valid UUID"},
+		"date":     {ruleDate, "{field} must be a valid date"},
+		"oneof":    {ruleOneOf, "{field} must be one of: {param}"},
+		// allowzero marks zero values as present for request.RequireFields and "required"
+		"allowzero": {func(reflect.Value, string) bool { return true }, ""},
+	}
+)
+
+// RegisterRule adds or replaces a validation rule usable in validate tags
+// message may use the {field} and {param} placeholders.
+// Example:
+//
+//	validator.RegisterRule("slug", func(v reflect.Value, _ string) bool {
+//		return slugRegex.MatchString(v.String())
+//	}, "{field} must be a valid slug")
+func RegisterRule(name string, fn RuleFunc, message string) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[name] = rule{fn: fn, message: message}
+}
+
+// Struct validates v (a struct or pointer to struct) using `validate` tags
+// Rules are comma-separated; parameters follow "=". Empty optional fields skip the other rules.
+// Returns a *FieldError for the first failing field, or nil. Use StructAll to collect every failure.
+// Example:
+//
+//	type RegisterRequest struct {
+//		Email    string `json:"email" validate:"required,email"`
+//		Password string `json:"password" validate:"required,min=8,max=64"`
+//		Age      int    `json:"age" validate:"min=18"`
+//	}
+//	if err := validator.Struct(&req); err != nil {
+//		return response.BadRequest(c, err.Error()) // "password must be at least 8 characters"
+//	}
+func Struct(v interface{}) error {
+	if errs := validateStruct(v, false); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// StructAll validates v like Struct but reports every failing rule of every field
+// Returns ValidationErrors, or nil when v is valid.
+// Example:
+//
+//	if err := validator.StructAll(&req); err != nil {
+//		var verrs validator.ValidationErrors
+//		errors.As(err, &verrs)
+//		return response.ValidationError(c, verrs.Fields())
+//	}
+func StructAll(v interface{}) error {
+	if errs := validateStruct(v, true); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidationErrors is the list of failures returned by StructAll
+type ValidationErrors []*FieldError
+
+// Error joins all messages
+func (v ValidationErrors) Error() string {
+	msgs := make([]string, len(v))
+	for i, e := range v {
+		msgs[i] = e.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Fields groups messages by field name, for response.ValidationError
+func (v ValidationErrors) Fields() map[string][]string {
+	fields := make(map[string][]string, len(v))
+	for _, e := range v {
+		fields[e.Field] = append(fields[e.Field], e.Message)
+	}
+	return fields
+}
+
+func validateStruct(v interface{}, all bool) ValidationErrors {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return nil // maps and other non-struct values carry no tags
+	}
+	return walkStruct(rv, "", all, 0)
+}
+
+// maxDepth stops runaway recursion on self-referencing types
+const maxDepth = 32
+
+// walkStruct validates the fields of rv and recurses into nested structs, slices and maps.
+// prefix is the path of rv in the request, e.g. "items[2]." for the third item.
+func walkStruct(rv reflect.Value, prefix string, all bool, depth int) ValidationErrors {
+	var errs ValidationErrors
+	meta := metaOf(rv.Type())
+	for i := range meta.fields {
+		f := &meta.fields[i]
+		path := prefix + f.name
+		fv := rv.Field(f.index)
+		if f.tag != "" {
+			fieldErrs := validateField(path, fv, rv, f, all)
+			errs = append(errs, fieldErrs...)
+			if !all && len(errs) > 0 {
+				return errs
+			}
+			if len(fieldErrs) > 0 {
+				continue // don't report nested errors under an already invalid field
+			}
+		}
+		if f.nested && depth < maxDepth {
+			errs = append(errs, walkNested(fv, path, all, depth+1)...)
+			if !all && len(errs) > 0 {
+				return errs
+			}
+		}
+	}
+	return errs
+}
+
+// walkNested descends into struct values, and slices/arrays/maps whose elements are structs
+func walkNested(v reflect.Value, path string, all bool, depth int) ValidationErrors {
+	v = deref(v)
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == timeType {
+			return nil
+		}
+		return walkStruct(v, path+".", all, depth)
+	case reflect.Slice, reflect.Array:
+		if !mayHoldStructs(v.Type().Elem()) {
+			return nil
+		}
+		var errs ValidationErrors
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, walkNested(v.Index(i), path+"["+strconv.Itoa(i)+"]", all, depth)...)
+			if !all && len(errs) > 0 {
+				return errs
+			}
+		}
+		return errs
+	case reflect.Map:
+		if !mayHoldStructs(v.Type().Elem()) {
+			return nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		var errs ValidationErrors
+		for _, k := range keys {
+			errs = append(errs, walkNested(v.MapIndex(k), path+"["+fmt.Sprint(k)+"]", all, depth)...)
+			if !all && len(errs) > 0 {
+				return errs
+			}
+		}
+		return errs
+	}
+	return nil
+}
+
+// mayHoldStructs reports whether values of t can contain structs to validate
+func mayHoldStructs(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return mayHoldStructs(t.Elem())
+	case reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// validateField applies the rules of f to fv; stops at the first failure unless all is set
+// parent is the struct holding fv, used by cross-field and conditional rules.
+func validateField(name string, fv, parent reflect.Value, f *fieldMeta, all bool) []*FieldError {
+	required := f.required
+	// required_if / required_unless / required_with / required_without
+	for _, r := range f.rules {
+		if r.conditional {
+			if cond, _ := conditionalRequired(r.name, r.param, parent); cond {
+				required = true
+			}
+		}
+	}
+	// allowzero: zero values are present, only nil pointers count as missing
+	missing := IsMissing(fv, f.allowZero)
+	// Optional fields are only checked when set
+	if !required && missing {
+		return nil
+	}
+
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	if required && missing {
+		return []*FieldError{{Field: name, Rule: "required", Message: message(rules["required"].message, name, "required", "", fv)}}
+	}
+	var errs []*FieldError
+	for _, r := range f.rules {
+		if r.name == "required" || r.conditional {
+			continue
+		}
+		var passed bool
+		var msg string
+		cr, isCross := crossRules[r.name]
+		if isCross {
+			passed, msg = cr.fn(fv, parent, r.param), cr.message
+		} else if rule, ok := rules[r.name]; ok {
+			passed, msg = rule.fn(fv, r.param), rule.message
+		} else {
+			return append(errs, &FieldError{Field: name, Rule: r.name, Message: fmt.Sprintf("unknown validation rule %q on %s", r.name, name)})
+		}
+		if passed {
+			continue
+		}
+		shown := r.param
+		if isCross {
+			shown = siblingName(parent, r.param)
+		}
+		errs = append(errs, &FieldError{Field: name, Rule: r.name, Param: r.param, Message: message(msg, name, r.name, shown, fv)})
+		if !all {
+			return errs
+		}
+	}
+	return errs
+}
+
+// message formats a rule message; length rules on strings and slices mention the unit
+func message(pattern, field, ruleName, param string, fv reflect.Value) string {
+	msg := strings.NewReplacer("{field}", field, "{param}", param).Replace(pattern)
+	if ruleName == "min" || ruleName == "max" || ruleName == "len" {
+		switch deref(fv).Kind() {
+		case reflect.String:
+			msg += " characters"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			msg += " items"
+		}
+	}
+	return msg
+}
+
+// jsonName returns the json tag name of a field, falling back to the Go name
+func jsonName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// HasRule reports whether a validate tag contains the named rule
+func HasRule(tag, name string) bool {
+	for _, s := range strings.Split(tag, ",") {
+		if r, _, _ := strings.Cut(strings.TrimSpace(s), "="); r == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// IsMissing reports whether v counts as missing for "required": blank strings,
+// zero numbers, false, zero time.Time, nil pointers and empty slices/maps.
+// With allowZero only nil pointers are missing.
+func IsMissing(v reflect.Value, allowZero bool) bool {
+	if allowZero {
+		return isNil(v)
+	}
+	return isEmptyValue(v)
+}
+
+// isEmptyValue treats blank strings, zero values and nil pointers as empty
+// A non-nil pointer is never empty, so *int / *bool fields can carry explicit zeros.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+func ruleRequired(v reflect.Value, _ string) bool {
+	return !isEmptyValue(v)
+}
+
+func ruleEmail(v reflect.Value, _ string) bool {
+	v = deref(v)
+	return v.Kind() == reflect.String && IsValidEmail(v.String())
+}
+
+// size returns the value compared by min/max/len: rune count for strings,
+// length for collections, the number itself for numerics
+func size(v reflect.Value) (float64, bool) {
+	v = deref(v)
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func compareSize(v reflect.Value, param string, ok func(n, limit float64) bool) bool {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return false
+	}
+	n, valid := size(v)
+	return valid && ok(n, limit)
+}
+
+func ruleMin(v reflect.Value, param string) bool {
+	return compareSize(v, param, func(n, limit float64) bool { return n >= limit })
+}
+
+func ruleMax(v reflect.Value, param string) bool {
+	return compareSize(v, param, func(n, limit float64) bool { return n <= limit })
+}
+
+func ruleLen(v reflect.Value, param string) bool {
+	return compareSize(v, param, func(n, limit float64) bool { return n == limit })
+}
+
+func stringRule(check func(s, param string) bool) RuleFunc {
+	return func(v reflect.Value, param string) bool {
+		v = deref(v)
+		return v.Kind() == reflect.String && check(v.String(), param)
+	}
+}
+
+var (
+	ruleURL  = stringRule(func(s, _ string) bool { return IsValidURL(s) })
+	ruleUUID = stringRule(func(s, _ string) bool { return IsValidUUID(s) })
+	// date defaults to YYYY-MM-DD; date=2006-01-02T15:04:05Z07:00 for other layouts
+	ruleDate = stringRule(func(s, layout string) bool {
+		if layout == "" {
+			layout = time.DateOnly
+		}
+		return IsValidDate(s, layout)
+	})
+)
+
+// ruleOneOf checks strings and numbers against space-separated values: oneof=draft published
+func ruleOneOf(v reflect.Value, param string) bool {
+	v = deref(v)
+	var s string
+	switch v.Kind() {
+	case reflect.String:
+		s = v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = strconv.FormatUint(v.Uint(), 10)
+	default:
+		return false
+	}
+	return OneOf(s, strings.Fields(param)...)
+}
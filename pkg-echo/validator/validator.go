@@ -1,11 +1,17 @@
 package validator
 
 import (
+	"cmp"
+	"net/url"
 	"regexp"
 	"strings"
+	"time"
 )
 
-var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+var (
+	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	uuidRegex  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
 
 // IsValidEmail checks if email format is valid
 func IsValidEmail(email string) bool {
@@ -24,6 +30,7 @@ func MinLength(s string, min int) bool {
 
 // ValidateRequired checks if all provided fields (key -> value) are non-empty after trimming spaces.
 // Returns (true, "") if all valid, otherwise (false, "<field> is required") for the first missing field.
+// For structs, prefer `validate` tags checked by Struct.
 // Example:
 //
 //	ok, msg := validator.ValidateRequired(map[string]string{"email": req.Email, "password": req.Password})
@@ -35,3 +42,50 @@ func ValidateRequired(fields map[string]string) (bool, string) {
 	}
 	return true, ""
 }
+
+// IsValidURL checks for an absolute http(s) URL with a host
+// Example:
+//
+//	validator.IsValidURL("https://example.com/path") // true
+func IsValidURL(s string) bool {
+	u, err := url.ParseRequestURI(strings.TrimSpace(s))
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// IsValidUUID checks for a canonical 8-4-4-4-12 hex UUID (any version)
+func IsValidUUID(s string) bool {
+	return uuidRegex.MatchString(s)
+}
+
+// IsValidDate checks that s parses with the given time layout
+// Example:
+//
+//	validator.IsValidDate("2024-02-30", time.DateOnly) // false
+func IsValidDate(s, layout string) bool {
+	_, err := time.Parse(layout, s)
+	return err == nil
+}
+
+// InRange checks min <= v <= max
+// Example:
+//
+//	validator.InRange(req.Rating, 1, 5)
+func InRange[T cmp.Ordered](v, min, max T) bool {
+	return v >= min && v <= max
+}
+
+// OneOf checks that v equals one of values
+// Example:
+//
+//	validator.OneOf(req.Status, "draft", "published", "archived")
+func OneOf[T comparable](v T, values ...T) bool {
+	for _, allowed := range values {
+		if v == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+	"github.com/yoockh/go-api-utils/pkg-fiber/response"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/options"
+)
+
+// JWTConfig configures JWT middleware behavior.
+// Example:
+//
+//	api := app.Group("/api")
+//	api.Use(middleware.JWTMiddleware(middleware.JWTConfig{SecretKey: "secret", UseCustomToken: true}))
+type JWTConfig struct {
+	SecretKey      string
+	UseCustomToken bool
+	SkipperFunc    func(c *fiber.Ctx) bool
+}
+
+// JWTMiddleware validates Bearer token from Authorization header and injects claims into c.Locals.
+// For custom token: stores map data under "token_data".
+// For basic token: stores user_id, email, role, and "claims".
+// opts are passed to the auth token validation (options.WithClock).
+func JWTMiddleware(config JWTConfig, opts ...options.Option) fiber.Handler {
+	if config.SecretKey == "" {
+		panic("JWT secret key cannot be empty")
+	}
+
+	return func(c *fiber.Ctx) error {
+		if config.SkipperFunc != nil && config.SkipperFunc(c) {
+			return c.Next()
+		}
+
+		authHeader := c.Get(fiber.HeaderAuthorization)
+		if authHeader == "" {
+			return response.Unauthorized(c, "missing authorization header")
+		}
+		parts := strings.Fields(authHeader)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+			return response.Unauthorized(c, "invalid authorization header format")
+		}
+		tokenString := parts[1]
+
+		if config.UseCustomToken {
+			data, err := auth.ValidateCustomToken(tokenString, config.SecretKey, opts...)
+			if err != nil {
+				if err == auth.ErrExpiredToken {
+					return response.Unauthorized(c, "token expired")
+				}
+				return response.Unauthorized(c, "invalid token")
+			}
+			c.Locals("token_data", data)
+			// Convenience extractions (if present)
+			if v, ok := data["user_id"]; ok {
+				c.Locals("user_id", v)
+			}
+			if v, ok := data["email"]; ok {
+				c.Locals("email", v)
+			}
+			if v, ok := data["role"]; ok {
+				c.Locals("role", v)
+			}
+		} else {
+			claims, err := auth.ValidateToken(tokenString, config.SecretKey, opts...)
+			if err != nil {
+				if err == auth.ErrExpiredToken {
+					return response.Unauthorized(c, "token expired")
+				}
+				return response.Unauthorized(c, "invalid token")
+			}
+			c.Locals("claims", claims)
+			c.Locals("user_id", claims.UserID)
+			c.Locals("email", claims.Email)
+			if claims.Role != "" {
+				c.Locals("role", claims.Role)
+			}
+		}
+
+		// Enrich the request-scoped logger and the error-reporting scope
+		ctx := errs.WithScope(c.UserContext())
+		uid := c.Locals("user_id")
+		ctx = logging.With(ctx, "user_id", uid)
+		if uid != nil {
+			errs.SetUser(ctx, fmt.Sprint(uid))
+		}
+		c.SetUserContext(ctx)
+
+		return c.Next()
+	}
+}
+
+// GetTokenData returns custom token data from c.Locals or empty map if not present.
+// Example:
+//
+//	data := middleware.GetTokenData(c)
+//	userID := request.GetInt(data, "user_id")
+func GetTokenData(c *fiber.Ctx) map[string]interface{} {
+	if m, ok := c.Locals("token_data").(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
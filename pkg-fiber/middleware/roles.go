@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRoles allows only requests whose role is included in the allowed list.
+// It reads "role" from c.Locals set by JWTMiddleware (custom token or basic claims).
+// Example:
+//
+//	admin := app.Group("/admin", middleware.JWTMiddleware(cfg), middleware.RequireRoles("admin"))
+func RequireRoles(allowed ...string) fiber.Handler {
+	set := map[string]struct{}{}
+	for _, r := range allowed {
+		set[strings.ToLower(strings.TrimSpace(r))] = struct{}{}
+	}
+	return func(c *fiber.Ctx) error {
+		role := CurrentRole(c)
+		if _, ok := set[strings.ToLower(role)]; !ok {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		return c.Next()
+	}
+}
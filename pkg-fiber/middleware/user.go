@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yoockh/go-api-utils/pkg-fiber/request"
+)
+
+// CurrentUserID returns user ID from c.Locals (custom token or basic claims), 0 if not found.
+// Example:
+//
+//	uid := middleware.CurrentUserID(c)
+func CurrentUserID(c *fiber.Ctx) uint {
+	switch t := c.Locals("user_id").(type) {
+	case uint:
+		return t
+	case int:
+		if t >= 0 {
+			return uint(t)
+		}
+	case float64:
+		if t >= 0 {
+			return uint(t)
+		}
+	case string:
+		if n, err := strconv.Atoi(t); err == nil && n >= 0 {
+			return uint(n)
+		}
+	}
+	// Fallback to custom token data
+	return request.GetUint(GetTokenData(c), "user_id")
+}
+
+// CurrentEmail returns email from c.Locals or empty string.
+func CurrentEmail(c *fiber.Ctx) string {
+	if s, ok := c.Locals("email").(string); ok && s != "" {
+		return s
+	}
+	return request.GetString(GetTokenData(c), "email")
+}
+
+// CurrentRole returns role from c.Locals or empty string.
+func CurrentRole(c *fiber.Ctx) string {
+	if s, ok := c.Locals("role").(string); ok && s != "" {
+		return s
+	}
+	return request.GetString(GetTokenData(c), "role")
+}
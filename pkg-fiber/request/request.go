@@ -0,0 +1,144 @@
+package request
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	echorequest "github.com/yoockh/go-api-utils/pkg-echo/request"
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	"github.com/yoockh/go-api-utils/pkg-fiber/response"
+	"github.com/yoockh/go-api-utils/pkg/sanitize"
+)
+
+// BindJSON binds the request body into a new T, applies `sanitize` tags and validates its `validate` struct tags.
+// On failure a response is sent and ok is false: 400 for malformed JSON, 422 listing
+// every invalid field for validation errors.
+// Example:
+//
+//	req, ok := request.BindJSON[CreateBookRequest](c)
+//	if !ok {
+//	    return nil // error response already sent
+//	}
+func BindJSON[T any](c *fiber.Ctx) (T, bool) {
+	var v T
+	if err := c.BodyParser(&v); err != nil {
+		response.BadRequest(c, "invalid request body")
+		return v, false
+	}
+	sanitize.Struct(&v)
+	if err := validator.StructAll(&v); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			response.ValidationError(c, verrs.Fields())
+		} else {
+			response.BadRequest(c, err.Error())
+		}
+		return v, false
+	}
+	return v, true
+}
+
+// BindAndRequireFields binds JSON request body into v and validates required JSON fields
+// by their json tag names (e.g., "email", "password"). `sanitize` tags are applied,
+// then `validate` tags are checked.
+// Example:
+//
+//	var req LoginRequest
+//	if !request.BindAndRequireFields(c, &req, "email", "password") {
+//	    return nil // error response already sent
+//	}
+func BindAndRequireFields(c *fiber.Ctx, v interface{}, requiredJSON ...string) bool {
+	if err := c.BodyParser(v); err != nil {
+		response.BadRequest(c, "invalid request body")
+		return false
+	}
+	sanitize.Struct(v)
+
+	if ok, msg := RequireFields(v, requiredJSON...); !ok {
+		response.BadRequest(c, msg)
+		return false
+	}
+	if err := validator.Struct(v); err != nil {
+		response.BadRequest(c, err.Error())
+		return false
+	}
+	return true
+}
+
+// RequireFields validates required JSON fields on an already-bound struct (see pkg-echo/request.RequireFields).
+func RequireFields(v interface{}, requiredJSON ...string) (bool, string) {
+	return echorequest.RequireFields(v, requiredJSON...)
+}
+
+// ValidateEmail validates email and sends error response if invalid
+func ValidateEmail(c *fiber.Ctx, email string) bool {
+	if !validator.IsValidEmail(email) {
+		response.BadRequest(c, "invalid email format")
+		return false
+	}
+	return true
+}
+
+// GetInt, GetUint, GetString, GetBool and GetFloat read values from custom token data
+// Returns the zero value if the key is missing or has another type.
+// Example:
+//
+//	data := middleware.GetTokenData(c)
+//	userID := request.GetInt(data, "user_id")
+func GetInt(data map[string]interface{}, key string) int { return echorequest.GetInt(data, key) }
+
+func GetUint(data map[string]interface{}, key string) uint { return echorequest.GetUint(data, key) }
+
+func GetString(data map[string]interface{}, key string) string {
+	return echorequest.GetString(data, key)
+}
+
+func GetBool(data map[string]interface{}, key string) bool { return echorequest.GetBool(data, key) }
+
+func GetFloat(data map[string]interface{}, key string) float64 {
+	return echorequest.GetFloat(data, key)
+}
+
+// QueryString returns query param as string with default fallback.
+// Example:
+//
+//	q := request.QueryString(c, "search", "")
+func QueryString(c *fiber.Ctx, key, def string) string {
+	v := c.Query(key)
+	if strings.TrimSpace(v) == "" {
+		return def
+	}
+	return v
+}
+
+// QueryInt returns query param as int with default fallback.
+// Example:
+//
+//	page := request.QueryInt(c, "page", 1)
+func QueryInt(c *fiber.Ctx, key string, def int) int {
+	v := strings.TrimSpace(c.Query(key))
+	if v == "" {
+		return def
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		return n
+	}
+	return def
+}
+
+// PathParamUint parses a path param (e.g., :id) into uint, 0 if invalid.
+// Example:
+//
+//	id := request.PathParamUint(c, "id")
+func PathParamUint(c *fiber.Ctx, key string) uint {
+	v := strings.TrimSpace(c.Params(key))
+	if v == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+		return uint(n)
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// Response represents standard API response structure
+type Response struct {
+	Success bool        `json:"success,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	// Errors maps field names to validation messages (see ValidationError)
+	Errors map[string][]string `json:"errors,omitempty"`
+}
+
+// Success sends a standardized 200 OK JSON response with message and data.
+// Example:
+//
+//	return response.Success(c, "books retrieved", books)
+func Success(c *fiber.Ctx, message string, data interface{}) error {
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    data,
+	})
+}
+
+// SuccessData sends a 200 OK JSON response with raw data (no wrapper).
+// Example:
+//
+//	return response.SuccessData(c, books)
+func SuccessData(c *fiber.Ctx, data interface{}) error {
+	return c.Status(http.StatusOK).JSON(data)
+}
+
+// Paginated sends a standardized 200 OK response with pagination metadata.
+// Example:
+//
+//	meta := fiber.Map{"page": 1, "per_page": 10, "total": 42, "total_pages": 5}
+//	return response.Paginated(c, "books retrieved", books, meta)
+func Paginated(c *fiber.Ctx, message string, data interface{}, meta interface{}) error {
+	return c.Status(http.StatusOK).JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    data,
+		"meta":    meta,
+	})
+}
+
+// Created sends 201 Created
+func Created(c *fiber.Ctx, message string, data interface{}) error {
+	return c.Status(http.StatusCreated).JSON(Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// NoContent sends 204 No Content
+func NoContent(c *fiber.Ctx) error {
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// Error sends an error response with custom status code
+func Error(c *fiber.Ctx, statusCode int, message string) error {
+	return c.Status(statusCode).JSON(Response{
+		Success: false,
+		Error:   message,
+	})
+}
+
+// BadRequest sends 400
+func BadRequest(c *fiber.Ctx, message string) error {
+	return Error(c, http.StatusBadRequest, message)
+}
+
+// Unauthorized sends 401
+func Unauthorized(c *fiber.Ctx, message string) error {
+	return Error(c, http.StatusUnauthorized, message)
+}
+
+// Forbidden sends 403
+func Forbidden(c *fiber.Ctx, message string) error {
+	return Error(c, http.StatusForbidden, message)
+}
+
+// NotFound sends 404
+func NotFound(c *fiber.Ctx, message string) error {
+	return Error(c, http.StatusNotFound, message)
+}
+
+// InternalServerError sends 500 and reports message to the error reporter
+// Fiber has no *http.Request, so the event carries the request ID, user and tags from c.UserContext().
+func InternalServerError(c *fiber.Ctx, message string) error {
+	errs.Report(c.UserContext(), errors.New(message))
+	return Error(c, http.StatusInternalServerError, message)
+}
+
+// ValidationError sends 422 with a field -> messages map.
+// Example:
+//
+//	var verrs validator.ValidationErrors
+//	if errors.As(validator.StructAll(&req), &verrs) {
+//		return response.ValidationError(c, verrs.Fields())
+//	}
+func ValidationError(c *fiber.Ctx, fields map[string][]string) error {
+	return c.Status(http.StatusUnprocessableEntity).JSON(Response{
+		Success: false,
+		Error:   "validation failed",
+		Errors:  fields,
+	})
+}
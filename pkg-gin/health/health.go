@@ -0,0 +1,49 @@
+package health
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yoockh/go-api-utils/pkg/debug"
+	"gorm.io/gorm"
+)
+
+// NewHandler returns a simple health-check handler that verifies DB connectivity.
+// Example:
+//
+//	r := gin.Default()
+//	r.GET("/health", health.NewHandler(db))
+func NewHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		type status struct {
+			Status string `json:"status"`
+			DB     string `json:"db"`
+			Time   string `json:"time"`
+		}
+		dbStatus := "ok"
+		if err := db.Exec("SELECT 1").Error; err != nil {
+			dbStatus = "down"
+		}
+		c.JSON(http.StatusOK, status{
+			Status: "ok",
+			DB:     dbStatus,
+			Time:   time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+}
+
+// NewStatsHandler returns runtime stats (goroutines, memory, GC, uptime) and the DB pool stats as JSON.
+// Protect the route; the output is meant for operators.
+func NewStatsHandler(db *gorm.DB) gin.HandlerFunc {
+	dbs := map[string]*sql.DB{}
+	if db != nil {
+		if sqlDB, err := db.DB(); err == nil {
+			dbs["main"] = sqlDB
+		}
+	}
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, debug.ReadStats(dbs))
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+	"github.com/yoockh/go-api-utils/pkg-gin/response"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/options"
+)
+
+// JWTConfig configures JWT middleware behavior.
+// Example:
+//
+//	api := r.Group("/api")
+//	api.Use(middleware.JWTMiddleware(middleware.JWTConfig{SecretKey: "secret", UseCustomToken: true}))
+type JWTConfig struct {
+	SecretKey      string
+	UseCustomToken bool
+	SkipperFunc    func(c *gin.Context) bool
+}
+
+// JWTMiddleware validates Bearer token from Authorization header and injects claims into context.
+// For custom token: stores map data under "token_data".
+// For basic token: stores user_id, email, role, and "claims".
+// opts are passed to the auth token validation (options.WithClock).
+func JWTMiddleware(config JWTConfig, opts ...options.Option) gin.HandlerFunc {
+	if config.SecretKey == "" {
+		panic("JWT secret key cannot be empty")
+	}
+
+	return func(c *gin.Context) {
+		if config.SkipperFunc != nil && config.SkipperFunc(c) {
+			c.Next()
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			response.Unauthorized(c, "missing authorization header")
+			return
+		}
+		parts := strings.Fields(authHeader)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+			response.Unauthorized(c, "invalid authorization header format")
+			return
+		}
+		tokenString := parts[1]
+
+		if config.UseCustomToken {
+			data, err := auth.ValidateCustomToken(tokenString, config.SecretKey, opts...)
+			if err != nil {
+				if err == auth.ErrExpiredToken {
+					response.Unauthorized(c, "token expired")
+					return
+				}
+				response.Unauthorized(c, "invalid token")
+				return
+			}
+			c.Set("token_data", data)
+			// Convenience extractions (if present)
+			if v, ok := data["user_id"]; ok {
+				c.Set("user_id", v)
+			}
+			if v, ok := data["email"]; ok {
+				c.Set("email", v)
+			}
+			if v, ok := data["role"]; ok {
+				c.Set("role", v)
+			}
+		} else {
+			claims, err := auth.ValidateToken(tokenString, config.SecretKey, opts...)
+			if err != nil {
+				if err == auth.ErrExpiredToken {
+					response.Unauthorized(c, "token expired")
+					return
+				}
+				response.Unauthorized(c, "invalid token")
+				return
+			}
+			c.Set("claims", claims)
+			c.Set("user_id", claims.UserID)
+			c.Set("email", claims.Email)
+			if claims.Role != "" {
+				c.Set("role", claims.Role)
+			}
+		}
+
+		// Enrich the request-scoped logger and the error-reporting scope
+		ctx := errs.WithScope(c.Request.Context())
+		uid, _ := c.Get("user_id")
+		logging.With(ctx, "user_id", uid)
+		if uid != nil {
+			errs.SetUser(ctx, fmt.Sprint(uid))
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// GetTokenData returns custom token data from context or empty map if not present.
+// Example:
+//
+//	data := middleware.GetTokenData(c)
+//	userID := request.GetInt(data, "user_id")
+func GetTokenData(c *gin.Context) map[string]interface{} {
+	v, ok := c.Get("token_data")
+	if !ok {
+		return map[string]interface{}{}
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
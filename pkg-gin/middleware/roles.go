@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRoles allows only requests whose role is included in the allowed list.
+// It reads "role" from context keys set by JWTMiddleware (custom token or basic claims).
+// Example:
+//
+//	api := r.Group("/api", middleware.JWTMiddleware(middleware.JWTConfig{SecretKey: "secret"}))
+//	api.GET("/admin/stats", middleware.RequireRoles("admin"), adminHandler)
+func RequireRoles(allowed ...string) gin.HandlerFunc {
+	set := map[string]struct{}{}
+	for _, r := range allowed {
+		set[strings.ToLower(strings.TrimSpace(r))] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		role := CurrentRole(c)
+		if _, ok := set[strings.ToLower(role)]; !ok {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+}
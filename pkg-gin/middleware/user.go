@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yoockh/go-api-utils/pkg-gin/request"
+)
+
+// CurrentUserID returns user ID from context (custom token or basic claims), 0 if not found.
+// Example:
+//
+//	uid := middleware.CurrentUserID(c)
+func CurrentUserID(c *gin.Context) uint {
+	if v, ok := c.Get("user_id"); ok {
+		switch t := v.(type) {
+		case uint:
+			return t
+		case int:
+			if t >= 0 {
+				return uint(t)
+			}
+		case float64:
+			if t >= 0 {
+				return uint(t)
+			}
+		case string:
+			if n, err := strconv.Atoi(t); err == nil && n >= 0 {
+				return uint(n)
+			}
+		}
+	}
+	// Fallback to custom token data
+	return request.GetUint(GetTokenData(c), "user_id")
+}
+
+// CurrentEmail returns email from context or empty string.
+func CurrentEmail(c *gin.Context) string {
+	if s := c.GetString("email"); s != "" {
+		return s
+	}
+	return request.GetString(GetTokenData(c), "email")
+}
+
+// CurrentRole returns role from context or empty string.
+func CurrentRole(c *gin.Context) string {
+	if s := c.GetString("role"); s != "" {
+		return s
+	}
+	return request.GetString(GetTokenData(c), "role")
+}
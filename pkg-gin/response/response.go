@@ -0,0 +1,117 @@
+package response
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// Response represents standard API response structure
+type Response struct {
+	Success bool        `json:"success,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	// Errors maps field names to validation messages (see ValidationError)
+	Errors map[string][]string `json:"errors,omitempty"`
+}
+
+// Success sends a standardized 200 OK JSON response with message and data.
+// Example:
+//
+//	response.Success(c, "books retrieved", books)
+func Success(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    data,
+	})
+}
+
+// SuccessData sends a 200 OK JSON response with raw data (no wrapper).
+// Example:
+//
+//	response.SuccessData(c, books)
+func SuccessData(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusOK, data)
+}
+
+// Paginated sends a standardized 200 OK response with pagination metadata.
+// Example:
+//
+//	meta := gin.H{"page": 1, "per_page": 10, "total": 42, "total_pages": 5}
+//	response.Paginated(c, "books retrieved", books, meta)
+func Paginated(c *gin.Context, message string, data interface{}, meta interface{}) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    data,
+		"meta":    meta,
+	})
+}
+
+// Created sends 201 Created
+func Created(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusCreated, Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
+// NoContent sends 204 No Content
+func NoContent(c *gin.Context) {
+	c.Status(http.StatusNoContent)
+}
+
+// Error sends an error response and aborts the handler chain
+func Error(c *gin.Context, statusCode int, message string) {
+	c.AbortWithStatusJSON(statusCode, Response{
+		Success: false,
+		Error:   message,
+	})
+}
+
+// BadRequest sends 400
+func BadRequest(c *gin.Context, message string) {
+	Error(c, http.StatusBadRequest, message)
+}
+
+// Unauthorized sends 401
+func Unauthorized(c *gin.Context, message string) {
+	Error(c, http.StatusUnauthorized, message)
+}
+
+// Forbidden sends 403
+func Forbidden(c *gin.Context, message string) {
+	Error(c, http.StatusForbidden, message)
+}
+
+// NotFound sends 404
+func NotFound(c *gin.Context, message string) {
+	Error(c, http.StatusNotFound, message)
+}
+
+// InternalServerError sends 500 and reports message to the error reporter with the request context
+func InternalServerError(c *gin.Context, message string) {
+	errs.ReportRequest(c.Request, errors.New(message))
+	Error(c, http.StatusInternalServerError, message)
+}
+
+// ValidationError sends 422 with a field -> messages map.
+// Example:
+//
+//	var verrs validator.ValidationErrors
+//	if errors.As(validator.StructAll(&req), &verrs) {
+//		response.ValidationError(c, verrs.Fields())
+//		return
+//	}
+func ValidationError(c *gin.Context, fields map[string][]string) {
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, Response{
+		Success: false,
+		Error:   "validation failed",
+		Errors:  fields,
+	})
+}
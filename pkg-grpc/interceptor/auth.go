@@ -0,0 +1,139 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// JWTConfig configures the JWT interceptors
+// Tokens are the same ones accepted by the HTTP middleware (pkg-echo/auth).
+type JWTConfig struct {
+	SecretKey      string
+	UseCustomToken bool
+	// SkipperFunc returns true for methods that don't need a token,
+	// e.g. "/grpc.health.v1.Health/Check"
+	SkipperFunc func(fullMethod string) bool
+}
+
+// UnaryJWT validates the Bearer token from the "authorization" metadata
+// The claims are stored in the context the same way pkg/middleware.JWT does, so handlers
+// use middleware.UserIDFromContext(ctx), middleware.RoleFromContext(ctx), etc.
+// Missing or invalid tokens fail with codes.Unauthenticated.
+// Example:
+//
+//	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+//		interceptor.UnaryLogger(logger),
+//		interceptor.UnaryJWT(interceptor.JWTConfig{SecretKey: os.Getenv("JWT_SECRET")}),
+//	))
+func UnaryJWT(config JWTConfig) grpc.UnaryServerInterceptor {
+	if config.SecretKey == "" {
+		panic("JWT secret key cannot be empty")
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, config, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamJWT is UnaryJWT for streaming RPCs
+func StreamJWT(config JWTConfig) grpc.StreamServerInterceptor {
+	if config.SecretKey == "" {
+		panic("JWT secret key cannot be empty")
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), config, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// RequireRoles returns a unary interceptor allowing only the given roles (codes.PermissionDenied otherwise)
+// Chain it after UnaryJWT.
+func RequireRoles(allowed ...string) grpc.UnaryServerInterceptor {
+	check := roleChecker(allowed)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := check(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRequireRoles is RequireRoles for streaming RPCs; chain it after StreamJWT
+// Example:
+//
+//	srv := grpc.NewServer(grpc.ChainStreamInterceptor(
+//		interceptor.StreamJWT(jwtConfig),
+//		interceptor.StreamRequireRoles("admin"),
+//	))
+func StreamRequireRoles(allowed ...string) grpc.StreamServerInterceptor {
+	check := roleChecker(allowed)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := check(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// roleChecker returns a check failing with codes.PermissionDenied unless the role in ctx is
+// one of allowed (case-insensitive)
+func roleChecker(allowed []string) func(ctx context.Context) error {
+	set := map[string]struct{}{}
+	for _, role := range allowed {
+		set[strings.ToLower(strings.TrimSpace(role))] = struct{}{}
+	}
+	return func(ctx context.Context) error {
+		if _, ok := set[strings.ToLower(middleware.RoleFromContext(ctx))]; !ok {
+			return status.Error(codes.PermissionDenied, "insufficient role")
+		}
+		return nil
+	}
+}
+
+func authenticate(ctx context.Context, config JWTConfig, fullMethod string) (context.Context, error) {
+	if config.SkipperFunc != nil && config.SkipperFunc(fullMethod) {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return ctx, status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+	parts := strings.Fields(values[0])
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return ctx, status.Error(codes.Unauthenticated, "invalid authorization metadata format")
+	}
+
+	ctx, err := middleware.Authenticate(ctx, middleware.JWTConfig{
+		SecretKey:      config.SecretKey,
+		UseCustomToken: config.UseCustomToken,
+	}, parts[1])
+	if err != nil {
+		if err == auth.ErrExpiredToken {
+			return ctx, status.Error(codes.Unauthenticated, "token expired")
+		}
+		return ctx, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return ctx, nil
+}
+
+// serverStream overrides the context of a grpc.ServerStream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,82 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryLogger attaches a request-scoped logger (request_id, method) to the context and logs
+// each call with its status code and duration, like the HTTP logging middleware.
+// The request ID is taken from the "x-request-id" metadata if present, otherwise generated,
+// and sent back in the response header. Put it first so UnaryJWT can add user_id.
+// Example:
+//
+//	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptor.UnaryLogger(logger), interceptor.UnaryJWT(cfg)))
+//
+//	// inside handlers
+//	logging.FromContext(ctx).Info("order created", "id", order.ID)
+func UnaryLogger(base *slog.Logger) grpc.UnaryServerInterceptor {
+	if base == nil {
+		base = slog.Default()
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = withLogger(ctx, base, info.FullMethod)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, err, start)
+		return resp, err
+	}
+}
+
+// StreamLogger is UnaryLogger for streaming RPCs; the call is logged when the stream ends
+func StreamLogger(base *slog.Logger) grpc.StreamServerInterceptor {
+	if base == nil {
+		base = slog.Default()
+	}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withLogger(ss.Context(), base, info.FullMethod)
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, err, start)
+		return err
+	}
+}
+
+func withLogger(ctx context.Context, base *slog.Logger, fullMethod string) context.Context {
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(strings.ToLower(logging.RequestIDHeader)); len(v) > 0 {
+			id = v[0]
+		}
+	}
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(logging.RequestIDHeader), id))
+
+	logger := base.With(
+		slog.String("request_id", id),
+		slog.String("method", fullMethod),
+	)
+	ctx = logging.WithRequestID(ctx, id)
+	return logging.NewContext(ctx, logger)
+}
+
+func logCall(ctx context.Context, err error, start time.Time) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	logging.FromContext(ctx).Log(ctx, level, "grpc call",
+		slog.String("code", code.String()),
+		slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+	)
+}
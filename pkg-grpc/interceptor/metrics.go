@@ -0,0 +1,75 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics records grpc_server_handled_total{service,method,code} and
+// grpc_server_handling_seconds{service,method} for every call
+// Example:
+//
+//	m := interceptor.NewMetrics(metrics.Default)
+//	srv := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(m.Unary()),
+//		grpc.ChainStreamInterceptor(m.Stream()),
+//	)
+//	http.Handle("/metrics", metrics.Handler())
+type Metrics struct {
+	handled  *metrics.Counter
+	duration *metrics.Histogram
+}
+
+// NewMetrics creates the gRPC metrics and registers them in reg (metrics.Default if nil)
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	if reg == nil {
+		reg = metrics.Default
+	}
+	m := &Metrics{
+		handled:  metrics.NewCounter("grpc_server_handled_total", "Total RPCs completed on the server", "service", "method", "code"),
+		duration: metrics.NewHistogram("grpc_server_handling_seconds", "RPC handling duration", nil, "service", "method"),
+	}
+	reg.MustRegister(m.handled)
+	reg.MustRegister(m.duration)
+	return m
+}
+
+// Unary returns the unary interceptor
+func (m *Metrics) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+// Stream returns the stream interceptor
+func (m *Metrics) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, err, start)
+		return err
+	}
+}
+
+func (m *Metrics) observe(fullMethod string, err error, start time.Time) {
+	service, method := splitMethod(fullMethod)
+	m.handled.Inc(service, method, status.Code(err).String())
+	m.duration.Observe(time.Since(start).Seconds(), service, method)
+}
+
+// splitMethod splits "/package.Service/Method" into service and method
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
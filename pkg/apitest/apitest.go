@@ -0,0 +1,379 @@
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+)
+
+// API sends requests to an http.Handler (a ServeMux, router or *echo.Echo) in tests
+type API struct {
+	t       testing.TB
+	handler http.Handler
+	header  http.Header
+
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// Exchange is a recorded request/response pair
+type Exchange struct {
+	Request      *http.Request
+	RequestBody  []byte
+	Status       int
+	Header       http.Header
+	ResponseBody []byte
+}
+
+// New creates an API for handler
+// Example:
+//
+//	func TestListProducts(t *testing.T) {
+//		api := apitest.New(t, newRouter())
+//		api.Get("/products").Query("page", "2").Expect().
+//			Status(http.StatusOK).
+//			Success().
+//			JSONPath("data.0.name", "Laptop")
+//	}
+func New(t testing.TB, handler http.Handler) *API {
+	return &API{t: t, handler: handler, header: http.Header{}}
+}
+
+// WithHeader sets a header on every request sent by a
+func (a *API) WithHeader(key, value string) *API {
+	a.header.Set(key, value)
+	return a
+}
+
+// WithBearer authenticates every request sent by a with token
+func (a *API) WithBearer(token string) *API {
+	return a.WithHeader("Authorization", "Bearer "+token)
+}
+
+// Exchanges returns the request/response pairs sent so far
+func (a *API) Exchanges() []Exchange {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]Exchange(nil), a.exchanges...)
+}
+
+// Get starts a GET request
+func (a *API) Get(path string) *Request { return a.Request(http.MethodGet, path) }
+
+// Post starts a POST request
+func (a *API) Post(path string) *Request { return a.Request(http.MethodPost, path) }
+
+// Put starts a PUT request
+func (a *API) Put(path string) *Request { return a.Request(http.MethodPut, path) }
+
+// Patch starts a PATCH request
+func (a *API) Patch(path string) *Request { return a.Request(http.MethodPatch, path) }
+
+// Delete starts a DELETE request
+func (a *API) Delete(path string) *Request { return a.Request(http.MethodDelete, path) }
+
+// Request starts a request with any method
+func (a *API) Request(method, path string) *Request {
+	return &Request{api: a, method: method, path: path, header: a.header.Clone(), query: url.Values{}}
+}
+
+// Token generates a basic JWT (pkg-echo/auth) valid for one hour, failing the test on error
+// Example:
+//
+//	token := apitest.Token(t, "secret", 1, "admin@example.com", "admin")
+//	api.Delete("/products/1").Bearer(token).Expect().Status(http.StatusNoContent)
+func Token(t testing.TB, secret string, userID int, email, role string) string {
+	t.Helper()
+	token, err := auth.GenerateToken(userID, email, role, secret, time.Hour)
+	if err != nil {
+		t.Fatalf("apitest: failed to generate token: %v", err)
+	}
+	return token
+}
+
+// Request is a request being built
+type Request struct {
+	api         *API
+	method      string
+	path        string
+	header      http.Header
+	query       url.Values
+	body        []byte
+	contentType string
+}
+
+// Header sets a request header
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// Bearer sets the Authorization header
+func (r *Request) Bearer(token string) *Request {
+	return r.Header("Authorization", "Bearer "+token)
+}
+
+// Query adds a query parameter
+func (r *Request) Query(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// JSON encodes v as the request body
+func (r *Request) JSON(v interface{}) *Request {
+	r.api.t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		r.api.t.Fatalf("apitest: failed to encode request body: %v", err)
+	}
+	r.body, r.contentType = body, "application/json"
+	return r
+}
+
+// Body sets a raw request body and its content type
+func (r *Request) Body(body, contentType string) *Request {
+	r.body, r.contentType = []byte(body), contentType
+	return r
+}
+
+// Expect sends the request and returns the response for assertions
+func (r *Request) Expect() *Response {
+	t := r.api.t
+	t.Helper()
+
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+	req := httptest.NewRequest(r.method, target, bytes.NewReader(r.body))
+	req.Header = r.header.Clone()
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+
+	rec := httptest.NewRecorder()
+	r.api.handler.ServeHTTP(rec, req)
+
+	resp := &Response{t: t, Recorder: rec, body: rec.Body.Bytes()}
+	r.api.mu.Lock()
+	r.api.exchanges = append(r.api.exchanges, Exchange{
+		Request:      req,
+		RequestBody:  r.body,
+		Status:       rec.Code,
+		Header:       rec.Header().Clone(),
+		ResponseBody: resp.body,
+	})
+	r.api.mu.Unlock()
+	return resp
+}
+
+// Response holds a recorded response and asserts on it
+// Failed assertions are reported with t.Errorf, so one call can report several problems.
+type Response struct {
+	t        testing.TB
+	Recorder *httptest.ResponseRecorder
+	body     []byte
+
+	decoded interface{}
+	parsed  bool
+}
+
+// Status asserts the status code
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+	if r.Recorder.Code != code {
+		r.t.Errorf("apitest: status = %d, want %d; body: %s", r.Recorder.Code, code, truncate(r.body))
+	}
+	return r
+}
+
+// Header asserts a response header value
+func (r *Response) Header(key, want string) *Response {
+	r.t.Helper()
+	if got := r.Recorder.Header().Get(key); got != want {
+		r.t.Errorf("apitest: header %s = %q, want %q", key, got, want)
+	}
+	return r
+}
+
+// Success asserts the envelope's "success" is true
+func (r *Response) Success() *Response {
+	r.t.Helper()
+	return r.JSONPath("success", true)
+}
+
+// Message asserts the envelope's "message"
+func (r *Response) Message(want string) *Response {
+	r.t.Helper()
+	return r.JSONPath("message", want)
+}
+
+// Error asserts the envelope's "error"
+func (r *Response) Error(want string) *Response {
+	r.t.Helper()
+	return r.JSONPath("error", want)
+}
+
+// FieldError asserts that the validation "errors" map has messages for field
+func (r *Response) FieldError(field string) *Response {
+	r.t.Helper()
+	if v, ok := r.lookup("errors." + field); !ok || v == nil {
+		r.t.Errorf("apitest: no validation error for %q; body: %s", field, truncate(r.body))
+	}
+	return r
+}
+
+// JSONPath asserts the value at a dotted path ("data.items.0.name") equals want
+// want is compared by its JSON form, so 1, int64(1) and 1.0 are equal.
+func (r *Response) JSONPath(path string, want interface{}) *Response {
+	r.t.Helper()
+	got, ok := r.lookup(path)
+	if !ok {
+		r.t.Errorf("apitest: JSON path %q not found; body: %s", path, truncate(r.body))
+		return r
+	}
+	if !reflect.DeepEqual(got, normalize(want)) {
+		r.t.Errorf("apitest: JSON path %q = %s, want %s", path, encode(got), encode(want))
+	}
+	return r
+}
+
+// JSONPathExists asserts a path is present (its value may be null)
+func (r *Response) JSONPathExists(path string) *Response {
+	r.t.Helper()
+	if _, ok := r.lookup(path); !ok {
+		r.t.Errorf("apitest: JSON path %q not found; body: %s", path, truncate(r.body))
+	}
+	return r
+}
+
+// JSONPathLen asserts the array or object at path has n elements
+func (r *Response) JSONPathLen(path string, n int) *Response {
+	r.t.Helper()
+	got, _ := r.lookup(path)
+	var size int
+	switch v := got.(type) {
+	case []interface{}:
+		size = len(v)
+	case map[string]interface{}:
+		size = len(v)
+	default:
+		r.t.Errorf("apitest: JSON path %q is not an array or object; body: %s", path, truncate(r.body))
+		return r
+	}
+	if size != n {
+		r.t.Errorf("apitest: JSON path %q has %d elements, want %d", path, size, n)
+	}
+	return r
+}
+
+// Decode unmarshals the whole body into v
+func (r *Response) Decode(v interface{}) *Response {
+	r.t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		r.t.Fatalf("apitest: failed to decode body: %v; body: %s", err, truncate(r.body))
+	}
+	return r
+}
+
+// Data unmarshals the envelope's "data" into v
+// Example:
+//
+//	var p Product
+//	api.Post("/products").JSON(req).Expect().Status(http.StatusCreated).Data(&p)
+func (r *Response) Data(v interface{}) *Response {
+	r.t.Helper()
+	var env struct {
+		Data json.RawMessage `json:"data"`
+	}
+	r.Decode(&env)
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		r.t.Fatalf("apitest: failed to decode data: %v; body: %s", err, truncate(r.body))
+	}
+	return r
+}
+
+// Body returns the raw response body
+func (r *Response) Body() []byte {
+	return r.body
+}
+
+func (r *Response) lookup(path string) (interface{}, bool) {
+	if !r.parsed {
+		r.parsed = true
+		if err := json.Unmarshal(r.body, &r.decoded); err != nil {
+			r.t.Helper()
+			r.t.Errorf("apitest: response is not JSON: %v; body: %s", err, truncate(r.body))
+		}
+	}
+	return Lookup(r.decoded, path)
+}
+
+// Lookup resolves a dotted path ("data.items.0.name") in decoded JSON
+func Lookup(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			next, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// normalize converts v to the types encoding/json produces when decoding into interface{}
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func encode(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "<unencodable>"
+	}
+	return string(data)
+}
+
+func truncate(body []byte) string {
+	const max = 2048
+	if len(body) > max {
+		return string(body[:max]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,161 @@
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/yoockh/go-api-utils/pkg/openapi"
+)
+
+// ContractOption configures contract checks
+type ContractOption func(*contract)
+
+type contract struct {
+	allowUnknown bool
+}
+
+// AllowUnknownFields accepts object properties that the schema doesn't document
+func AllowUnknownFields() ContractOption {
+	return func(c *contract) { c.allowUnknown = true }
+}
+
+// Contract checks every exchange sent by a so far against spec; call it at the end of a test
+// Example:
+//
+//	func TestBooksContract(t *testing.T) {
+//		mux, spec := newRouter()
+//		api := apitest.New(t, mux)
+//		api.Post("/books").JSON(CreateBookRequest{Title: "Go"}).Expect().Status(http.StatusCreated)
+//		api.Get("/books/1").Expect().Status(http.StatusOK)
+//		api.Contract(spec)
+//	}
+func (a *API) Contract(spec *openapi.Spec, opts ...ContractOption) {
+	a.t.Helper()
+	AssertContract(a.t, spec, a.Exchanges(), opts...)
+}
+
+// AssertContract fails the test for each exchange that drifts from the spec
+// An exchange drifts when its route or status isn't documented, or when its response body
+// doesn't match the documented schema (undocumented properties included, see AllowUnknownFields).
+// Request bodies and parameters are only checked for 2xx responses, so tests sending
+// invalid input on purpose don't fail.
+func AssertContract(t testing.TB, spec *openapi.Spec, exchanges []Exchange, opts ...ContractOption) {
+	t.Helper()
+	c := &contract{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	doc := spec.Document()
+	for _, ex := range exchanges {
+		for _, problem := range c.check(&doc, ex) {
+			t.Errorf("apitest: %s %s -> %d: %s", ex.Request.Method, ex.Request.URL.Path, ex.Status, problem)
+		}
+	}
+}
+
+func (c *contract) check(doc *openapi.Document, ex Exchange) []string {
+	path, op, params := findOperation(doc, ex.Request.Method, ex.Request.URL.Path)
+	if op == nil {
+		return []string{"route is not documented"}
+	}
+
+	var problems []string
+	report := func(where string, errs []error) {
+		for _, err := range errs {
+			problems = append(problems, where+": "+err.Error())
+		}
+	}
+
+	if ex.Status >= 200 && ex.Status < 300 {
+		query := ex.Request.URL.Query()
+		for _, p := range op.Parameters {
+			var raw string
+			var present bool
+			switch p.In {
+			case "path":
+				raw, present = params[p.Name]
+			case "query":
+				raw, present = query.Get(p.Name), query.Has(p.Name)
+			case "header":
+				raw, present = ex.Request.Header.Get(p.Name), ex.Request.Header.Get(p.Name) != ""
+			}
+			if !present {
+				if p.Required {
+					problems = append(problems, fmt.Sprintf("required %s parameter %q is missing", p.In, p.Name))
+				}
+				continue
+			}
+			if err := doc.ValidateParam(p.Schema, raw); err != nil {
+				problems = append(problems, fmt.Sprintf("%s parameter %q: %v", p.In, p.Name, err))
+			}
+		}
+
+		if body := op.RequestBody; body != nil {
+			if schema, ok := jsonSchema(body.Content); ok && len(ex.RequestBody) > 0 {
+				var v interface{}
+				if err := json.Unmarshal(ex.RequestBody, &v); err != nil {
+					problems = append(problems, "request body is not JSON: "+err.Error())
+				} else {
+					report("request body", doc.Validate(schema, v, !c.allowUnknown))
+				}
+			} else if body.Required && len(ex.RequestBody) == 0 {
+				problems = append(problems, "request body is required")
+			}
+		}
+	}
+
+	resp := op.Response(ex.Status)
+	if resp == nil {
+		return append(problems, fmt.Sprintf("status %d is not documented for %s %s", ex.Status, strings.ToUpper(ex.Request.Method), path))
+	}
+	schema, ok := jsonSchema(resp.Content)
+	switch {
+	case !ok && len(resp.Content) == 0 && len(ex.ResponseBody) > 0:
+		problems = append(problems, "response has a body but none is documented")
+	case ok:
+		if mt, _, _ := mime.ParseMediaType(ex.Header.Get("Content-Type")); mt != "application/json" && !strings.HasSuffix(mt, "+json") {
+			problems = append(problems, fmt.Sprintf("response Content-Type is %q, want application/json", ex.Header.Get("Content-Type")))
+			break
+		}
+		var v interface{}
+		if err := json.Unmarshal(ex.ResponseBody, &v); err != nil {
+			problems = append(problems, "response body is not JSON: "+err.Error())
+			break
+		}
+		report("response body", doc.Validate(schema, v, !c.allowUnknown))
+	}
+	return problems
+}
+
+// findOperation matches the request path, also without the path of a documented server URL ("/v1")
+func findOperation(doc *openapi.Document, method, path string) (string, *openapi.OperationObject, map[string]string) {
+	if p, op, params := doc.FindOperation(method, path); op != nil {
+		return p, op, params
+	}
+	for _, s := range doc.Servers {
+		u, err := url.Parse(s.URL)
+		if err != nil || strings.Trim(u.Path, "/") == "" {
+			continue
+		}
+		base := "/" + strings.Trim(u.Path, "/")
+		if rest, ok := strings.CutPrefix(path, base); ok && (rest == "" || rest[0] == '/') {
+			if p, op, params := doc.FindOperation(method, rest); op != nil {
+				return p, op, params
+			}
+		}
+	}
+	return "", nil, nil
+}
+
+func jsonSchema(content map[string]openapi.MediaType) (*openapi.Schema, bool) {
+	for mt, m := range content {
+		if mt == "application/json" || strings.HasSuffix(mt, "+json") {
+			return m.Schema, true
+		}
+	}
+	return nil, false
+}
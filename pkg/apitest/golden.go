@@ -0,0 +1,228 @@
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// update is registered by apitest, so don't define another -update flag in tests importing it
+var update = flag.Bool("update", false, "rewrite apitest golden files with the current responses")
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// GoldenOption configures golden file comparison
+type GoldenOption func(*golden)
+
+type golden struct {
+	paths [][]string
+	keys  map[string]bool
+	exact bool
+}
+
+// Mask replaces the values at dotted paths with "<masked>"; "*" matches any key or index
+// Example:
+//
+//	apitest.Mask("data.id", "data.items.*.id")
+func Mask(paths ...string) GoldenOption {
+	return func(g *golden) {
+		for _, p := range paths {
+			g.paths = append(g.paths, strings.Split(p, "."))
+		}
+	}
+}
+
+// MaskKeys replaces the values of the named keys with "<masked>" at any depth
+func MaskKeys(keys ...string) GoldenOption {
+	return func(g *golden) {
+		for _, k := range keys {
+			g.keys[k] = true
+		}
+	}
+}
+
+// Exact disables the default masking of RFC 3339 timestamps and UUID strings
+func Exact() GoldenOption {
+	return func(g *golden) { g.exact = true }
+}
+
+// Golden compares the JSON body with testdata/<name>.golden.json
+// Keys are sorted and the body is indented, so the file diffs well in review.
+// Timestamps and UUIDs are masked by default; mask numeric ids and other volatile values
+// with Mask or MaskKeys. Run `go test -update` (or UPDATE_GOLDEN=1) to write the files.
+// Example:
+//
+//	api.Get("/products/1").Expect().
+//		Status(http.StatusOK).
+//		Golden("get_product", apitest.MaskKeys("id"))
+func (r *Response) Golden(name string, opts ...GoldenOption) *Response {
+	r.t.Helper()
+	AssertGolden(r.t, name, r.body, opts...)
+	return r
+}
+
+// AssertGolden compares any JSON body (e.g. from an echo or gin recorder) with testdata/<name>.golden.json
+func AssertGolden(t testing.TB, name string, body []byte, opts ...GoldenOption) {
+	t.Helper()
+	g := &golden{keys: map[string]bool{}}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		t.Errorf("apitest: response is not JSON: %v; body: %s", err, truncate(body))
+		return
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(g.normalize(v, nil)); err != nil {
+		t.Errorf("apitest: failed to encode response: %v", err)
+		return
+	}
+	got := buf.Bytes()
+
+	file := filepath.Join("testdata", name+".golden.json")
+	if *update || os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("apitest: failed to create %s: %v", filepath.Dir(file), err)
+		}
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			t.Fatalf("apitest: failed to write %s: %v", file, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Errorf("apitest: failed to read golden file (run go test -update to create it): %v", err)
+		return
+	}
+	if string(want) != string(got) {
+		t.Errorf("apitest: response differs from %s (run go test -update to accept it):\n%s",
+			file, lineDiff(string(want), string(got)))
+	}
+}
+
+func (g *golden) normalize(v interface{}, path []string) interface{} {
+	if g.masked(path) {
+		return "<masked>"
+	}
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for k, child := range node {
+			node[k] = g.normalize(child, append(path, k))
+		}
+	case []interface{}:
+		for i, child := range node {
+			node[i] = g.normalize(child, append(path, strconv.Itoa(i)))
+		}
+	case string:
+		if g.exact {
+			break
+		}
+		if _, err := time.Parse(time.RFC3339Nano, node); err == nil {
+			return "<timestamp>"
+		}
+		if uuidPattern.MatchString(node) {
+			return "<uuid>"
+		}
+	}
+	return v
+}
+
+func (g *golden) masked(path []string) bool {
+	if len(path) > 0 && g.keys[path[len(path)-1]] {
+		return true
+	}
+outer:
+	for _, pattern := range g.paths {
+		if len(pattern) != len(path) {
+			continue
+		}
+		for i, seg := range pattern {
+			if seg != "*" && seg != path[i] {
+				continue outer
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// lineDiff shows want/got line by line, "-" for golden lines and "+" for new ones,
+// with two lines of context around each change
+func lineDiff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(a)*len(b) > 4_000_000 {
+		return "--- want\n" + want + "+++ got\n" + got
+	}
+
+	// Longest common subsequence table, filled from the end
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	const context = 2
+	var out strings.Builder
+	last := -1
+	for n, l := range lines {
+		near := false
+		for k := max(0, n-context); k <= min(len(lines)-1, n+context); k++ {
+			if lines[k].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if last >= 0 && n > last+1 {
+			out.WriteString("  ...\n")
+		}
+		out.WriteByte(l.op)
+		out.WriteString(" " + l.text + "\n")
+		last = n
+	}
+	return out.String()
+}
@@ -0,0 +1,207 @@
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a single audit record: who did what to which resource
+type Entry struct {
+	ID         int64             `json:"id"`
+	ActorID    string            `json:"actor_id"`
+	Action     string            `json:"action"`   // e.g. "create", "update", "delete", "login"
+	Resource   string            `json:"resource"` // e.g. "products"
+	ResourceID string            `json:"resource_id,omitempty"`
+	Changes    map[string]Change `json:"changes,omitempty"`
+	IP         string            `json:"ip,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Status     int               `json:"status,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Change is the before/after value of a single field
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Filter narrows Query results; zero values are ignored
+type Filter struct {
+	ActorID    string
+	Action     string
+	Resource   string
+	ResourceID string
+	Since      time.Time
+	Until      time.Time
+	Limit      int // default 50, max 500
+	Offset     int
+}
+
+// Store persists and queries audit entries
+type Store interface {
+	Record(ctx context.Context, e *Entry) error
+	Query(ctx context.Context, f Filter) ([]Entry, int64, error)
+}
+
+// redactedKeys are replaced with "[REDACTED]" in diffs
+var redactedKeys = []string{"password", "secret", "token"}
+
+// Diff compares two values (structs or maps) field by field using their JSON representation
+// Only changed fields are returned; pass nil before for creates and nil after for deletes.
+// Fields whose name contains password, secret or token are redacted.
+// Example:
+//
+//	changes := auditlog.Diff(oldProduct, newProduct)
+//	// {"price": {"from": 10, "to": 12}}
+func Diff(before, after any) map[string]Change {
+	b, a := toMap(before), toMap(after)
+	keys := map[string]struct{}{}
+	for k := range b {
+		keys[k] = struct{}{}
+	}
+	for k := range a {
+		keys[k] = struct{}{}
+	}
+	names := make([]string, 0, len(keys))
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	changes := map[string]Change{}
+	for _, k := range names {
+		from, to := b[k], a[k]
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		if isRedacted(k) {
+			from, to = redact(from), redact(to)
+		}
+		changes[k] = Change{From: from, To: to}
+	}
+	return changes
+}
+
+func toMap(v any) map[string]any {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+func isRedacted(key string) bool {
+	k := strings.ToLower(key)
+	for _, r := range redactedKeys {
+		if strings.Contains(k, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	return "[REDACTED]"
+}
+
+// pending is the per-request record that handlers fill in with Track
+type pending struct {
+	mu    sync.Mutex
+	entry Entry
+	skip  bool
+}
+
+type pendingKey struct{}
+
+// Begin attaches an empty pending record to ctx (done by the audit middleware)
+func Begin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pendingKey{}, &pending{})
+}
+
+// Track describes the change made by the current request
+// Call it from handlers wrapped by the audit middleware; outside it this is a no-op.
+// Example:
+//
+//	auditlog.Track(r.Context(), "products", strconv.Itoa(p.ID), oldProduct, p)
+func Track(ctx context.Context, resource, resourceID string, before, after any) {
+	if p, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		p.mu.Lock()
+		p.entry.Resource = resource
+		p.entry.ResourceID = resourceID
+		p.entry.Changes = Diff(before, after)
+		p.mu.Unlock()
+	}
+}
+
+// SetAction overrides the action derived from the HTTP method (e.g. "approve", "export")
+func SetAction(ctx context.Context, action string) {
+	if p, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		p.mu.Lock()
+		p.entry.Action = action
+		p.mu.Unlock()
+	}
+}
+
+// Skip prevents the current request from being recorded
+func Skip(ctx context.Context) {
+	if p, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		p.mu.Lock()
+		p.skip = true
+		p.mu.Unlock()
+	}
+}
+
+// Complete merges the values set with Track/SetAction into base and records it
+// Returns false without recording if the request called Skip.
+func Complete(ctx context.Context, store Store, base Entry) (bool, error) {
+	if p, ok := ctx.Value(pendingKey{}).(*pending); ok {
+		p.mu.Lock()
+		skip, tracked := p.skip, p.entry
+		p.mu.Unlock()
+		if skip {
+			return false, nil
+		}
+		if tracked.Action != "" {
+			base.Action = tracked.Action
+		}
+		if tracked.Resource != "" {
+			base.Resource = tracked.Resource
+			base.ResourceID = tracked.ResourceID
+			base.Changes = tracked.Changes
+		}
+	}
+	if base.CreatedAt.IsZero() {
+		base.CreatedAt = time.Now().UTC()
+	}
+	return true, store.Record(ctx, &base)
+}
+
+// ActionForMethod maps HTTP methods to audit actions: POST=create, PUT/PATCH=update, DELETE=delete
+func ActionForMethod(method string) string {
+	switch method {
+	case "POST":
+		return "create"
+	case "PUT", "PATCH":
+		return "update"
+	case "DELETE":
+		return "delete"
+	default:
+		return strings.ToLower(method)
+	}
+}
@@ -0,0 +1,142 @@
+package auditlog
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+	"github.com/yoockh/go-api-utils/pkg/realip"
+	"github.com/yoockh/go-api-utils/pkg/request"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// ActorFunc returns the ID of the authenticated user making the request ("" if anonymous)
+type ActorFunc func(r *http.Request) string
+
+// JWTActor is the default ActorFunc: the user ID set by middleware.JWT
+// Never read the actor from a request header; any client can send one.
+func JWTActor(r *http.Request) string {
+	if id := middleware.UserIDFromContext(r.Context()); id != 0 {
+		return strconv.FormatUint(uint64(id), 10)
+	}
+	return ""
+}
+
+// Middleware records successful (status < 400) POST, PUT, PATCH and DELETE requests
+// Resource defaults to the route pattern; handlers call Track to record the resource ID
+// and before/after diff, SetAction to rename the action, or Skip to opt out.
+// A nil actor means JWTActor, so mount it behind the JWT middleware.
+// Example:
+//
+//	audit := auditlog.Middleware(store, nil)
+//	handler := middleware.JWT(jwtConfig)(audit(mux))
+func Middleware(store Store, actor ActorFunc) func(http.Handler) http.Handler {
+	if actor == nil {
+		actor = JWTActor
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+			r = r.WithContext(Begin(r.Context()))
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.status >= 400 {
+				return
+			}
+
+			resource := r.Pattern
+			if resource == "" {
+				resource = r.URL.Path
+			}
+			e := Entry{
+				Action:    ActionForMethod(r.Method),
+				Resource:  resource,
+				IP:        realip.FromRequest(r),
+				UserAgent: r.UserAgent(),
+				RequestID: logging.RequestID(r.Context()),
+				Status:    rec.status,
+			}
+			e.ActorID = actor(r)
+			if _, err := Complete(r.Context(), store, e); err != nil {
+				log.Printf("audit log error: %v", err)
+			}
+		})
+	}
+}
+
+// Handler serves audit entries as JSON for an admin endpoint
+// Query params: actor_id, action, resource, resource_id, since, until (RFC3339), limit, offset.
+// Protect it with authentication and a role check.
+// Example:
+//
+//	mux.Handle("GET /admin/audit", auditlog.Handler(store))
+func Handler(store Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, err := FilterFromRequest(r)
+		if err != nil {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		entries, total, err := store.Query(r.Context(), f)
+		if err != nil {
+			log.Printf("audit query error: %v", err)
+			response.InternalServerError(w, "failed to query audit log")
+			return
+		}
+		if entries == nil {
+			entries = []Entry{}
+		}
+		response.Success(w, "audit entries retrieved", map[string]interface{}{
+			"entries": entries,
+			"total":   total,
+		})
+	})
+}
+
+// FilterFromRequest builds a Filter from query parameters
+func FilterFromRequest(r *http.Request) (Filter, error) {
+	q := r.URL.Query()
+	f := Filter{
+		ActorID:    q.Get("actor_id"),
+		Action:     q.Get("action"),
+		Resource:   q.Get("resource"),
+		ResourceID: q.Get("resource_id"),
+		Limit:      request.GetQueryParamInt(r, "limit", 50),
+		Offset:     request.GetQueryParamInt(r, "offset", 0),
+	}
+	for name, dst := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		if v := q.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s (expected RFC3339 timestamp)", name)
+			}
+			*dst = t
+		}
+	}
+	return f, nil
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
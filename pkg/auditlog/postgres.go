@@ -0,0 +1,161 @@
+package auditlog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/repository"
+)
+
+// DefaultTable is the table used by NewPostgresStore
+const DefaultTable = "audit_logs"
+
+var columns = []string{"actor_id", "action", "resource", "resource_id", "changes", "ip", "user_agent", "request_id", "status", "created_at"}
+
+// PostgresStore stores audit entries in a Postgres table
+type PostgresStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewPostgresStore creates a store writing to the audit_logs table
+// Example:
+//
+//	store := auditlog.NewPostgresStore(db)
+//	if err := store.CreateTable(ctx); err != nil { ... }
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db, table: DefaultTable}
+}
+
+// WithTable returns a copy of the store using a different table name
+func (s *PostgresStore) WithTable(table string) *PostgresStore {
+	return &PostgresStore{db: s.db, table: table}
+}
+
+// CreateTable creates the audit table and its indexes if they don't exist
+func (s *PostgresStore) CreateTable(ctx context.Context) error {
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	actor_id TEXT NOT NULL DEFAULT '',
+	action TEXT NOT NULL,
+	resource TEXT NOT NULL,
+	resource_id TEXT NOT NULL DEFAULT '',
+	changes JSONB,
+	ip TEXT NOT NULL DEFAULT '',
+	user_agent TEXT NOT NULL DEFAULT '',
+	request_id TEXT NOT NULL DEFAULT '',
+	status INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`, s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %[1]s_resource_idx ON %[1]s (resource, resource_id)", s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %[1]s_actor_idx ON %[1]s (actor_id)", s.table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %[1]s_created_at_idx ON %[1]s (created_at)", s.table),
+	}
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create audit table: %w", err)
+		}
+	}
+	return nil
+}
+
+// Record inserts e and sets its ID
+func (s *PostgresStore) Record(ctx context.Context, e *Entry) error {
+	var changes []byte
+	if len(e.Changes) > 0 {
+		var err error
+		if changes, err = json.Marshal(e.Changes); err != nil {
+			return fmt.Errorf("failed to encode audit changes: %w", err)
+		}
+	}
+	query := repository.BuildInsertQuery(s.table, columns)
+	err := s.db.QueryRowContext(ctx, query,
+		e.ActorID, e.Action, e.Resource, e.ResourceID, changes,
+		e.IP, e.UserAgent, e.RequestID, e.Status, e.CreatedAt,
+	).Scan(&e.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// Query returns entries matching f (newest first) and the total number of matches
+func (s *PostgresStore) Query(ctx context.Context, f Filter) ([]Entry, int64, error) {
+	var conds []string
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.ActorID != "" {
+		add("actor_id = $%d", f.ActorID)
+	}
+	if f.Action != "" {
+		add("action = $%d", f.Action)
+	}
+	if f.Resource != "" {
+		add("resource = $%d", f.Resource)
+	}
+	if f.ResourceID != "" {
+		add("resource_id = $%d", f.ResourceID)
+	}
+	if !f.Since.IsZero() {
+		add("created_at >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("created_at < $%d", f.Until)
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int64
+	countQuery := repository.BuildSelectQuery(s.table, []string{"COUNT(*)"}, where)
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	offset := f.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	query := repository.BuildSelectQuery(s.table, append([]string{"id"}, columns...), where) +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT %d OFFSET %d", limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries, err := repository.ScanRows(rows, scanEntry)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan audit entries: %w", err)
+	}
+	return entries, total, nil
+}
+
+func scanEntry(rows *sql.Rows) (Entry, error) {
+	var e Entry
+	var changes []byte
+	err := rows.Scan(&e.ID, &e.ActorID, &e.Action, &e.Resource, &e.ResourceID, &changes,
+		&e.IP, &e.UserAgent, &e.RequestID, &e.Status, &e.CreatedAt)
+	if err != nil {
+		return e, err
+	}
+	if len(changes) > 0 {
+		if err := json.Unmarshal(changes, &e.Changes); err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
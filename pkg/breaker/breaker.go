@@ -0,0 +1,315 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/clock"
+	"github.com/yoockh/go-api-utils/pkg/metrics"
+)
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	Closed   State = iota // calls pass through; failures are counted
+	Open                  // calls fail fast with ErrOpen until OpenTimeout elapses
+	HalfOpen              // a limited number of trial calls decide whether to close again
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// ErrOpen is returned without calling fn while the breaker is open or the half-open
+// trial slots are taken. It maps to 503 via errs.HTTPStatus.
+var ErrOpen error = openError{}
+
+type openError struct{}
+
+func (openError) Error() string   { return "circuit breaker is open" }
+func (openError) StatusCode() int { return http.StatusServiceUnavailable }
+
+// Config configures a Breaker
+type Config struct {
+	Name string // used in metrics and callbacks
+
+	FailureThreshold int           // consecutive failures that open the breaker (default 5)
+	OpenTimeout      time.Duration // how long to stay open before trying again (default 30s)
+	HalfOpenMaxCalls int           // concurrent trial calls while half-open (default 1)
+	SuccessThreshold int           // successful trials needed to close (default 1)
+
+	// IsFailure decides which errors count against the upstream (default: any error
+	// except context.Canceled, which means our caller went away)
+	IsFailure func(error) bool
+
+	// OnStateChange is called after every transition, outside the breaker's lock
+	OnStateChange func(name string, from, to State)
+}
+
+// Breaker is a circuit breaker guarding calls to one upstream
+type Breaker struct {
+	config Config
+
+	mu        sync.Mutex
+	state     State
+	failures  int // consecutive failures while closed
+	successes int // successful trials while half-open
+	inFlight  int // trial calls running while half-open
+	openedAt  time.Time
+	// generation changes with every transition, so calls that started in an earlier state
+	// don't count against the current one
+	generation uint64
+}
+
+// ticket is handed out by acquire and returned to record
+type ticket struct {
+	trial      bool // one of the half-open trial slots
+	generation uint64
+}
+
+var (
+	calls = metrics.NewCounter("circuit_breaker_calls_total", "Calls through circuit breakers by result (success, failure, rejected)", "name", "result")
+
+	breakersMu sync.Mutex
+	breakers   []*Breaker
+)
+
+// New creates a Breaker
+// Example:
+//
+//	cb := breaker.New(breaker.Config{
+//		Name:             "payments",
+//		FailureThreshold: 5,
+//		OpenTimeout:      30 * time.Second,
+//		OnStateChange: func(name string, from, to breaker.State) {
+//			slog.Warn("circuit breaker", "name", name, "from", from, "to", to)
+//		},
+//	})
+//	metrics.Default.MustRegister(breaker.Collector()) // once, for all breakers
+//
+// Breakers stay in Collector's output until Close is called.
+func New(config Config) *Breaker {
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenMaxCalls < 1 {
+		config.HalfOpenMaxCalls = 1
+	}
+	if config.SuccessThreshold < 1 {
+		config.SuccessThreshold = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool { return !errors.Is(err, context.Canceled) }
+	}
+	b := &Breaker{config: config}
+	breakersMu.Lock()
+	breakers = append(breakers, b)
+	breakersMu.Unlock()
+	return b
+}
+
+// Name returns the configured name
+func (b *Breaker) Name() string {
+	return b.config.Name
+}
+
+// Close removes b from Collector's output
+// Call it for breakers created per host or tenant once they are no longer used, so they
+// can be garbage collected; b keeps working if it is still called.
+// Example:
+//
+//	cb := breaker.New(breaker.Config{Name: "webhook:" + host})
+//	defer cb.Close()
+func (b *Breaker) Close() {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	for i, x := range breakers {
+		if x == b {
+			breakers = append(breakers[:i:i], breakers[i+1:]...)
+			return
+		}
+	}
+}
+
+// State returns the current state, moving from open to half-open once OpenTimeout has elapsed
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	from, to := b.advance()
+	state := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+	return state
+}
+
+// Do calls fn unless the breaker is open, and records the outcome
+// Example:
+//
+//	err := cb.Do(ctx, func(ctx context.Context) error {
+//		return payments.Charge(ctx, order)
+//	})
+//	if errors.Is(err, breaker.ErrOpen) {
+//		response.Error(w, http.StatusServiceUnavailable, "payments temporarily unavailable")
+//		return
+//	}
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	trial, err := b.acquire()
+	if err != nil {
+		calls.Inc(b.config.Name, "rejected")
+		return err
+	}
+	// A panic counts as a failure; otherwise a panicking half-open trial would keep its
+	// slot and the breaker would reject every call from then on
+	defer func() {
+		if p := recover(); p != nil {
+			b.record(trial, fmt.Errorf("panic: %v", p))
+			panic(p)
+		}
+	}()
+	err = fn(ctx)
+	b.record(trial, err)
+	return err
+}
+
+// Call is Do for functions returning a value
+// Example:
+//
+//	rate, err := breaker.Call(ctx, cb, func(ctx context.Context) (float64, error) {
+//		return fx.Rate(ctx, "USD", "IDR")
+//	})
+func Call[T any](ctx context.Context, b *Breaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var out T
+	err := b.Do(ctx, func(ctx context.Context) error {
+		var err error
+		out, err = fn(ctx)
+		return err
+	})
+	return out, err
+}
+
+// acquire reports whether the call may proceed and returns the ticket to record it with
+func (b *Breaker) acquire() (ticket, error) {
+	b.mu.Lock()
+	from, to := b.advance()
+	t := ticket{generation: b.generation}
+	var err error
+	switch b.state {
+	case Open:
+		err = ErrOpen
+	case HalfOpen:
+		if b.inFlight >= b.config.HalfOpenMaxCalls {
+			err = ErrOpen
+		} else {
+			b.inFlight++
+			t.trial = true
+		}
+	}
+	b.mu.Unlock()
+	b.notify(from, to)
+	return t, err
+}
+
+func (b *Breaker) record(t ticket, err error) {
+	failed := err != nil && b.config.IsFailure(err)
+	if failed {
+		calls.Inc(b.config.Name, "failure")
+	} else {
+		calls.Inc(b.config.Name, "success")
+	}
+
+	b.mu.Lock()
+	from := b.state
+	if t.generation != b.generation {
+		// Started before the last transition: a slow call from before the breaker opened
+		// must not trip it again, and its trial slot was already reset
+		b.mu.Unlock()
+		return
+	}
+	if t.trial {
+		b.inFlight--
+	}
+	switch {
+	case b.state == HalfOpen && failed:
+		b.trip()
+	case b.state == HalfOpen && t.trial:
+		b.successes++
+		if b.successes >= b.config.SuccessThreshold {
+			b.reset()
+		}
+	case b.state == Closed && failed:
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.trip()
+		}
+	case b.state == Closed:
+		b.failures = 0
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// advance moves an expired open breaker to half-open; call with mu held
+func (b *Breaker) advance() (from, to State) {
+	from = b.state
+	if b.state == Open && clock.Since(b.openedAt) >= b.config.OpenTimeout {
+		b.state, b.successes, b.inFlight = HalfOpen, 0, 0
+		b.generation++
+	}
+	return from, b.state
+}
+
+func (b *Breaker) trip() {
+	b.state, b.openedAt, b.failures, b.successes = Open, clock.Now(), 0, 0
+	b.generation++
+}
+
+func (b *Breaker) reset() {
+	b.state, b.failures, b.successes = Closed, 0, 0
+	b.generation++
+}
+
+func (b *Breaker) notify(from, to State) {
+	if from != to && b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.config.Name, from, to)
+	}
+}
+
+// Collector exports circuit_breaker_state and circuit_breaker_calls_total for every breaker
+// Register it once; breakers created later are included automatically.
+func Collector() metrics.Collector {
+	return metrics.CollectorFunc(func() []metrics.Family {
+		breakersMu.Lock()
+		bs := append([]*Breaker(nil), breakers...)
+		breakersMu.Unlock()
+
+		gauge := metrics.Family{
+			Name: "circuit_breaker_state",
+			Help: "Circuit breaker state (0 closed, 1 open, 2 half-open)",
+			Type: "gauge",
+		}
+		for _, b := range bs {
+			gauge.Samples = append(gauge.Samples, metrics.Sample{
+				Name:   "circuit_breaker_state",
+				Labels: []metrics.Label{{Name: "name", Value: b.config.Name}},
+				Value:  float64(b.State()),
+			})
+		}
+		return append([]metrics.Family{gauge}, calls.Collect()...)
+	})
+}
@@ -0,0 +1,58 @@
+package breaker
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Transport is an http.RoundTripper that routes requests through a Breaker
+// Connection errors, 429 and 5xx responses count as failures; other statuses are successes.
+// Example:
+//
+//	cb := breaker.New(breaker.Config{Name: "geo"})
+//	httpClient := &http.Client{Transport: breaker.NewTransport(cb, client.NewTransport(nil))}
+type Transport struct {
+	Breaker *Breaker
+	Base    http.RoundTripper // defaults to http.DefaultTransport
+}
+
+// NewTransport wraps base (nil = http.DefaultTransport) with b
+func NewTransport(b *Breaker, base http.RoundTripper) *Transport {
+	return &Transport{Breaker: b, Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	trial, err := t.Breaker.acquire()
+	if err != nil {
+		calls.Inc(t.Breaker.config.Name, "rejected")
+		return nil, err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			t.Breaker.record(trial, fmt.Errorf("panic: %v", p))
+			panic(p)
+		}
+	}()
+	resp, err := base.RoundTrip(req)
+	switch {
+	case err != nil:
+		t.Breaker.record(trial, err)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		t.Breaker.record(trial, statusError(resp.StatusCode))
+	default:
+		t.Breaker.record(trial, nil)
+	}
+	return resp, err
+}
+
+type statusError int
+
+func (e statusError) Error() string {
+	return "HTTP " + strconv.Itoa(int(e))
+}
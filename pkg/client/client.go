@@ -0,0 +1,342 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/breaker"
+	"github.com/yoockh/go-api-utils/pkg/codec"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/options"
+	"github.com/yoockh/go-api-utils/pkg/retry"
+)
+
+// maxErrorBody caps how much of a non-2xx body is kept in Error
+const maxErrorBody = 1 << 20
+
+// Config configures a Client
+type Config struct {
+	BaseURL string            // prefixed to request paths, e.g. "https://inventory.internal/api"
+	Headers map[string]string // sent with every request (e.g. Authorization, User-Agent)
+	Timeout time.Duration     // per attempt; 0 = no timeout beyond the context
+
+	// Attempts is the total number of tries for retryable failures (default 3, 1 disables retries).
+	// Connection errors, 429 and 5xx are retried with exponential backoff.
+	Attempts int
+	Backoff  retry.Option // default retry.ExponentialBackoff(100ms, 2s)
+
+	// RetryNonIdempotent also retries POST and PATCH; only enable it for endpoints
+	// that are safe to repeat (e.g. with an Idempotency-Key header)
+	RetryNonIdempotent bool
+
+	// Transport defaults to NewTransport(nil), which propagates request IDs and traces
+	Transport http.RoundTripper
+
+	// Breaker, when set, guards every attempt: connection errors, 429 and 5xx count as
+	// failures, and an open breaker fails fast with breaker.ErrOpen (not retried)
+	Breaker *breaker.Breaker
+}
+
+// Client is a JSON HTTP client for calling other services
+type Client struct {
+	baseURL    string
+	headers    http.Header
+	timeout    time.Duration
+	attempts   int
+	backoff    retry.Option
+	retryAll   bool
+	breaker    *breaker.Breaker
+	httpClient *http.Client
+	json       codec.Codec
+}
+
+// Envelope decodes the standard {success, message, data} response of services built with this library
+// Example:
+//
+//	var env client.Envelope[Product]
+//	err := c.Get(ctx, "/products/42", &env)
+//	product := env.Data
+type Envelope[T any] struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+	Meta    any    `json:"meta,omitempty"`
+}
+
+// Error is returned for non-2xx responses
+// The message and field errors are read from the standard error envelope when present.
+// It unwraps to errs.ErrNotFound, errs.ErrConflict, errs.ErrValidation or
+// errs.ErrPreconditionFailed for the matching statuses, but as errs.StatusCoder it is
+// always 502: an upstream 401 means our credentials are wrong, not the caller's, so
+// statuses worth passing on are translated explicitly (see Do).
+type Error struct {
+	Status  int // upstream status
+	Method  string
+	URL     string // without query string or password
+	Message string
+	Fields  map[string][]string
+	Body    []byte
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.Status)
+	}
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.Status, msg)
+}
+
+// StatusCode implements errs.StatusCoder: an upstream failure is a bad gateway for our clients
+func (e *Error) StatusCode() int {
+	return http.StatusBadGateway
+}
+
+func (e *Error) Unwrap() error {
+	switch e.Status {
+	case http.StatusNotFound:
+		return errs.ErrNotFound
+	case http.StatusConflict:
+		return errs.ErrConflict
+	case http.StatusUnprocessableEntity:
+		return errs.ErrValidation
+	case http.StatusPreconditionFailed:
+		return errs.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// RequestOption customizes a single request
+type RequestOption func(*http.Request, *requestOptions)
+
+type requestOptions struct {
+	timeout time.Duration
+}
+
+// WithHeader sets a header on one request
+func WithHeader(key, value string) RequestOption {
+	return func(r *http.Request, _ *requestOptions) { r.Header.Set(key, value) }
+}
+
+// WithQuery adds query parameters to one request
+func WithQuery(q url.Values) RequestOption {
+	return func(r *http.Request, _ *requestOptions) {
+		values := r.URL.Query()
+		for k, vs := range q {
+			for _, v := range vs {
+				values.Add(k, v)
+			}
+		}
+		r.URL.RawQuery = values.Encode()
+	}
+}
+
+// WithTimeout overrides Config.Timeout for one request (per attempt)
+func WithTimeout(d time.Duration) RequestOption {
+	return func(_ *http.Request, o *requestOptions) { o.timeout = d }
+}
+
+// New creates a Client
+// Options: options.WithEncoder for request and response bodies (default codec.JSON()) and
+// options.WithTimeout as the per-request timeout when Config.Timeout is zero.
+// Example:
+//
+//	inventory := client.New(client.Config{
+//		BaseURL: os.Getenv("INVENTORY_URL"),
+//		Headers: map[string]string{"Authorization": "Bearer " + os.Getenv("INVENTORY_TOKEN")},
+//		Timeout: 3 * time.Second,
+//	})
+func New(config Config, opts ...options.Option) *Client {
+	o := options.Apply(opts...)
+	if config.Timeout <= 0 {
+		config.Timeout = o.Timeout
+	}
+	headers := http.Header{}
+	for k, v := range config.Headers {
+		headers.Set(k, v)
+	}
+	if config.Attempts < 1 {
+		config.Attempts = 3
+	}
+	if config.Backoff == nil {
+		config.Backoff = retry.ExponentialBackoff(100*time.Millisecond, 2*time.Second)
+	}
+	if config.Transport == nil {
+		config.Transport = NewTransport(nil)
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(config.BaseURL, "/"),
+		headers:    headers,
+		timeout:    config.Timeout,
+		attempts:   config.Attempts,
+		backoff:    config.Backoff,
+		retryAll:   config.RetryNonIdempotent,
+		breaker:    config.Breaker,
+		httpClient: &http.Client{Transport: config.Transport},
+		json:       o.JSON(),
+	}
+}
+
+// Get sends a GET request and decodes the JSON response into out (nil to discard)
+func (c *Client) Get(ctx context.Context, path string, out interface{}, opts ...RequestOption) error {
+	return c.Do(ctx, http.MethodGet, path, nil, out, opts...)
+}
+
+// Post sends body as JSON and decodes the response into out
+func (c *Client) Post(ctx context.Context, path string, body, out interface{}, opts ...RequestOption) error {
+	return c.Do(ctx, http.MethodPost, path, body, out, opts...)
+}
+
+// Put sends body as JSON and decodes the response into out
+func (c *Client) Put(ctx context.Context, path string, body, out interface{}, opts ...RequestOption) error {
+	return c.Do(ctx, http.MethodPut, path, body, out, opts...)
+}
+
+// Patch sends body as JSON and decodes the response into out
+func (c *Client) Patch(ctx context.Context, path string, body, out interface{}, opts ...RequestOption) error {
+	return c.Do(ctx, http.MethodPatch, path, body, out, opts...)
+}
+
+// Delete sends a DELETE request and decodes the response into out (nil to discard)
+func (c *Client) Delete(ctx context.Context, path string, out interface{}, opts ...RequestOption) error {
+	return c.Do(ctx, http.MethodDelete, path, nil, out, opts...)
+}
+
+// Do sends a request with a JSON body (nil for none) and decodes a 2xx JSON response into out
+// Non-2xx responses return *Error; retryable failures are retried per Config.
+// Example:
+//
+//	var env client.Envelope[[]Item]
+//	err := inventory.Do(r.Context(), http.MethodGet, "/items", nil, &env,
+//		client.WithQuery(url.Values{"sku": {sku}}), client.WithTimeout(time.Second))
+//	if errors.Is(err, errs.ErrNotFound) {
+//		return errs.NotFound("item") // upstream 404 becomes ours; other failures stay 502
+//	}
+func (c *Client) Do(ctx context.Context, method, path string, body, out interface{}, opts ...RequestOption) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = c.json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+
+	attempts := c.attempts
+	if !c.retryAll && (method == http.MethodPost || method == http.MethodPatch) {
+		attempts = 1
+	}
+	return retry.Do(ctx, func(ctx context.Context) error {
+		if c.breaker == nil {
+			return c.attempt(ctx, method, path, payload, out, opts)
+		}
+		var result error
+		err := c.breaker.Do(ctx, func(ctx context.Context) error {
+			result = c.attempt(ctx, method, path, payload, out, opts)
+			if retryable(result) {
+				return result // upstream trouble; 4xx and decode errors don't trip the breaker
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return result
+	}, retry.Attempts(attempts), c.backoff, retry.RetryIf(retryable))
+}
+
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, out interface{}, opts []RequestOption) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), body)
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("failed to build request: %w", err))
+	}
+	for k, vs := range c.headers {
+		req.Header[k] = append([]string(nil), vs...)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	ro := requestOptions{timeout: c.timeout}
+	for _, opt := range opts {
+		opt(req, &ro)
+	}
+	if ro.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ro.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newError(req, resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := c.json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return retry.Permanent(fmt.Errorf("failed to decode response from %s %s: %w", method, req.URL.Redacted(), err))
+	}
+	return nil
+}
+
+func (c *Client) url(path string) string {
+	if c.baseURL == "" || strings.Contains(path, "://") {
+		return path
+	}
+	return c.baseURL + "/" + strings.TrimLeft(path, "/")
+}
+
+func newError(req *http.Request, resp *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	// The query may carry keys or personal data and the error text ends up in logs
+	u := *req.URL
+	u.RawQuery, u.Fragment = "", ""
+	e := &Error{Status: resp.StatusCode, Method: req.Method, URL: u.Redacted(), Body: body}
+
+	var env struct {
+		Message string              `json:"message"`
+		Error   string              `json:"error"`
+		Errors  map[string][]string `json:"errors"`
+	}
+	if json.Unmarshal(body, &env) == nil {
+		e.Message = env.Error
+		if e.Message == "" {
+			e.Message = env.Message
+		}
+		e.Fields = env.Errors
+	}
+	return e
+}
+
+// retryable reports whether a failed attempt may succeed if repeated
+func retryable(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status == http.StatusTooManyRequests || apiErr.Status >= 500
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
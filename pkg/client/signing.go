@@ -0,0 +1,157 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set by SigningTransport and checked by middleware.VerifySignature
+const (
+	SignatureHeader          = "X-Signature"           // base64 signature of the canonical string
+	SignatureKeyIDHeader     = "X-Signature-Key-Id"    // which key signed, so keys can be rotated
+	SignatureTimestampHeader = "X-Signature-Timestamp" // Unix seconds
+	SignatureNonceHeader     = "X-Signature-Nonce"     // random per request, for replay protection
+)
+
+// Signer signs canonical request strings; HMACKey and Ed25519Key implement it
+type Signer interface {
+	Sign(message []byte) []byte
+}
+
+// Verifier checks signatures made by the matching Signer; HMACKey and Ed25519PublicKey implement it
+type Verifier interface {
+	Verify(message, signature []byte) bool
+}
+
+// HMACKey is a shared secret signing and verifying with HMAC-SHA256
+type HMACKey []byte
+
+// Sign returns the HMAC-SHA256 of message
+func (k HMACKey) Sign(message []byte) []byte {
+	mac := hmac.New(sha256.New, k)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// Verify compares signature with the HMAC of message in constant time
+func (k HMACKey) Verify(message, signature []byte) bool {
+	return len(k) > 0 && hmac.Equal(k.Sign(message), signature)
+}
+
+// Ed25519Key signs with an Ed25519 private key; the receiving service only needs the
+// public key (Ed25519PublicKey), so it can't sign requests itself
+type Ed25519Key ed25519.PrivateKey
+
+// Sign returns the Ed25519 signature of message
+func (k Ed25519Key) Sign(message []byte) []byte {
+	return ed25519.Sign(ed25519.PrivateKey(k), message)
+}
+
+// Ed25519PublicKey verifies signatures made with Ed25519Key
+type Ed25519PublicKey ed25519.PublicKey
+
+// Verify reports whether signature is a valid Ed25519 signature of message
+func (k Ed25519PublicKey) Verify(message, signature []byte) bool {
+	return len(k) == ed25519.PublicKeySize && ed25519.Verify(ed25519.PublicKey(k), message, signature)
+}
+
+// CanonicalString returns the string signed for a request: method, escaped path, sorted
+// query, timestamp, nonce and the hex SHA-256 of the body, separated by newlines
+// Both SigningTransport and middleware.VerifySignature build it with this function.
+func CanonicalString(r *http.Request, timestamp, nonce string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.URL.EscapedPath(),
+		r.URL.Query().Encode(),
+		timestamp,
+		nonce,
+		hex.EncodeToString(sum[:]),
+	}, "\n")
+}
+
+// SigningTransport signs every outbound request, for service-to-service calls without mTLS
+// The receiving service checks requests with middleware.VerifySignature. Each request,
+// retries included, gets a fresh timestamp and nonce.
+// Example:
+//
+//	payments := client.New(client.Config{
+//		BaseURL:   os.Getenv("PAYMENTS_URL"),
+//		Transport: client.NewSigningTransport(client.NewTransport(nil), "orders-v1", client.HMACKey(os.Getenv("PAYMENTS_SECRET"))),
+//	})
+type SigningTransport struct {
+	Base   http.RoundTripper // defaults to http.DefaultTransport
+	KeyID  string            // sent as X-Signature-Key-Id; optional with a single key
+	Signer Signer
+}
+
+// NewSigningTransport wraps base (nil = http.DefaultTransport) with request signing
+func NewSigningTransport(base http.RoundTripper, keyID string, signer Signer) *SigningTransport {
+	return &SigningTransport{Base: base, KeyID: keyID, Signer: signer}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *SigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(b)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	// RoundTrippers must not modify the caller's request
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+	out.Header.Set(SignatureTimestampHeader, timestamp)
+	out.Header.Set(SignatureNonceHeader, nonce)
+	if t.KeyID != "" {
+		out.Header.Set(SignatureKeyIDHeader, t.KeyID)
+	}
+	sig := t.Signer.Sign([]byte(CanonicalString(out, timestamp, nonce, body)))
+	out.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(sig))
+	return base.RoundTrip(out)
+}
+
+// readBody returns the body of req without consuming the caller's copy when GetBody is set
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	rc := req.Body
+	if req.GetBody != nil {
+		var err error
+		if rc, err = req.GetBody(); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body.Close()
+	}
+	defer rc.Close()
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	return body, nil
+}
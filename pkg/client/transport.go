@@ -0,0 +1,76 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Transport propagates the request ID and trace context of the incoming request
+// to outbound calls, and records a client span for each call.
+// Build outbound requests with the incoming request context for this to work.
+// Example:
+//
+//	httpClient := &http.Client{Transport: client.NewTransport(nil), Timeout: 10 * time.Second}
+//	req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, "https://inventory/api/items", nil)
+//	resp, err := httpClient.Do(req) // carries X-Request-ID and traceparent
+type Transport struct {
+	Base http.RoundTripper // defaults to http.DefaultTransport
+}
+
+// NewTransport wraps base (nil = http.DefaultTransport) with ID and trace propagation
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := tracing.Tracer().Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("url.full", req.URL.Redacted()),
+		),
+	)
+	defer span.End()
+
+	// RoundTrippers must not modify the caller's request
+	out := req.Clone(ctx)
+	Propagate(out)
+
+	resp, err := base.RoundTrip(out)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 500 {
+		tracing.RecordError(span, &statusError{code: resp.StatusCode})
+	}
+	return resp, nil
+}
+
+// Propagate copies the request ID and trace context from the request context into its headers
+// Use this when you can't swap the transport of a third-party client.
+func Propagate(req *http.Request) {
+	if id := logging.RequestID(req.Context()); id != "" && req.Header.Get(logging.RequestIDHeader) == "" {
+		req.Header.Set(logging.RequestIDHeader, id)
+	}
+	tracing.Inject(req.Context(), req.Header)
+}
+
+type statusError struct{ code int }
+
+func (e *statusError) Error() string {
+	return "HTTP " + strconv.Itoa(e.code)
+}
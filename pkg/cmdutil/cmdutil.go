@@ -0,0 +1,216 @@
+package cmdutil
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/yoockh/go-api-utils/pkg/fixtures"
+	"github.com/yoockh/go-api-utils/pkg/migrations"
+)
+
+// ErrUsage is returned for unknown or malformed subcommands
+var ErrUsage = errors.New("usage: migrate up | migrate down [steps] | migrate status | seed")
+
+// Config configures the schema management subcommands
+type Config struct {
+	// DB opens the database; it is only called when a subcommand runs
+	DB func(ctx context.Context) (*sql.DB, error)
+	// Migrations holds the <version>_<name>.up.sql / .down.sql files (see migrations.Load)
+	Migrations fs.FS
+	// MigrationsDir is the directory of Migrations holding the files (default ".")
+	MigrationsDir string
+	// Seeds holds seed data: *.sql files run in name order in one transaction, then
+	// *.yml, *.yaml and *.json fixture files applied with fixtures.Set.Apply
+	Seeds fs.FS
+	// SeedsDir is the directory of Seeds holding the files (default ".")
+	SeedsDir string
+	// Out receives the command output (default os.Stdout)
+	Out io.Writer
+}
+
+// Run handles "migrate up", "migrate down [steps]", "migrate status" and "seed" in args
+// (usually os.Args[1:]) so schema management ships in the same binary that is deployed.
+// handled is false when args hold no subcommand and the application should start normally.
+// Example:
+//
+//	//go:embed migrations/*.sql
+//	var migrationFS embed.FS
+//
+//	//go:embed seeds
+//	var seedFS embed.FS
+//
+//	func main() {
+//		handled, err := cmdutil.Run(context.Background(), os.Args[1:], cmdutil.Config{
+//			DB:            func(ctx context.Context) (*sql.DB, error) { return database.ConnectPostgresURL(cfg.DatabaseURL) },
+//			Migrations:    migrationFS,
+//			MigrationsDir: "migrations",
+//			Seeds:         seedFS,
+//			SeedsDir:      "seeds",
+//		})
+//		if handled {
+//			if err != nil {
+//				log.Fatal(err)
+//			}
+//			return
+//		}
+//		// start the server
+//	}
+//
+//	$ ./app migrate up
+//	$ ./app migrate down 2
+//	$ ./app migrate status
+//	$ ./app seed
+func Run(ctx context.Context, args []string, cfg Config) (handled bool, err error) {
+	if len(args) == 0 || (args[0] != "migrate" && args[0] != "seed") {
+		return false, nil
+	}
+	if cfg.DB == nil {
+		return true, errors.New("cmdutil: Config.DB is required")
+	}
+	if cfg.Out == nil {
+		cfg.Out = os.Stdout
+	}
+
+	switch args[0] {
+	case "migrate":
+		if len(args) < 2 {
+			return true, ErrUsage
+		}
+		return true, migrate(ctx, cfg, args[1], args[2:])
+	default:
+		if len(args) > 1 {
+			return true, ErrUsage
+		}
+		return true, seed(ctx, cfg)
+	}
+}
+
+func migrate(ctx context.Context, cfg Config, cmd string, rest []string) error {
+	steps := 1
+	switch {
+	case cmd == "down" && len(rest) == 1:
+		n, err := strconv.Atoi(rest[0])
+		if err != nil || n < 1 {
+			return fmt.Errorf("%w: steps must be a positive number", ErrUsage)
+		}
+		steps = n
+	case cmd != "up" && cmd != "down" && cmd != "status", len(rest) > 0:
+		return ErrUsage
+	}
+	if cfg.Migrations == nil {
+		return errors.New("cmdutil: Config.Migrations is required")
+	}
+
+	db, err := cfg.DB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+	m, err := migrations.New(db, cfg.Migrations, dirOrRoot(cfg.MigrationsDir))
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mig := range applied {
+			fmt.Fprintf(cfg.Out, "applied %d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(cfg.Out, "no pending migrations")
+		}
+		return err
+	case "down":
+		reverted, err := m.Down(ctx, steps)
+		for _, mig := range reverted {
+			fmt.Fprintf(cfg.Out, "reverted %d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Fprintln(cfg.Out, "no applied migrations")
+		}
+		return err
+	default:
+		list, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(cfg.Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+		for _, s := range list {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, state)
+		}
+		return w.Flush()
+	}
+}
+
+func seed(ctx context.Context, cfg Config) error {
+	if cfg.Seeds == nil {
+		return errors.New("cmdutil: Config.Seeds is required")
+	}
+	seeds, err := fs.Sub(cfg.Seeds, dirOrRoot(cfg.SeedsDir))
+	if err != nil {
+		return err
+	}
+	scripts, err := fs.Glob(seeds, "*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(scripts)
+	set, err := fixtures.Load(seeds)
+	if err != nil {
+		return err
+	}
+
+	db, err := cfg.DB(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin seed transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for _, name := range scripts {
+		data, err := fs.ReadFile(seeds, name)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, string(data)); err != nil {
+			return fmt.Errorf("seed %s: %w", name, err)
+		}
+		fmt.Fprintf(cfg.Out, "ran %s\n", name)
+	}
+	if tables := set.Tables(); len(tables) > 0 {
+		if err := set.Apply(ctx, tx); err != nil {
+			return err
+		}
+		fmt.Fprintf(cfg.Out, "loaded fixtures into %s\n", strings.Join(tables, ", "))
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit seed data: %w", err)
+	}
+	return nil
+}
+
+func dirOrRoot(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
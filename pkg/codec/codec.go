@@ -0,0 +1,67 @@
+package codec
+
+import (
+	"encoding/json"
+	"io"
+	"sync/atomic"
+)
+
+// Codec is a JSON implementation; Std wraps encoding/json and pkg/codec/gojson wraps
+// github.com/goccy/go-json
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder writes JSON values to a stream
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads JSON values from a stream
+type Decoder interface {
+	Decode(v any) error
+	DisallowUnknownFields()
+}
+
+// Std is the encoding/json Codec, used unless SetJSON installs another
+var Std Codec = stdCodec{}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (stdCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (stdCodec) NewEncoder(w io.Writer) Encoder     { return json.NewEncoder(w) }
+func (stdCodec) NewDecoder(r io.Reader) Decoder     { return json.NewDecoder(r) }
+
+var current atomic.Pointer[Codec]
+
+// SetJSON replaces the JSON implementation used by pkg/response and request.ParseJSON
+// Call it once at startup, before serving requests; nil restores Std.
+// Example:
+//
+//	codec.SetJSON(gojson.Codec) // github.com/yoockh/go-api-utils/pkg/codec/gojson
+//
+//	// any other library, e.g. segmentio/encoding/json, with a small adapter:
+//	type segmentio struct{}
+//	func (segmentio) Marshal(v any) ([]byte, error)             { return sjson.Marshal(v) }
+//	func (segmentio) Unmarshal(data []byte, v any) error        { return sjson.Unmarshal(data, v) }
+//	func (segmentio) NewEncoder(w io.Writer) codec.Encoder      { return sjson.NewEncoder(w) }
+//	func (segmentio) NewDecoder(r io.Reader) codec.Decoder      { return sjson.NewDecoder(r) }
+//	codec.SetJSON(segmentio{})
+func SetJSON(c Codec) {
+	if c == nil {
+		c = Std
+	}
+	current.Store(&c)
+}
+
+// JSON returns the JSON implementation set by SetJSON, Std by default
+func JSON() Codec {
+	if c := current.Load(); c != nil {
+		return *c
+	}
+	return Std
+}
@@ -0,0 +1,22 @@
+package gojson
+
+import (
+	"io"
+
+	"github.com/goccy/go-json"
+	"github.com/yoockh/go-api-utils/pkg/codec"
+)
+
+// Codec is a codec.Codec backed by github.com/goccy/go-json, a drop-in encoding/json
+// replacement that is several times faster on large responses
+// Example:
+//
+//	codec.SetJSON(gojson.Codec)
+var Codec codec.Codec = goJSON{}
+
+type goJSON struct{}
+
+func (goJSON) Marshal(v any) ([]byte, error)        { return json.Marshal(v) }
+func (goJSON) Unmarshal(data []byte, v any) error   { return json.Unmarshal(data, v) }
+func (goJSON) NewEncoder(w io.Writer) codec.Encoder { return json.NewEncoder(w) }
+func (goJSON) NewDecoder(r io.Reader) codec.Decoder { return json.NewDecoder(r) }
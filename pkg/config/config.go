@@ -1,56 +1,214 @@
 package config
 
 import (
+	"cmp"
 	"log"
+	"net/http"
 	"os"
-
-	"github.com/joho/godotenv"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds application configuration
+// The env tags name the variables each field is read from (used by Dump).
 type Config struct {
-	Port        string
-	DatabaseURL string
-	DBHost      string
-	DBPort      string
-	DBUser      string
-	DBPassword  string
-	DBName      string
-	DBSSLMode   string
+	Port        string `env:"PORT"`
+	DatabaseURL string `env:"DATABASE_URL"`
+	DBHost      string `env:"DB_HOST"`
+	DBPort      string `env:"DB_PORT"`
+	DBUser      string `env:"DB_USER"`
+	DBPassword  string `env:"DB_PASSWORD"`
+	DBName      string `env:"DB_NAME"`
+	DBSSLMode   string `env:"DB_SSL_MODE"`
+	// DBQueryTimeout is the default query timeout from DB_QUERY_TIMEOUT (e.g. "5s"), 0 when unset.
+	// See database.SetQueryTimeout.
+	DBQueryTimeout time.Duration `env:"DB_QUERY_TIMEOUT"`
+	// Timeouts holds named operation timeouts from TIMEOUT_<NAME> variables,
+	// e.g. TIMEOUT_DB=2s -> Timeouts["db"]. See request.SetTimeouts.
+	Timeouts map[string]time.Duration
+
+	Server ServerConfig
+	JWT    JWTConfig
+	Redis  RedisConfig
+	SMTP   SMTPConfig
+}
+
+// ServerConfig holds HTTP server timeouts from SERVER_* variables
+// The defaults match graceful.NewServer.
+type ServerConfig struct {
+	ReadTimeout       time.Duration `env:"SERVER_READ_TIMEOUT"`        // default 30s
+	ReadHeaderTimeout time.Duration `env:"SERVER_READ_HEADER_TIMEOUT"` // default 10s
+	WriteTimeout      time.Duration `env:"SERVER_WRITE_TIMEOUT"`       // default 30s
+	IdleTimeout       time.Duration `env:"SERVER_IDLE_TIMEOUT"`        // default 2m
+	ShutdownTimeout   time.Duration `env:"SERVER_SHUTDOWN_TIMEOUT"`    // default 30s, the drain deadline for graceful.ListenAndServe
+}
+
+// Apply sets the server's timeouts
+// Example:
+//
+//	srv := graceful.NewServer(":"+cfg.Port, handler)
+//	cfg.Server.Apply(srv)
+//	err := graceful.ListenAndServe(ctx, srv, cfg.Server.ShutdownTimeout)
+func (c ServerConfig) Apply(srv *http.Server) {
+	srv.ReadTimeout = c.ReadTimeout
+	srv.ReadHeaderTimeout = c.ReadHeaderTimeout
+	srv.WriteTimeout = c.WriteTimeout
+	srv.IdleTimeout = c.IdleTimeout
 }
 
-// LoadEnv loads environment variables from .env file and returns Config
+// JWTConfig holds token settings for the auth and middleware packages
+type JWTConfig struct {
+	Secret string        `env:"JWT_SECRET"`
+	Expiry time.Duration `env:"JWT_EXPIRY"` // default 24h, the lifetime passed to auth.GenerateToken
+}
+
+// RedisConfig holds the Redis connection settings (hub.RedisConfig, caches)
+type RedisConfig struct {
+	Addr     string `env:"REDIS_ADDR"`     // default "localhost:6379"
+	Username string `env:"REDIS_USERNAME"` // for ACL users
+	Password string `env:"REDIS_PASSWORD"`
+	DB       int    `env:"REDIS_DB"`
+}
+
+// SMTPConfig holds the mail server settings (notifications.SMTPConfig)
+type SMTPConfig struct {
+	Host     string `env:"SMTP_HOST"`
+	Port     string `env:"SMTP_PORT"` // default "587"
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD"`
+	From     string `env:"SMTP_FROM"`
+}
+
+// LoadEnv loads environment variables from .env files and returns Config
 // Use this at app startup to load configuration
+// Files are layered by the APP_ENV profile: .env, .env.local, .env.<APP_ENV> and
+// .env.<APP_ENV>.local, later files overriding earlier ones; variables already set in the
+// environment override them all. Values from a remote source (SetRemoteSource) override
+// the environment.
+// Secret references such as DB_PASSWORD=vault:secret/data/db#password are fetched from
+// the secret manager (see SecretProvider); failures are logged and the default is used.
 // Example:
 //
 //	config := LoadEnv()
 //	db, _ := database.ConnectPostgresURL(config.DatabaseURL)
+//	token, _ := auth.GenerateToken(user.ID, user.Email, user.Role, config.JWT.Secret, config.JWT.Expiry)
 func LoadEnv() *Config {
-	// Try to load .env file (ignore error if not exists)
-	if err := godotenv.Load(); err != nil {
+	// Load .env and the APP_ENV profile files (ignore them if they don't exist)
+	if files := loadDotenv(); len(files) == 0 {
 		log.Println("No .env file found, using system environment variables")
 	}
 
+	env := newSecretResolver(lookupEnv(true)).lookup
 	return &Config{
-		Port:        getEnv("PORT", "8080"),
-		DatabaseURL: getEnv("DATABASE_URL", ""),
-		DBHost:      getEnv("DB_HOST", "localhost"),
-		DBPort:      getEnv("DB_PORT", "5432"),
-		DBUser:      getEnv("DB_USER", "postgres"),
-		DBPassword:  getEnv("DB_PASSWORD", ""),
-		DBName:      getEnv("DB_NAME", "mydb"),
-		DBSSLMode:   getEnv("DB_SSL_MODE", "disable"),
+		Port:           getEnv(env, "PORT", "8080"),
+		DatabaseURL:    getEnv(env, "DATABASE_URL", ""),
+		DBHost:         getEnv(env, "DB_HOST", "localhost"),
+		DBPort:         getEnv(env, "DB_PORT", "5432"),
+		DBUser:         getEnv(env, "DB_USER", "postgres"),
+		DBPassword:     getEnv(env, "DB_PASSWORD", ""),
+		DBName:         getEnv(env, "DB_NAME", "mydb"),
+		DBSSLMode:      getEnv(env, "DB_SSL_MODE", "disable"),
+		DBQueryTimeout: getEnvDuration(env, "DB_QUERY_TIMEOUT"),
+		Timeouts:       loadTimeouts(),
+		Server: ServerConfig{
+			ReadTimeout:       getEnvDurationOr(env, "SERVER_READ_TIMEOUT", 30*time.Second),
+			ReadHeaderTimeout: getEnvDurationOr(env, "SERVER_READ_HEADER_TIMEOUT", 10*time.Second),
+			WriteTimeout:      getEnvDurationOr(env, "SERVER_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       getEnvDurationOr(env, "SERVER_IDLE_TIMEOUT", 2*time.Minute),
+			ShutdownTimeout:   getEnvDurationOr(env, "SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+		},
+		JWT: JWTConfig{
+			Secret: getEnv(env, "JWT_SECRET", ""),
+			Expiry: getEnvDurationOr(env, "JWT_EXPIRY", 24*time.Hour),
+		},
+		Redis: RedisConfig{
+			Addr:     getEnv(env, "REDIS_ADDR", "localhost:6379"),
+			Username: getEnv(env, "REDIS_USERNAME", ""),
+			Password: getEnv(env, "REDIS_PASSWORD", ""),
+			DB:       getEnvInt(env, "REDIS_DB", 0),
+		},
+		SMTP: SMTPConfig{
+			Host:     getEnv(env, "SMTP_HOST", ""),
+			Port:     getEnv(env, "SMTP_PORT", "587"),
+			Username: getEnv(env, "SMTP_USERNAME", ""),
+			Password: getEnv(env, "SMTP_PASSWORD", ""),
+			From:     getEnv(env, "SMTP_FROM", ""),
+		},
 	}
 }
 
+// loadTimeouts collects TIMEOUT_<NAME>=<duration> variables (remote ones override local ones);
+// invalid durations are logged and skipped
+func loadTimeouts() map[string]time.Duration {
+	timeouts := map[string]time.Duration{}
+	environ := os.Environ()
+	for k, v := range remoteValues(false) {
+		environ = append(environ, k+"="+v)
+	}
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(key, "TIMEOUT_")
+		if !ok || name == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Printf("config: ignoring %s=%q: not a positive duration", key, value)
+			continue
+		}
+		timeouts[strings.ToLower(name)] = d
+	}
+	return timeouts
+}
+
 // getEnv retrieves environment variable or returns default value
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+// Unresolvable secret references are logged and fall back to the default.
+func getEnv(env lookupFunc, key, defaultValue string) string {
+	value, _, err := env(key)
+	if err != nil {
+		log.Printf("config: ignoring %s: %v", key, err)
+		return defaultValue
+	}
+	if value != "" {
 		return value
 	}
 	return defaultValue
 }
 
+// getEnvDuration parses a duration variable; unset or invalid values return 0 (invalid ones are logged)
+func getEnvDuration(env lookupFunc, key string) time.Duration {
+	value := getEnv(env, key, "")
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Printf("config: ignoring %s=%q: not a positive duration", key, value)
+		return 0
+	}
+	return d
+}
+
+// getEnvDurationOr is getEnvDuration with a default for unset, invalid and zero values
+func getEnvDurationOr(env lookupFunc, key string, def time.Duration) time.Duration {
+	return cmp.Or(getEnvDuration(env, key), def)
+}
+
+// getEnvInt parses an integer variable; unset or invalid values return def (invalid ones are logged)
+func getEnvInt(env lookupFunc, key string, def int) int {
+	value := getEnv(env, key, "")
+	if value == "" {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("config: ignoring %s=%q: not an integer", key, value)
+		return def
+	}
+	return n
+}
+
 // MustLoadEnv loads config and panics if DATABASE_URL is not set
 // Use this when database is required for app to run
 // Example:
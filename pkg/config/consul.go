@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ConsulConfig configures the Consul KV remote source
+type ConsulConfig struct {
+	Address    string // default CONSUL_HTTP_ADDR or http://127.0.0.1:8500
+	Token      string // ACL token (default CONSUL_HTTP_TOKEN)
+	Datacenter string // optional
+	Prefix     string // key prefix of this service, e.g. "services/orders/"
+	Client     *http.Client
+}
+
+// Consul reads configuration from Consul KV with one recursive read of Prefix
+// Keys below the prefix become env names: "services/orders/db/host" -> DB_HOST.
+type Consul struct {
+	config ConsulConfig
+}
+
+// NewConsul creates a Consul KV remote source
+// Example:
+//
+//	consul, _ := config.NewConsul(config.ConsulConfig{Prefix: "services/orders/"})
+//	config.SetRemoteSource(consul)
+func NewConsul(config ConsulConfig) (*Consul, error) {
+	if config.Address == "" {
+		config.Address = firstEnv("CONSUL_HTTP_ADDR")
+	}
+	if config.Address == "" {
+		config.Address = "http://127.0.0.1:8500"
+	}
+	if !strings.Contains(config.Address, "://") {
+		config.Address = "http://" + config.Address // CONSUL_HTTP_ADDR is often host:port
+	}
+	config.Address = strings.TrimRight(config.Address, "/")
+	if _, err := url.Parse(config.Address); err != nil {
+		return nil, fmt.Errorf("invalid consul address: %w", err)
+	}
+	if config.Token == "" {
+		config.Token = firstEnv("CONSUL_HTTP_TOKEN")
+	}
+	config.Prefix = strings.TrimLeft(config.Prefix, "/")
+	if config.Client == nil {
+		config.Client = httpClient
+	}
+	return &Consul{config: config}, nil
+}
+
+// Values implements RemoteSource
+func (c *Consul) Values(ctx context.Context) (map[string]string, error) {
+	q := url.Values{"recurse": {"true"}}
+	if c.config.Datacenter != "" {
+		q.Set("dc", c.config.Datacenter)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.Address+"/v1/kv/"+c.config.Prefix+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.Token != "" {
+		req.Header.Set("X-Consul-Token", c.config.Token)
+	}
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return map[string]string{}, nil // nothing stored under the prefix yet
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("consul returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var pairs []struct {
+		Key   string
+		Value *string // base64, null for folders
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		return nil, fmt.Errorf("invalid consul response: %w", err)
+	}
+	values := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		key := remoteKey(strings.TrimPrefix(p.Key, c.config.Prefix))
+		if p.Value == nil || key == "" {
+			continue
+		}
+		v, err := base64.StdEncoding.DecodeString(*p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid consul value for %s: %w", p.Key, err)
+		}
+		values[key] = string(v)
+	}
+	return values, nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// EtcdConfig configures the etcd remote source
+type EtcdConfig struct {
+	Endpoint string // default ETCD_ENDPOINT or http://127.0.0.1:2379
+	Username string // for etcd auth, optional
+	Password string
+	Prefix   string // key prefix of this service, e.g. "/services/orders/"
+	Client   *http.Client
+}
+
+// Etcd reads configuration from etcd v3 through its JSON gateway with one range read of Prefix
+// Keys below the prefix become env names: "/services/orders/db/host" -> DB_HOST.
+type Etcd struct {
+	config EtcdConfig
+}
+
+// NewEtcd creates an etcd remote source
+// Example:
+//
+//	etcd, _ := config.NewEtcd(config.EtcdConfig{Endpoint: "http://etcd:2379", Prefix: "/services/orders/"})
+//	config.SetRemoteSource(etcd)
+func NewEtcd(config EtcdConfig) (*Etcd, error) {
+	if config.Endpoint == "" {
+		config.Endpoint = firstEnv("ETCD_ENDPOINT")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "http://127.0.0.1:2379"
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.Prefix == "" {
+		return nil, fmt.Errorf("etcd prefix must be set")
+	}
+	if config.Client == nil {
+		config.Client = httpClient
+	}
+	return &Etcd{config: config}, nil
+}
+
+// Values implements RemoteSource
+func (e *Etcd) Values(ctx context.Context) (map[string]string, error) {
+	token := ""
+	if e.config.Username != "" {
+		var auth struct {
+			Token string `json:"token"`
+		}
+		err := e.post(ctx, "/v3/auth/authenticate", "", map[string]string{
+			"name": e.config.Username, "password": e.config.Password,
+		}, &auth)
+		if err != nil {
+			return nil, fmt.Errorf("etcd authentication failed: %w", err)
+		}
+		token = auth.Token
+	}
+
+	// range_end is the prefix with its last byte incremented: every key starting with it
+	end := []byte(e.config.Prefix)
+	end[len(end)-1]++
+	var out struct {
+		Kvs []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"kvs"`
+	}
+	err := e.post(ctx, "/v3/kv/range", token, map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(e.config.Prefix)),
+		"range_end": base64.StdEncoding.EncodeToString(end),
+	}, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(out.Kvs))
+	for _, kv := range out.Kvs {
+		k, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid etcd key: %w", err)
+		}
+		v, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid etcd value for %s: %w", k, err)
+		}
+		if key := remoteKey(strings.TrimPrefix(string(k), e.config.Prefix)); key != "" {
+			values[key] = string(v)
+		}
+	}
+	return values, nil
+}
+
+func (e *Etcd) post(ctx context.Context, path, token string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := e.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("etcd returned %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid etcd response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var flagOverrides struct {
+	mu     sync.RWMutex
+	values map[string]string // env name -> value of flags given on the command line
+}
+
+// LoadWithFlags is LoadEnv with command-line overrides
+// Every Config variable gets a flag on flag.CommandLine named after it (PORT -> --port,
+// DATABASE_URL -> --database-url, SERVER_WRITE_TIMEOUT -> --server-write-timeout), and
+// the command line is parsed unless the program already did. Precedence is flag > remote
+// source > environment > .env files > default; flags the program defines itself with the
+// same name are left alone. The overrides also apply to Load, the Get helpers and Watch
+// reloads. Run with -h to list them.
+// Example:
+//
+//	// go run . --port 9090 --database-url postgres://localhost/dev
+//	cfg := config.LoadWithFlags()
+func LoadWithFlags() *Config {
+	if !flag.Parsed() {
+		registered := registerFlags(flag.CommandLine)
+		flag.Parse()
+		setFlagOverrides(flag.CommandLine, registered)
+	}
+	return LoadEnv()
+}
+
+// LoadWithFlagSet is LoadWithFlags for a custom flag set and arguments (subcommands, tests)
+// It registers the Config flags on fs, parses args and returns the parse error, if any.
+// Example:
+//
+//	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+//	cfg, err := config.LoadWithFlagSet(fs, os.Args[2:])
+func LoadWithFlagSet(fs *flag.FlagSet, args []string) (*Config, error) {
+	registered := registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	setFlagOverrides(fs, registered)
+	return LoadEnv(), nil
+}
+
+// registerFlags defines a string flag per Config variable and returns flag name -> env name
+func registerFlags(fs *flag.FlagSet) map[string]string {
+	registered := map[string]string{}
+	for _, key := range envKeys(reflect.TypeOf(Config{}), "") {
+		name := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+		if fs.Lookup(name) != nil {
+			continue // the program's own flag wins
+		}
+		fs.String(name, "", "overrides $"+key)
+		registered[name] = key
+	}
+	return registered
+}
+
+// setFlagOverrides records the registered flags that were given on the command line
+func setFlagOverrides(fs *flag.FlagSet, registered map[string]string) {
+	values := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		if key, ok := registered[f.Name]; ok {
+			values[key] = f.Value.String()
+		}
+	})
+	flagOverrides.mu.Lock()
+	flagOverrides.values = values
+	flagOverrides.mu.Unlock()
+}
+
+func flagValues() map[string]string {
+	flagOverrides.mu.RLock()
+	defer flagOverrides.mu.RUnlock()
+	return flagOverrides.values
+}
+
+// envKeys lists the env names of the tagged fields of t, nested structs included
+func envKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag, hasTag := sf.Tag.Lookup("env")
+		if !hasTag {
+			if p, ok := sf.Tag.Lookup("envPrefix"); ok || isNestedStruct(sf.Type) {
+				ft := sf.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				keys = append(keys, envKeys(ft, prefix+p)...)
+			}
+			continue
+		}
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			keys = append(keys, prefix+name)
+		}
+	}
+	return keys
+}
+
+// lookupEnv returns the environment lookup with flags and remote values taking precedence
+func lookupEnv(refresh bool) func(key string) (string, bool) {
+	values, flags := remoteValues(refresh), flagValues()
+	if len(values) == 0 && len(flags) == 0 {
+		return os.LookupEnv
+	}
+	return func(key string) (string, bool) {
+		if v, ok := flags[key]; ok {
+			return v, true
+		}
+		if v, ok := values[key]; ok {
+			return v, true
+		}
+		return os.LookupEnv(key)
+	}
+}
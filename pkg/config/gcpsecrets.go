@@ -0,0 +1,151 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/clock"
+)
+
+// GCPSecretsConfig configures the GCP Secret Manager secret provider
+type GCPSecretsConfig struct {
+	Project string // for short references (GOOGLE_CLOUD_PROJECT)
+	// AccessToken is a static OAuth2 token (GOOGLE_OAUTH_ACCESS_TOKEN). When empty, tokens
+	// for the attached service account come from the metadata server (GCE, GKE, Cloud Run).
+	AccessToken string
+	Endpoint    string // default https://secretmanager.googleapis.com
+	Client      *http.Client
+}
+
+// GCPSecretManager reads secrets with the Secret Manager REST API
+// Paths are full version names ("gcpsm:projects/p/secrets/db/versions/3"), or short
+// forms resolved against Project: "gcpsm:db" (latest version) and "gcpsm:db/versions/3".
+// A secret holding a JSON object supports "#field".
+type GCPSecretManager struct {
+	config GCPSecretsConfig
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewGCPSecretManager creates a GCP Secret Manager secret provider
+// Example:
+//
+//	sm, _ := config.NewGCPSecretManager(config.GCPSecretsConfig{Project: "my-project"})
+//	config.RegisterSecretProvider("gcpsm", sm)
+func NewGCPSecretManager(config GCPSecretsConfig) (*GCPSecretManager, error) {
+	if config.Endpoint == "" {
+		config.Endpoint = "https://secretmanager.googleapis.com"
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.Client == nil {
+		config.Client = httpClient
+	}
+	return &GCPSecretManager{config: config}, nil
+}
+
+func gcpSecretsFromEnv() (SecretProvider, error) {
+	return NewGCPSecretManager(GCPSecretsConfig{
+		Project:     firstEnv("GOOGLE_CLOUD_PROJECT", "GCP_PROJECT"),
+		AccessToken: firstEnv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+	})
+}
+
+// Secret implements SecretProvider
+func (g *GCPSecretManager) Secret(ctx context.Context, path string) (string, error) {
+	name := strings.Trim(path, "/")
+	if !strings.HasPrefix(name, "projects/") {
+		if g.config.Project == "" {
+			return "", errors.New("short gcpsm references need a project (GOOGLE_CLOUD_PROJECT)")
+		}
+		if !strings.Contains(name, "/versions/") {
+			name += "/versions/latest"
+		}
+		name = "projects/" + g.config.Project + "/secrets/" + name
+	}
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.config.Endpoint+"/v1/"+name+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := g.config.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrSecretNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("secret manager returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("invalid secret manager response: %w", err)
+	}
+	b, err := base64.StdEncoding.DecodeString(out.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret payload: %w", err)
+	}
+	return string(b), nil
+}
+
+// metadataTokenURL serves tokens for the service account attached to the instance
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// accessToken returns the static token or a cached metadata server token
+func (g *GCPSecretManager) accessToken(ctx context.Context) (string, error) {
+	if g.config.AccessToken != "" {
+		return g.config.AccessToken, nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && clock.Now().Before(g.expires) {
+		return g.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := g.config.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("no GCP credentials: set GOOGLE_OAUTH_ACCESS_TOKEN or run on GCP (%w)", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %d", resp.StatusCode)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil || tok.AccessToken == "" {
+		return "", errors.New("invalid metadata server token response")
+	}
+	g.token = tok.AccessToken
+	// refresh a minute early so a token never expires mid-request
+	g.expires = clock.Now().Add(time.Duration(tok.ExpiresIn)*time.Second - time.Minute)
+	return g.token, nil
+}
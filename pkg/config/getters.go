@@ -0,0 +1,72 @@
+package config
+
+import (
+	"log"
+	"reflect"
+	"time"
+)
+
+// GetString returns the variable key, or def when it is unset or empty
+// Remote sources and secret references are resolved like in LoadEnv.
+// Example:
+//
+//	region := config.GetString("AWS_REGION", "us-east-1")
+func GetString(key, def string) string {
+	return getEnv(newSecretResolver(lookupEnv(false)).lookup, key, def)
+}
+
+// GetInt returns the variable key as an int, or def when it is unset, empty or not an integer
+// Invalid values are logged so a typo doesn't go unnoticed.
+// Example:
+//
+//	workers := config.GetInt("WORKERS", 4)
+func GetInt(key string, def int) int {
+	return getTyped(key, def)
+}
+
+// GetBool returns the variable key as a bool (true/false/1/0/t/f), or def when it is unset,
+// empty or invalid
+// Example:
+//
+//	if config.GetBool("FEATURE_NEW_CHECKOUT", false) {
+//	    mux.Handle("POST /checkout", newCheckout)
+//	}
+func GetBool(key string, def bool) bool {
+	return getTyped(key, def)
+}
+
+// GetDuration returns the variable key as a time.Duration ("30s", "5m"), or def when it is
+// unset, empty or invalid
+// Example:
+//
+//	client := &http.Client{Timeout: config.GetDuration("HTTP_TIMEOUT", 15*time.Second)}
+func GetDuration(key string, def time.Duration) time.Duration {
+	return getTyped(key, def)
+}
+
+// GetStringSlice splits the variable key on commas, trimming spaces and dropping empty
+// items; def is returned when it is unset or empty
+// Example:
+//
+//	origins := config.GetStringSlice("CORS_ORIGINS", []string{"http://localhost:3000"})
+func GetStringSlice(key string, def []string) []string {
+	v := getTyped(key, def)
+	if len(v) == 0 {
+		return def // "," alone
+	}
+	return v
+}
+
+// getTyped parses key with the same rules as Load
+func getTyped[T any](key string, def T) T {
+	raw := GetString(key, "")
+	if raw == "" {
+		return def
+	}
+	var v T
+	if err := setValue(reflect.ValueOf(&v).Elem(), raw, ","); err != nil {
+		log.Printf("config: ignoring %s=%q: %v", key, raw, err)
+		return def
+	}
+	return v
+}
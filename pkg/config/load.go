@@ -0,0 +1,206 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Load fills the struct pointed to by v from environment variables (and the .env files of the
+// APP_ENV profile, layered as in LoadEnv)
+// Fields are bound with struct tags:
+//
+//	env:"NAME"            the variable to read; add ",required" to fail when it is unset or empty
+//	default:"value"       used when the variable is unset or empty
+//	sep:";"               separator for slices (default ",")
+//	envPrefix:"DB_"       on a nested struct field: prefix for the env names of its fields
+//
+// Supported types are strings, bools, ints, uints, floats, time.Duration, slices of those,
+// pointers to them, and types implementing encoding.TextUnmarshaler. Fields without an env
+// tag are left alone. Secret references such as
+// "vault:secret/data/db#password" are fetched first, see SecretProvider. All problems are
+// reported together, one line per field.
+// Example:
+//
+//	type AppConfig struct {
+//	    Port        int           `env:"PORT" default:"8080"`
+//	    DatabaseURL string        `env:"DATABASE_URL,required"`
+//	    Timeout     time.Duration `env:"HTTP_TIMEOUT" default:"15s"`
+//	    Debug       bool          `env:"DEBUG"`
+//	    Origins     []string      `env:"CORS_ORIGINS" default:"http://localhost:3000"`
+//	    Redis       struct {
+//	        Addr string `env:"ADDR" default:"localhost:6379"`
+//	        DB   int    `env:"DB"`
+//	    } `envPrefix:"REDIS_"`
+//	}
+//
+//	var cfg AppConfig
+//	if err := config.Load(&cfg); err != nil {
+//	    log.Fatal(err) // config: DATABASE_URL is required
+//	}
+func Load(v interface{}) error {
+	loadDotenv() // missing .env files are fine, the environment may be complete
+	return bind(v, newSecretResolver(lookupEnv(true)).lookup)
+}
+
+// MustLoad is Load that stops the program when the configuration is invalid
+// Example:
+//
+//	var cfg AppConfig
+//	config.MustLoad(&cfg)
+func MustLoad(v interface{}) {
+	if err := Load(v); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// lookupFunc returns the value of an environment variable and whether it is set
+// err reports a secret reference that could not be resolved.
+type lookupFunc func(key string) (value string, ok bool, err error)
+
+// bind fills v from lookup; v must be a non-nil pointer to a struct
+func bind(v interface{}, lookup lookupFunc) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: Load needs a non-nil pointer to a struct, got %T", v)
+	}
+	return errors.Join(bindStruct(rv.Elem(), "", lookup)...)
+}
+
+func bindStruct(rv reflect.Value, prefix string, lookup lookupFunc) []error {
+	var errList []error
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+		tag, hasTag := sf.Tag.Lookup("env")
+		if !hasTag {
+			if p, ok := sf.Tag.Lookup("envPrefix"); ok || isNestedStruct(sf.Type) {
+				if fv.Kind() == reflect.Pointer {
+					if fv.IsNil() {
+						fv.Set(reflect.New(sf.Type.Elem()))
+					}
+					fv = fv.Elem()
+				}
+				errList = append(errList, bindStruct(fv, prefix+p, lookup)...)
+			}
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		raw, ok, err := lookup(key)
+		if err != nil {
+			errList = append(errList, fmt.Errorf("config: %s: %w", key, err))
+			continue
+		}
+		if !ok || raw == "" {
+			raw, ok = sf.Tag.Lookup("default")
+		}
+		if !ok || raw == "" {
+			if flags == "required" {
+				errList = append(errList, fmt.Errorf("config: %s is required", key))
+			}
+			continue
+		}
+		sep := sf.Tag.Get("sep")
+		if sep == "" {
+			sep = ","
+		}
+		if err := setValue(fv, raw, sep); err != nil {
+			errList = append(errList, fmt.Errorf("config: %s=%q: %w", key, raw, err))
+		}
+	}
+	return errList
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isNestedStruct reports whether t is a struct (or pointer to one) to bind field by field
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// setValue parses raw into fv according to its type
+func setValue(fv reflect.Value, raw, sep string) error {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return setValue(fv.Elem(), raw, sep)
+	}
+	if fv.CanAddr() {
+		if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(raw))
+		}
+	}
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("not a duration (e.g. 30s, 5m)")
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("not a bool (true/false/1/0)")
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("not an integer")
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("not a non-negative integer")
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return errors.New("not a number")
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		var parts []string
+		for _, p := range strings.Split(raw, sep) {
+			if p = strings.TrimSpace(p); p != "" {
+				parts = append(parts, p)
+			}
+		}
+		s := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			if err := setValue(s.Index(i), p, sep); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		fv.Set(s)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
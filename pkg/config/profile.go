@@ -0,0 +1,71 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// Env returns the active environment profile from APP_ENV, "development" when unset
+// APP_ENV may come from the real environment or from the base .env file.
+// Example:
+//
+//	if config.Env() == "production" {
+//	    gin.SetMode(gin.ReleaseMode)
+//	}
+func Env() string {
+	if env := os.Getenv("APP_ENV"); env != "" {
+		return env
+	}
+	if vals, err := godotenv.Read(".env"); err == nil && vals["APP_ENV"] != "" {
+		return vals["APP_ENV"]
+	}
+	return "development"
+}
+
+// envFiles returns the existing env files of the active profile, lowest precedence first:
+// .env, .env.local, .env.<profile>, .env.<profile>.local
+// .env.local is skipped for the test profile so tests are reproducible across machines.
+func envFiles() []string {
+	env := Env()
+	candidates := []string{".env", ".env.local", ".env." + env, ".env." + env + ".local"}
+	if env == "test" {
+		candidates = []string{".env", ".env." + env, ".env." + env + ".local"}
+	}
+	var files []string
+	for _, f := range candidates {
+		if st, err := os.Stat(f); err == nil && !st.IsDir() {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// loadDotenv loads the profile's env files so later files override earlier ones while
+// variables from the real environment keep precedence; it returns the files loaded
+func loadDotenv() []string {
+	files := envFiles()
+	// godotenv never overrides a variable that is already set, so load the most specific first
+	for i := len(files) - 1; i >= 0; i-- {
+		if err := godotenv.Load(files[i]); err != nil {
+			log.Printf("config: skipping %s: %v", files[i], err)
+		}
+	}
+	return files
+}
+
+// readDotenv merges the profile's env files like loadDotenv without touching the environment
+func readDotenv() (map[string]string, error) {
+	merged := map[string]string{}
+	for _, f := range envFiles() {
+		vals, err := godotenv.Read(f)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range vals {
+			merged[k] = v
+		}
+	}
+	return merged, nil
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RemoteSource is a central key/value store holding configuration for a fleet of services
+// (Consul KV, etcd, or your own). Once set with SetRemoteSource, its values take
+// precedence in LoadEnv, Load and the Get helpers, and variables it doesn't have fall back
+// to the local environment and .env files.
+type RemoteSource interface {
+	// Values returns all variables of this service, keyed by env name (e.g. DB_HOST)
+	Values(ctx context.Context) (map[string]string, error)
+}
+
+// RemoteSourceFunc adapts a function to RemoteSource
+type RemoteSourceFunc func(ctx context.Context) (map[string]string, error)
+
+// Values calls f(ctx)
+func (f RemoteSourceFunc) Values(ctx context.Context) (map[string]string, error) {
+	return f(ctx)
+}
+
+// remoteTimeout bounds a fetch from the remote source
+const remoteTimeout = 10 * time.Second
+
+var remote struct {
+	mu     sync.Mutex
+	source RemoteSource
+	values map[string]string // last successful fetch
+}
+
+// SetRemoteSource makes LoadEnv, Load and the Get helpers read variables from src first
+// LoadEnv and Load fetch fresh values each time (so Watch picks up changes on SIGHUP); the
+// Get helpers use the values of the last load. When the store can't be reached the last
+// fetched values are kept, or only the local environment is used, and the error is logged.
+// Remote values may be secret references. nil removes the source.
+// Example:
+//
+//	consul, err := config.NewConsul(config.ConsulConfig{Prefix: "services/orders/"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config.SetRemoteSource(consul) // services/orders/DB_HOST -> DB_HOST
+//	cfg := config.LoadEnv()
+func SetRemoteSource(src RemoteSource) {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+	remote.source = src
+	remote.values = nil
+}
+
+// remoteValues returns the remote variables, fetching them when refresh is set or
+// nothing was fetched yet; nil without a remote source
+func remoteValues(refresh bool) map[string]string {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+	if remote.source == nil || (!refresh && remote.values != nil) {
+		return remote.values
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), remoteTimeout)
+	defer cancel()
+	values, err := remote.source.Values(ctx)
+	if err != nil {
+		log.Printf("config: remote source unavailable, using last fetched values and local environment: %v", err)
+		return remote.values
+	}
+	remote.values = values
+	return values
+}
+
+// remoteKey turns a store key below the prefix into an env name: "db/host" -> "DB_HOST"
+func remoteKey(key string) string {
+	return strings.ToUpper(strings.NewReplacer("/", "_", "-", "_", ".", "_").Replace(strings.Trim(key, "/")))
+}
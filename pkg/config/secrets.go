@@ -0,0 +1,190 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSecretNotFound is returned by secret providers when the referenced secret does not exist
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider fetches secrets from a secret manager
+// LoadEnv and Load resolve variables of the form "<scheme>:<path>[#field]" with the provider
+// registered for scheme; built in are "vault" (HashiCorp Vault), "awssm" (AWS Secrets
+// Manager) and "gcpsm" (GCP Secret Manager), configured from the environment on first use.
+type SecretProvider interface {
+	// Secret returns the secret stored at path. Secrets holding several key/value pairs
+	// are returned as a JSON object, from which "#field" picks one value.
+	Secret(ctx context.Context, path string) (string, error)
+}
+
+// SecretProviderFunc adapts a function to SecretProvider
+type SecretProviderFunc func(ctx context.Context, path string) (string, error)
+
+// Secret calls f(ctx, path)
+func (f SecretProviderFunc) Secret(ctx context.Context, path string) (string, error) {
+	return f(ctx, path)
+}
+
+// secretTimeout bounds each secret fetch so a slow secret manager can't hang startup
+const secretTimeout = 10 * time.Second
+
+var (
+	secretMu        sync.RWMutex
+	secretProviders = map[string]SecretProvider{
+		"vault": &envProvider{build: vaultFromEnv},
+		"awssm": &envProvider{build: awsSecretsFromEnv},
+		"gcpsm": &envProvider{build: gcpSecretsFromEnv},
+	}
+)
+
+// RegisterSecretProvider makes values starting with "<scheme>:" resolve through p
+// It replaces a built-in provider of the same scheme (e.g. a Vault client using
+// Kubernetes auth instead of VAULT_TOKEN); a nil p removes the scheme. Call it before
+// LoadEnv or Load.
+// Example:
+//
+//	vault, err := config.NewVault(config.VaultConfig{Address: "https://vault.internal:8200", Token: token})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	config.RegisterSecretProvider("vault", vault)
+//
+//	// DB_PASSWORD=vault:secret/data/db#password
+//	cfg := config.LoadEnv()
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	if p == nil {
+		delete(secretProviders, scheme)
+		return
+	}
+	secretProviders[scheme] = p
+}
+
+// ResolveSecret returns value with a secret reference replaced by the secret itself
+// Values without a registered "<scheme>:" prefix are returned unchanged, so plain values
+// and URLs such as postgres://... pass through.
+// Example:
+//
+//	password, err := config.ResolveSecret(ctx, os.Getenv("SMTP_PASSWORD"))
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	return newSecretResolver(lookupEnv(false)).resolve(ctx, value)
+}
+
+// secretResolver resolves references for one load, fetching each secret path once
+// so DB_USER and DB_PASSWORD from the same secret cost a single request
+type secretResolver struct {
+	env   func(key string) (string, bool)
+	cache map[string]string
+}
+
+func newSecretResolver(env func(key string) (string, bool)) *secretResolver {
+	return &secretResolver{env: env, cache: map[string]string{}}
+}
+
+// lookup reads key with env and resolves a secret reference in its value; it is the
+// lookupFunc of LoadEnv and Load
+func (r *secretResolver) lookup(key string) (string, bool, error) {
+	v, ok := r.env(key)
+	if !ok || v == "" {
+		return v, ok, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	v, err := r.resolve(ctx, v)
+	return v, ok, err
+}
+
+func (r *secretResolver) resolve(ctx context.Context, value string) (string, error) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok {
+		return value, nil
+	}
+	secretMu.RLock()
+	p := secretProviders[scheme]
+	secretMu.RUnlock()
+	if p == nil {
+		return value, nil
+	}
+
+	path, field, _ := strings.Cut(ref, "#")
+	if path == "" {
+		return "", fmt.Errorf("empty %s secret path", scheme)
+	}
+	cacheKey := scheme + ":" + path
+	secret, cached := r.cache[cacheKey]
+	if !cached {
+		var err error
+		if secret, err = p.Secret(ctx, path); err != nil {
+			return "", fmt.Errorf("%s secret %s: %w", scheme, path, err)
+		}
+		r.cache[cacheKey] = secret
+	}
+	if field == "" {
+		return secret, nil
+	}
+	return secretField(secret, field)
+}
+
+// secretField picks field from a secret holding a JSON object; non-string values are
+// returned as JSON
+func secretField(secret, field string) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot select #%s", field)
+	}
+	raw, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q: %w", field, ErrSecretNotFound)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// envProvider builds a provider from environment variables on first use, after LoadEnv
+// has read the .env file; failed builds are retried on the next use
+type envProvider struct {
+	mu    sync.Mutex
+	p     SecretProvider
+	build func() (SecretProvider, error)
+}
+
+func (e *envProvider) Secret(ctx context.Context, path string) (string, error) {
+	e.mu.Lock()
+	if e.p == nil {
+		p, err := e.build()
+		if err != nil {
+			e.mu.Unlock()
+			return "", err
+		}
+		e.p = p
+	}
+	p := e.p
+	e.mu.Unlock()
+	return p.Secret(ctx, path)
+}
+
+// httpClient is shared by the built-in secret providers and remote sources unless their
+// config sets a Client
+var httpClient = &http.Client{Timeout: secretTimeout}
+
+// firstEnv returns the first non-empty variable of keys
+func firstEnv(keys ...string) string {
+	for _, k := range keys {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// VaultConfig configures the HashiCorp Vault secret provider
+type VaultConfig struct {
+	Address   string // e.g. "https://vault.internal:8200" (VAULT_ADDR)
+	Token     string // VAULT_TOKEN
+	Namespace string // Vault Enterprise namespace (VAULT_NAMESPACE), optional
+	Client    *http.Client
+}
+
+// Vault reads secrets from HashiCorp Vault over its HTTP API
+// Paths are API paths below /v1/: "secret/data/db" for the KV v2 engine mounted at
+// secret/, "kv/db" for KV v1. Both return the secret's key/value pairs, so references
+// look like "vault:secret/data/db#password".
+type Vault struct {
+	config VaultConfig
+}
+
+// NewVault creates a Vault secret provider
+// Example:
+//
+//	vault, err := config.NewVault(config.VaultConfig{Address: os.Getenv("VAULT_ADDR"), Token: token})
+//	config.RegisterSecretProvider("vault", vault)
+func NewVault(config VaultConfig) (*Vault, error) {
+	if config.Address == "" || config.Token == "" {
+		return nil, errors.New("vault address and token must be set")
+	}
+	config.Address = strings.TrimRight(config.Address, "/")
+	if config.Client == nil {
+		config.Client = httpClient
+	}
+	return &Vault{config: config}, nil
+}
+
+func vaultFromEnv() (SecretProvider, error) {
+	v, err := NewVault(VaultConfig{
+		Address:   firstEnv("VAULT_ADDR"),
+		Token:     firstEnv("VAULT_TOKEN"),
+		Namespace: firstEnv("VAULT_NAMESPACE"),
+	})
+	if err != nil {
+		return nil, errors.New("vault secrets need VAULT_ADDR and VAULT_TOKEN")
+	}
+	return v, nil
+}
+
+// Secret implements SecretProvider and returns the secret's data as a JSON object
+func (v *Vault) Secret(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.config.Address+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", v.config.Token)
+	if v.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.config.Namespace)
+	}
+	resp, err := v.config.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrSecretNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("vault returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("invalid vault response: %w", err)
+	}
+	data := out.Data
+	// KV v2 nests the pairs under data.data next to data.metadata
+	if inner, ok := data["data"]; ok && data["metadata"] != nil {
+		data = nil
+		if err := json.Unmarshal(inner, &data); err != nil || data == nil {
+			return "", ErrSecretNotFound // deleted or destroyed version
+		}
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
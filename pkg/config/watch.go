@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// watchInterval is how often Watch checks the .env files for changes
+const watchInterval = 2 * time.Second
+
+// Watch reloads the configuration when one of the .env files of the APP_ENV profile changes
+// (or appears) or the process receives SIGHUP, and calls fn with the new Config when it
+// differs from the previous one
+// Values from the files replace the ones they set before (and variables removed from them
+// are unset), while variables set by the real environment keep precedence as in LoadEnv.
+// Secret references are fetched again on every reload, so `kill -HUP` picks up
+// credentials rotated in Vault or a cloud secret manager. The files are polled every
+// couple of seconds (no inotify dependency). fn runs on the watcher goroutine, one call
+// at a time; Watch returns immediately and stops when ctx is done.
+// Example:
+//
+//	var current atomic.Pointer[config.Config]
+//	current.Store(config.LoadEnv())
+//	config.Watch(ctx, func(cfg *config.Config) {
+//	    current.Store(cfg)
+//	    database.SetQueryTimeout(cfg.DBQueryTimeout)
+//	})
+func Watch(ctx context.Context, fn func(newCfg *Config)) {
+	w := &watcher{owned: map[string]bool{}}
+	if vals, err := readDotenv(); err == nil {
+		for k, v := range vals {
+			if cur, ok := os.LookupEnv(k); ok && cur == v {
+				w.owned[k] = true // most likely set by an earlier LoadEnv
+			}
+		}
+	}
+	w.stamps = fileStamps()
+	w.last = LoadEnv()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				w.reload(fn, "SIGHUP")
+			case <-ticker.C:
+				if st := fileStamps(); !slices.Equal(st, w.stamps) {
+					w.stamps = st
+					w.reload(fn, ".env files changed")
+				}
+			}
+		}
+	}()
+}
+
+// watcher holds the state of one Watch call
+type watcher struct {
+	owned  map[string]bool // variables whose value came from the files
+	stamps []fileStamp
+	last   *Config
+}
+
+type fileStamp struct {
+	name    string
+	modTime time.Time
+	size    int64
+}
+
+// fileStamps describes the profile's env files as they are now on disk
+func fileStamps() []fileStamp {
+	var stamps []fileStamp
+	for _, f := range envFiles() {
+		if st, err := os.Stat(f); err == nil {
+			stamps = append(stamps, fileStamp{name: f, modTime: st.ModTime(), size: st.Size()})
+		}
+	}
+	return stamps
+}
+
+func (w *watcher) reload(fn func(*Config), reason string) {
+	if err := w.applyFiles(); err != nil {
+		// e.g. caught mid-write; clearing the stamps retries on the next poll
+		log.Printf("config: not reloading, invalid .env file: %v", err)
+		w.stamps = nil
+		return
+	}
+	cfg := LoadEnv()
+	if reflect.DeepEqual(cfg, w.last) {
+		return
+	}
+	w.last = cfg
+	log.Printf("config: reloaded after %s", reason)
+	fn(cfg)
+}
+
+// applyFiles copies the files' variables into the environment, leaving variables they do
+// not own alone
+func (w *watcher) applyFiles() error {
+	vals, err := readDotenv() // deleted files simply drop out: what they set is unset
+	if err != nil {
+		return err
+	}
+	for k := range w.owned {
+		if _, ok := vals[k]; !ok {
+			os.Unsetenv(k)
+			delete(w.owned, k)
+		}
+	}
+	for k, v := range vals {
+		if _, set := os.LookupEnv(k); set && !w.owned[k] {
+			continue
+		}
+		os.Setenv(k, v)
+		w.owned[k] = true
+	}
+	return nil
+}
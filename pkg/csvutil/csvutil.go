@@ -0,0 +1,47 @@
+package csvutil
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field maps a CSV column to a struct field index
+type field struct {
+	name  string
+	index []int
+}
+
+// fieldsOf returns exported fields of struct type t in declaration order
+// Column names come from the `csv` tag, then the `json` tag, then the field name.
+// A tag of "-" skips the field.
+func fieldsOf(t reflect.Type) []field {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := tagName(sf.Tag.Get("csv"))
+		if name == "" {
+			name = tagName(sf.Tag.Get("json"))
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, field{name: name, index: sf.Index})
+	}
+	return fields
+}
+
+func tagName(tag string) string {
+	return strings.TrimSpace(strings.Split(tag, ",")[0])
+}
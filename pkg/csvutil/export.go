@@ -0,0 +1,151 @@
+package csvutil
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteSlice writes items as CSV with a header row built from struct tags
+// Rows go to w through a small buffer, so large exports don't build up in memory.
+// Cells that a spreadsheet would run as a formula are escaped (see escapeFormula).
+// Example:
+//
+//	w.Header().Set("Content-Type", "text/csv")
+//	w.Header().Set("Content-Disposition", `attachment; filename="products.csv"`)
+//	err := csvutil.WriteSlice(w, products)
+func WriteSlice[T any](w io.Writer, items []T) error {
+	fields := fieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if len(fields) == 0 {
+		return fmt.Errorf("csvutil: %T is not a struct", *new(T))
+	}
+
+	cw := csv.NewWriter(w)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(fields))
+	for _, item := range items {
+		rv := reflect.Indirect(reflect.ValueOf(item))
+		for i, f := range fields {
+			record[i] = escapeFormula(formatValue(rv.FieldByIndex(f.index)))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteRows streams sql.Rows as CSV using column names as the header
+// Use this for raw query exports without defining a struct; cells are escaped like in WriteSlice
+// Example:
+//
+//	rows, _ := db.QueryContext(ctx, "SELECT id, name, price FROM products")
+//	defer rows.Close()
+//	err := csvutil.WriteRows(w, rows)
+func WriteRows(w io.Writer, rows *sql.Rows) error {
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols); err != nil {
+		return err
+	}
+
+	values := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	record := make([]string, len(cols))
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			record[i] = escapeFormula(formatValue(reflect.ValueOf(v)))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeFormula prefixes cells starting with =, +, -, @, tab or CR with a quote so Excel
+// and Sheets show user data such as =HYPERLINK(...) as text instead of running it (CSV
+// injection); numbers like -5 are left alone
+func escapeFormula(s string) string {
+	if s == "" || !strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	return "'" + s
+}
+
+// formatValue renders a single value as a CSV cell
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch val := v.Interface().(type) {
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(time.RFC3339)
+	case []byte:
+		return string(val)
+	case driver.Valuer: // sql.NullString, sql.NullInt64, ...
+		dv, err := val.Value()
+		if err != nil {
+			return ""
+		}
+		return formatValue(reflect.ValueOf(dv))
+	case fmt.Stringer:
+		return val.String()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
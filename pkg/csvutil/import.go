@@ -0,0 +1,191 @@
+package csvutil
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RowError describes a problem with a single CSV row
+// Row is 1-based and counts the header, so it matches what users see in a spreadsheet.
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e RowError) Error() string {
+	if e.Column != "" {
+		return fmt.Sprintf("row %d, column %s: %s", e.Row, e.Column, e.Message)
+	}
+	return fmt.Sprintf("row %d: %s", e.Row, e.Message)
+}
+
+// ErrMissingHeader is returned when the CSV has no header row
+var ErrMissingHeader = errors.New("csv header row is missing")
+
+// Parse reads CSV with a header row into a slice of T
+// Columns are matched to struct fields by tag name (case-insensitive); unknown columns are ignored.
+// Conversion failures and validate errors are collected per row instead of aborting,
+// so bulk import endpoints can report every bad row at once. Invalid rows are not returned.
+// The returned error is only set for unreadable input (bad CSV syntax, I/O failure).
+// Example:
+//
+//	products, rowErrs, err := csvutil.Parse[Product](file, func(p *Product) error {
+//	    if p.Price <= 0 {
+//	        return errors.New("price must be positive")
+//	    }
+//	    return nil
+//	})
+//	if len(rowErrs) > 0 {
+//	    return response.BadRequest(c, fmt.Sprintf("%d invalid rows", len(rowErrs)))
+//	}
+func Parse[T any](r io.Reader, validate func(item *T) error) ([]T, []RowError, error) {
+	fields := fieldsOf(reflect.TypeOf((*T)(nil)).Elem())
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("csvutil: %T is not a struct", *new(T))
+	}
+	byName := map[string]field{}
+	for _, f := range fields {
+		byName[strings.ToLower(f.name)] = f
+	}
+
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, ErrMissingHeader
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	columns := make([]*field, len(header))
+	for i, h := range header {
+		h = strings.TrimPrefix(h, "\ufeff") // Excel adds a UTF-8 BOM
+		if f, ok := byName[strings.ToLower(strings.TrimSpace(h))]; ok {
+			f := f
+			columns[i] = &f
+		}
+	}
+
+	var items []T
+	var rowErrs []RowError
+	row := 1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			var pe *csv.ParseError
+			if errors.As(err, &pe) {
+				rowErrs = append(rowErrs, RowError{Row: row, Message: pe.Err.Error()})
+				continue
+			}
+			return items, rowErrs, fmt.Errorf("failed to read csv: %w", err)
+		}
+
+		var item T
+		rv := reflect.ValueOf(&item).Elem()
+		ok := true
+		for i, value := range record {
+			if i >= len(columns) || columns[i] == nil {
+				continue
+			}
+			if err := setValue(rv.FieldByIndex(columns[i].index), strings.TrimSpace(value)); err != nil {
+				rowErrs = append(rowErrs, RowError{Row: row, Column: columns[i].name, Message: err.Error()})
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+		if validate != nil {
+			if err := validate(&item); err != nil {
+				rowErrs = append(rowErrs, RowError{Row: row, Message: err.Error()})
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	return items, rowErrs, nil
+}
+
+// setValue parses s into v according to v's kind
+func setValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Ptr {
+		if s == "" {
+			return nil
+		}
+		p := reflect.New(v.Type().Elem())
+		if err := setValue(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		if s == "" {
+			return nil
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid date %q", s)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		if s == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if s == "" {
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+var queryTimeout atomic.Int64 // time.Duration
+
+// SetQueryTimeout sets the default query timeout, usually config.Config.DBQueryTimeout (DB_QUERY_TIMEOUT)
+// Connections opened afterwards by ConnectPostgres, ConnectPostgresURL and orm.ConnectGORM get
+// it as the server-side statement_timeout, so runaway queries are cancelled by Postgres even
+// when they run with context.Background(). Zero disables it.
+// Example:
+//
+//	database.SetQueryTimeout(5 * time.Second)
+//	db, err := database.ConnectPostgresURL(cfg.DatabaseURL)
+func SetQueryTimeout(d time.Duration) {
+	queryTimeout.Store(int64(max(d, 0)))
+}
+
+// QueryTimeout returns the default query timeout, 0 when none is set
+func QueryTimeout() time.Duration {
+	return time.Duration(queryTimeout.Load())
+}
+
+// WithQueryTimeout bounds ctx by the default query timeout
+// ctx is returned unchanged (with a no-op cancel) when no timeout is set or it already
+// has an earlier deadline. Always call cancel.
+// Example:
+//
+//	ctx, cancel := database.WithQueryTimeout(r.Context())
+//	defer cancel()
+//	err := db.QueryRowContext(ctx, "SELECT ...", id).Scan(&v)
+func WithQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return WithTimeout(ctx, QueryTimeout())
+}
+
+// WithTimeout bounds ctx by d unless d is 0 or ctx already expires sooner
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/retry"
+)
+
+// TxOption configures WithTx, orm.WithTransaction and repository.InUnitOfWork
+type TxOption func(*TxConfig)
+
+// TxConfig holds the transaction options and how often it is retried
+type TxConfig struct {
+	// Attempts is the maximum number of runs of the transaction function (default 3)
+	Attempts  int
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+}
+
+// TxAttempts sets how many times a transaction failing with a serialization failure or a
+// deadlock is run; 1 disables retries
+func TxAttempts(n int) TxOption {
+	return func(c *TxConfig) {
+		c.Attempts = max(n, 1)
+	}
+}
+
+// TxIsolation sets the isolation level
+func TxIsolation(level sql.IsolationLevel) TxOption {
+	return func(c *TxConfig) {
+		c.Isolation = level
+	}
+}
+
+// Serializable runs the transaction with SERIALIZABLE isolation
+func Serializable() TxOption {
+	return TxIsolation(sql.LevelSerializable)
+}
+
+// ReadOnly starts a read-only transaction
+func ReadOnly() TxOption {
+	return func(c *TxConfig) {
+		c.ReadOnly = true
+	}
+}
+
+// NewTxConfig applies opts to the defaults
+func NewTxConfig(opts ...TxOption) TxConfig {
+	c := TxConfig{Attempts: 3}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// SQLOptions returns the options for BeginTx; nil when the defaults are used
+func (c TxConfig) SQLOptions() *sql.TxOptions {
+	if c.Isolation == sql.LevelDefault && !c.ReadOnly {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: c.Isolation, ReadOnly: c.ReadOnly}
+}
+
+// Retry runs fn, a whole transaction, again after a short jittered pause while it fails with
+// a serialization failure (40001) or a deadlock (40P01), up to c.Attempts times
+// Under SERIALIZABLE isolation Postgres aborts one of two conflicting transactions and
+// expects the client to retry it. fn must not have side effects outside the database.
+func (c TxConfig) Retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	return retry.Do(ctx, fn,
+		retry.Attempts(c.Attempts),
+		retry.ExponentialBackoff(10*time.Millisecond, 500*time.Millisecond),
+		retry.RetryIf(errs.IsRetryableTx),
+	)
+}
+
+// WithTx runs fn in a transaction on db, committing when it returns nil and rolling back on
+// an error or panic. Serialization failures and deadlocks rerun the whole function (see TxConfig.Retry).
+// Example:
+//
+//	err := database.WithTx(ctx, db, func(tx *sql.Tx) error {
+//		if _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2", amount, from); err != nil {
+//			return err
+//		}
+//		_, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance + $1 WHERE id = $2", amount, to)
+//		return err
+//	}, database.Serializable())
+func WithTx(ctx context.Context, db interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}, fn func(tx *sql.Tx) error, opts ...TxOption) error {
+	cfg := NewTxConfig(opts...)
+	return cfg.Retry(ctx, func(ctx context.Context) error {
+		tx, err := db.BeginTx(ctx, cfg.SQLOptions())
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				tx.Rollback()
+				panic(p)
+			}
+		}()
+		if err := fn(tx); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+				return errors.Join(err, rbErr)
+			}
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	})
+}
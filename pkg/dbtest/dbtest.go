@@ -0,0 +1,263 @@
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/yoockh/go-api-utils/pkg/fixtures"
+	"github.com/yoockh/go-api-utils/pkg/migrations"
+	"github.com/yoockh/go-api-utils/pkg/retry"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Config configures the test database
+type Config struct {
+	// Migrations holds versioned *.sql files applied with pkg/migrations (*.down.sql files are
+	// skipped, applied versions are recorded in schema_migrations), e.g. os.DirFS("../../migrations")
+	Migrations fs.FS
+	// Models are passed to GORM AutoMigrate after the SQL migrations
+	Models []interface{}
+	// Image is the Postgres image started when TEST_DATABASE_URL is unset (default postgres:16-alpine)
+	Image string
+}
+
+var (
+	mu        sync.Mutex
+	sqlDB     *sql.DB
+	gormDB    *gorm.DB
+	dbURL     string
+	setupErr  error
+	container string
+)
+
+// Run prepares the database, runs the tests and tears everything down; call it from TestMain
+// The database is TEST_DATABASE_URL when set, otherwise a throwaway Postgres container
+// started with the docker CLI. When neither is available, tests using Tx, GormTx or DB are skipped.
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(dbtest.Run(m, dbtest.Config{Migrations: os.DirFS("../../migrations")}))
+//	}
+func Run(m *testing.M, config Config) int {
+	if err := Setup(config); err != nil {
+		log.Printf("dbtest: %v; database tests will be skipped", err)
+	}
+	defer Teardown()
+	return m.Run()
+}
+
+// Setup connects (or starts a container) and applies migrations; Run calls it for you
+func Setup(config Config) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if sqlDB != nil {
+		return nil
+	}
+
+	dbURL = os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		url, id, err := startContainer(config.Image)
+		if err != nil {
+			setupErr = err
+			return err
+		}
+		dbURL, container = url, id
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err == nil {
+		err = retry.Do(context.Background(), func(ctx context.Context) error {
+			return db.PingContext(ctx)
+		}, retry.Attempts(30), retry.ConstantBackoff(time.Second))
+	}
+	if err == nil {
+		err = migrate(db, config.Migrations)
+	}
+	var gdb *gorm.DB
+	if err == nil {
+		gdb, err = gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	}
+	if err == nil && len(config.Models) > 0 {
+		err = gdb.AutoMigrate(config.Models...)
+	}
+	if err != nil {
+		setupErr = fmt.Errorf("failed to prepare test database: %w", err)
+		if db != nil {
+			db.Close()
+		}
+		stopContainer()
+		return setupErr
+	}
+	sqlDB, gormDB = db, gdb
+	return nil
+}
+
+// Teardown closes the connection and removes the container started by Setup
+func Teardown() {
+	mu.Lock()
+	defer mu.Unlock()
+	if sqlDB != nil {
+		sqlDB.Close()
+		sqlDB, gormDB = nil, nil
+	}
+	stopContainer()
+}
+
+// URL returns the connection URL of the test database ("" before Setup)
+func URL() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return dbURL
+}
+
+// DB returns the shared *sql.DB; changes made through it are NOT rolled back
+func DB(t testing.TB) *sql.DB {
+	t.Helper()
+	mu.Lock()
+	db, err := sqlDB, setupErr
+	mu.Unlock()
+	if db == nil {
+		if err == nil {
+			err = errors.New("dbtest.Setup was not called (use dbtest.Run in TestMain)")
+		}
+		t.Skipf("dbtest: %v", err)
+	}
+	return db
+}
+
+// Tx begins a transaction that is rolled back when the test ends, isolating its writes
+// Write repositories against an interface satisfied by both *sql.DB and *sql.Tx
+// (ExecContext, QueryContext, QueryRowContext) to use it.
+// Example:
+//
+//	func TestCreateProduct(t *testing.T) {
+//		repo := NewProductRepo(dbtest.Tx(t))
+//		...
+//	}
+func Tx(t testing.TB) *sql.Tx {
+	t.Helper()
+	tx, err := DB(t).BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("dbtest: failed to begin transaction: %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// GormTx returns a *gorm.DB inside a transaction rolled back when the test ends
+// Transactions opened by the code under test become savepoints.
+// Example:
+//
+//	db := dbtest.GormTx(t)
+//	svc := NewOrderService(db)
+func GormTx(t testing.TB) *gorm.DB {
+	t.Helper()
+	DB(t) // skips when unavailable
+	mu.Lock()
+	gdb := gormDB
+	mu.Unlock()
+	tx := gdb.Begin()
+	if tx.Error != nil {
+		t.Fatalf("dbtest: failed to begin transaction: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// Fixtures begins a rolled-back transaction (see Tx) and loads the fixture set into it
+// Example:
+//
+//	var seed = fixtures.MustLoad(os.DirFS("testdata/fixtures"))
+//
+//	func TestListOrders(t *testing.T) {
+//		repo := NewOrderRepo(dbtest.Fixtures(t, seed))
+//		...
+//	}
+func Fixtures(t testing.TB, set *fixtures.Set) *sql.Tx {
+	t.Helper()
+	tx := Tx(t)
+	if err := set.Apply(context.Background(), tx); err != nil {
+		t.Fatalf("dbtest: failed to load fixtures: %v", err)
+	}
+	return tx
+}
+
+// GormFixtures is Fixtures for GORM: GormTx with the fixture set loaded
+func GormFixtures(t testing.TB, set *fixtures.Set) *gorm.DB {
+	t.Helper()
+	tx := GormTx(t)
+	if err := set.Apply(context.Background(), tx.Statement.ConnPool); err != nil {
+		t.Fatalf("dbtest: failed to load fixtures: %v", err)
+	}
+	return tx
+}
+
+// migrate applies the pending migrations with pkg/migrations, so a reused TEST_DATABASE_URL
+// only runs the ones it hasn't applied yet
+func migrate(db *sql.DB, fsys fs.FS) error {
+	if fsys == nil {
+		return nil
+	}
+	m, err := migrations.New(db, fsys, ".")
+	if err != nil {
+		return err
+	}
+	_, err = m.Up(context.Background())
+	return err
+}
+
+func startContainer(image string) (string, string, error) {
+	if image == "" {
+		image = "postgres:16-alpine"
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		return "", "", errors.New("TEST_DATABASE_URL is not set and docker is not available")
+	}
+	out, err := exec.Command("docker", "run", "-d", "--rm",
+		"-e", "POSTGRES_USER=postgres",
+		"-e", "POSTGRES_PASSWORD=postgres",
+		"-e", "POSTGRES_DB=test",
+		"-p", "127.0.0.1::5432",
+		image, "-c", "fsync=off").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to start %s: %w", image, exitDetail(err))
+	}
+	id := strings.TrimSpace(string(out))
+
+	out, err = exec.Command("docker", "port", id, "5432/tcp").Output()
+	if err != nil {
+		exec.Command("docker", "rm", "-f", id).Run()
+		return "", "", fmt.Errorf("failed to read container port: %w", exitDetail(err))
+	}
+	addr := strings.TrimSpace(strings.Split(string(out), "\n")[0])
+	return "postgres://postgres:postgres@" + addr + "/test?sslmode=disable", id, nil
+}
+
+// stopContainer removes the container started by Setup; call with mu held
+func stopContainer() {
+	if container != "" {
+		exec.Command("docker", "rm", "-f", container).Run()
+		container = ""
+	}
+}
+
+func exitDetail(err error) error {
+	var ee *exec.ExitError
+	if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(ee.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package debug
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// Handler serves pprof under /debug/pprof/ and expvar under /debug/vars without any protection
+// Prefer Mount or Protected, which require a guard.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// Protected wraps Handler with guard; a nil guard denies every request
+// Example:
+//
+//	// Echo
+//	guard := middleware.BasicAuth("ops", os.Getenv("DEBUG_PASSWORD"))
+//	e.Any("/debug/*", echo.WrapHandler(debug.Protected(guard)))
+func Protected(guard func(http.Handler) http.Handler) http.Handler {
+	if guard == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response.Forbidden(w, "debug endpoints are disabled")
+		})
+	}
+	return guard(Handler())
+}
+
+// Mount registers the protected debug endpoints on mux under /debug/
+// Example:
+//
+//	allow, _ := middleware.IPAllowlist("10.0.0.0/8", "127.0.0.1")
+//	debug.Mount(mux, allow)
+//	// go tool pprof http://localhost:8080/debug/pprof/heap
+func Mount(mux *http.ServeMux, guard func(http.Handler) http.Handler) {
+	mux.Handle("/debug/", Protected(guard))
+}
+
+// GuardFromEnv builds a guard from DEBUG_USER/DEBUG_PASSWORD (basic auth) and
+// DEBUG_ALLOW_IPS (comma-separated IPs/CIDRs). When both are set, both must pass.
+// Returns nil (endpoints disabled) when neither is configured.
+// Example:
+//
+//	guard, err := debug.GuardFromEnv()
+//	debug.Mount(mux, guard)
+func GuardFromEnv() (func(http.Handler) http.Handler, error) {
+	var guards []func(http.Handler) http.Handler
+	if ips := os.Getenv("DEBUG_ALLOW_IPS"); ips != "" {
+		allow, err := middleware.IPAllowlist(strings.Split(ips, ",")...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEBUG_ALLOW_IPS: %w", err)
+		}
+		guards = append(guards, allow)
+	}
+	if pass := os.Getenv("DEBUG_PASSWORD"); pass != "" {
+		user := os.Getenv("DEBUG_USER")
+		if user == "" {
+			user = "debug"
+		}
+		guards = append(guards, middleware.BasicAuth(user, pass))
+	}
+	if len(guards) == 0 {
+		return nil, nil
+	}
+	return func(next http.Handler) http.Handler {
+		for i := len(guards) - 1; i >= 0; i-- {
+			next = guards[i](next)
+		}
+		return next
+	}, nil
+}
@@ -0,0 +1,117 @@
+package debug
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+var startTime = time.Now()
+
+// RuntimeStats is a snapshot of process and connection pool health
+type RuntimeStats struct {
+	Uptime        string             `json:"uptime"`
+	UptimeSeconds float64            `json:"uptime_seconds"`
+	GoVersion     string             `json:"go_version"`
+	NumCPU        int                `json:"num_cpu"`
+	Goroutines    int                `json:"goroutines"`
+	Memory        MemoryStats        `json:"memory"`
+	GC            GCStats            `json:"gc"`
+	Databases     map[string]DBStats `json:"databases,omitempty"`
+}
+
+// MemoryStats holds heap and allocation figures in bytes
+type MemoryStats struct {
+	Alloc      uint64 `json:"alloc_bytes"`
+	TotalAlloc uint64 `json:"total_alloc_bytes"`
+	Sys        uint64 `json:"sys_bytes"`
+	HeapAlloc  uint64 `json:"heap_alloc_bytes"`
+	HeapInuse  uint64 `json:"heap_inuse_bytes"`
+	HeapIdle   uint64 `json:"heap_idle_bytes"`
+	HeapObject uint64 `json:"heap_objects"`
+	Mallocs    uint64 `json:"mallocs"`
+	Frees      uint64 `json:"frees"`
+}
+
+// GCStats summarizes garbage collection
+type GCStats struct {
+	NumGC        uint32    `json:"num_gc"`
+	LastGC       time.Time `json:"last_gc,omitempty"`
+	LastPauseMs  float64   `json:"last_pause_ms"`
+	TotalPauseMs float64   `json:"total_pause_ms"`
+	RecentPauses []float64 `json:"recent_pauses_ms"` // newest first, up to 10
+	CPUFraction  float64   `json:"cpu_fraction"`
+}
+
+// DBStats holds sql.DB connection pool figures
+type DBStats struct {
+	MaxOpen      int    `json:"max_open"`
+	Open         int    `json:"open"`
+	InUse        int    `json:"in_use"`
+	Idle         int    `json:"idle"`
+	WaitCount    int64  `json:"wait_count"`
+	WaitDuration string `json:"wait_duration"`
+}
+
+// ReadStats collects runtime stats and the pool stats of the given databases (name -> db)
+func ReadStats(dbs map[string]*sql.DB) RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	uptime := time.Since(startTime)
+	s := RuntimeStats{
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: uptime.Seconds(),
+		GoVersion:     runtime.Version(),
+		NumCPU:        runtime.NumCPU(),
+		Goroutines:    runtime.NumGoroutine(),
+		Memory: MemoryStats{
+			Alloc: m.Alloc, TotalAlloc: m.TotalAlloc, Sys: m.Sys,
+			HeapAlloc: m.HeapAlloc, HeapInuse: m.HeapInuse, HeapIdle: m.HeapIdle, HeapObject: m.HeapObjects,
+			Mallocs: m.Mallocs, Frees: m.Frees,
+		},
+		GC: GCStats{
+			NumGC:        m.NumGC,
+			TotalPauseMs: float64(m.PauseTotalNs) / 1e6,
+			CPUFraction:  m.GCCPUFraction,
+			RecentPauses: []float64{},
+		},
+	}
+	if m.NumGC > 0 {
+		s.GC.LastGC = time.Unix(0, int64(m.LastGC)).UTC()
+		s.GC.LastPauseMs = float64(m.PauseNs[(m.NumGC+255)%256]) / 1e6
+		// PauseNs is a circular buffer; walk back from the most recent GC
+		for i := uint32(0); i < 10 && i < m.NumGC; i++ {
+			s.GC.RecentPauses = append(s.GC.RecentPauses, float64(m.PauseNs[(m.NumGC+255-i)%256])/1e6)
+		}
+	}
+	if len(dbs) > 0 {
+		s.Databases = make(map[string]DBStats, len(dbs))
+		for name, db := range dbs {
+			st := db.Stats()
+			s.Databases[name] = DBStats{
+				MaxOpen: st.MaxOpenConnections, Open: st.OpenConnections, InUse: st.InUse, Idle: st.Idle,
+				WaitCount: st.WaitCount, WaitDuration: st.WaitDuration.String(),
+			}
+		}
+	}
+	return s
+}
+
+// StatsHandler serves ReadStats as JSON for quick operational checks
+// Mount it behind a guard like the other debug endpoints.
+// Example:
+//
+//	mux.Handle("GET /debug/stats", guard(debug.StatsHandler(map[string]*sql.DB{"main": db})))
+func StatsHandler(dbs map[string]*sql.DB) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := json.NewEncoder(w).Encode(ReadStats(dbs)); err != nil {
+			log.Printf("failed to encode stats: %v", err)
+		}
+	})
+}
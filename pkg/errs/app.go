@@ -0,0 +1,214 @@
+package errs
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error is an application error carrying the HTTP status, a machine-readable code and a
+// message that is safe to show clients. The cause (Err) is internal: it appears in Error(),
+// logs and reports, never in responses. The stack is captured when the Error is created.
+// errors.Is matches the sentinel for its status, so errs.NotFound("product") is ErrNotFound.
+type Error struct {
+	Status  int                 // HTTP status
+	Code    string              // machine-readable code, e.g. "not_found"
+	Message string              // client-facing message
+	Fields  map[string][]string // validation messages by field (422)
+	Err     error               // cause, never rendered
+	Key     string              // i18n catalog key translating Message (see i18n.LocalizeError)
+	Args    []any               // key/value arguments of Key, e.g. "resource", "product"
+
+	stack []uintptr
+}
+
+// New creates an Error; an empty code is derived from the status ("too_many_requests")
+// Example:
+//
+//	return errs.New(http.StatusPaymentRequired, "quota_exceeded", "monthly quota exceeded")
+func New(status int, code, message string) *Error {
+	if code == "" {
+		code = StatusCodeName(status)
+	}
+	return &Error{Status: status, Code: code, Message: message, stack: callers()}
+}
+
+// NotFound returns a 404 "<resource> not found" error
+// Example:
+//
+//	if errors.Is(err, sql.ErrNoRows) {
+//		return errs.NotFound("product")
+//	}
+func NotFound(resource string) *Error {
+	return New(http.StatusNotFound, "not_found", resource+" not found").WithKey("errors.not_found", "resource", resource)
+}
+
+// Validation returns a 422 error with messages by field, rendered under "errors"
+// Example:
+//
+//	return errs.Validation(map[string][]string{"email": {"email is already registered"}})
+func Validation(fields map[string][]string) *Error {
+	e := New(http.StatusUnprocessableEntity, "validation_failed", "validation failed").WithKey("errors.validation_failed")
+	e.Fields = fields
+	return e
+}
+
+// Conflict returns a 409 error with a client-facing message
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, "conflict", message)
+}
+
+// BadRequest returns a 400 error with a client-facing message
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, "bad_request", message)
+}
+
+// Unauthorized returns a 401 error with a client-facing message
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, "unauthorized", message)
+}
+
+// Forbidden returns a 403 error with a client-facing message
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, "forbidden", message)
+}
+
+// Internal returns a 500 error hiding err behind "internal server error"
+// Example:
+//
+//	if err := tx.Commit(); err != nil {
+//		return errs.Internal(err) // err is reported, the client sees the generic message
+//	}
+func Internal(err error) *Error {
+	e := New(http.StatusInternalServerError, "internal", "internal server error").WithKey("errors.internal")
+	e.Err = err
+	return e
+}
+
+// WithKey sets the i18n catalog key, and its key/value arguments, translating the message
+// when the request has a locale (see i18n.Middleware); the message stays the fallback
+// Example:
+//
+//	return errs.Conflict("only 3 left in stock").WithKey("errors.out_of_stock", "count", 3)
+//	// id.json: {"errors": {"out_of_stock": "hanya tersisa {count}"}}
+func (e *Error) WithKey(key string, args ...any) *Error {
+	e.Key, e.Args = key, args
+	return e
+}
+
+// Wrap sets err as the internal cause and returns e
+// Example:
+//
+//	if errors.Is(err, sql.ErrNoRows) {
+//		return errs.NotFound("order").Wrap(err)
+//	}
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// Wrapf sets an internal cause built with fmt.Errorf, so %w keeps the chain, and returns e
+// Example:
+//
+//	return errs.Conflict("sku already exists").Wrapf("insert product %q: %w", p.SKU, err)
+func (e *Error) Wrapf(format string, args ...any) *Error {
+	e.Err = fmt.Errorf(format, args...)
+	return e
+}
+
+// StackTrace returns the program counters captured when e was created
+func (e *Error) StackTrace() []uintptr {
+	return e.stack
+}
+
+// Format implements fmt.Formatter: %+v prints the message, cause and stack
+func (e *Error) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+// Error returns the message followed by the cause, for logs
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode implements StatusCoder
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// Is matches the sentinel error for the status (ErrNotFound for 404, ...) and catalog
+// definitions with the same code
+func (e *Error) Is(target error) bool {
+	if d, ok := target.(*Definition); ok {
+		return e.Code == d.Code
+	}
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrConflict:
+		return e.Status == http.StatusConflict
+	case ErrValidation:
+		return e.Status == http.StatusUnprocessableEntity
+	case ErrPreconditionFailed:
+		return e.Status == http.StatusPreconditionFailed
+	case ErrPreconditionRequired:
+		return e.Status == http.StatusPreconditionRequired
+	}
+	return false
+}
+
+// From converts any error to an *Error for rendering
+// An *Error in the chain is returned as is. Other errors get their status from HTTPStatus;
+// below 500 their message is kept (sql.ErrNoRows and unique violations get generic ones),
+// from 500 up it is replaced by the status text so internal details don't reach clients.
+// Example:
+//
+//	e := errs.From(err)
+//	response.Error(w, e.Status, e.Message)
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	status := HTTPStatus(err)
+	code := StatusCodeName(status)
+	message, key := err.Error(), ""
+	switch {
+	case status >= http.StatusInternalServerError:
+		message, key = strings.ToLower(http.StatusText(status)), "errors."+code
+	// Driver messages are not meant for clients
+	case errors.Is(err, sql.ErrNoRows):
+		message, key = "resource not found", "errors.resource_not_found"
+	case IsUniqueViolation(err):
+		message, key = "resource already exists", "errors.already_exists"
+	}
+	return &Error{Status: status, Code: code, Message: message, Err: err, Key: key, stack: StackOf(err)}
+}
+
+// StatusCodeName returns the default code for an HTTP status, e.g. "not_found" for 404
+func StatusCodeName(status int) string {
+	switch status {
+	case http.StatusUnprocessableEntity:
+		return "validation_failed"
+	case http.StatusInternalServerError:
+		return "internal"
+	}
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
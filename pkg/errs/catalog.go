@@ -0,0 +1,136 @@
+package errs
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Definition is an error code declared once in the catalog
+// It implements error so errors.Is(err, def) matches any *Error with the same code.
+type Definition struct {
+	Code        string // stable machine-readable code, e.g. "out_of_stock"
+	Status      int    // HTTP status
+	Message     string // default client-facing message
+	Description string // longer explanation for the exported docs
+	builtin     bool
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]*Definition{}
+	warned    sync.Map
+)
+
+func init() {
+	for _, status := range []int{
+		http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+		http.StatusMethodNotAllowed, http.StatusNotAcceptable, http.StatusConflict, http.StatusGone,
+		http.StatusPreconditionFailed, http.StatusRequestEntityTooLarge, http.StatusUnsupportedMediaType,
+		http.StatusUnprocessableEntity, http.StatusPreconditionRequired, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	} {
+		code := StatusCodeName(status)
+		catalog[code] = &Definition{Code: code, Status: status, Message: strings.ToLower(http.StatusText(status)), builtin: true}
+	}
+	catalog["validation_failed"].Message = "validation failed"
+}
+
+// Define declares an application error code; call it at package level
+// It panics if the code was already defined by the application (built-in codes such as
+// "not_found" may be redefined to change their message or description).
+// Example:
+//
+//	var ErrOutOfStock = errs.Define("out_of_stock", http.StatusConflict, "item is out of stock").
+//		Describe("The requested quantity exceeds the available stock.")
+//
+//	return ErrOutOfStock.New()                   // 409 {"error":"item is out of stock","code":"out_of_stock"}
+//	return ErrOutOfStock.Newf("only %d left", n) // same code, custom message
+//	errors.Is(err, ErrOutOfStock)                // true for both
+func Define(code string, status int, message string) *Definition {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	if existing, ok := catalog[code]; ok && !existing.builtin {
+		panic(fmt.Sprintf("errs: error code %q is already defined", code))
+	}
+	d := &Definition{Code: code, Status: status, Message: message}
+	catalog[code] = d
+	return d
+}
+
+// Describe sets the documentation shown in the exported catalog and returns d
+func (d *Definition) Describe(description string) *Definition {
+	catalogMu.Lock()
+	d.Description = description
+	catalogMu.Unlock()
+	return d
+}
+
+// New returns an *Error with the definition's status, code and default message
+// The message is translated with the i18n key "errors.<code>" when the catalog has one.
+func (d *Definition) New() *Error {
+	return New(d.Status, d.Code, d.Message).WithKey("errors." + d.Code)
+}
+
+// Newf returns an *Error with a formatted client-facing message
+func (d *Definition) Newf(format string, args ...any) *Error {
+	return New(d.Status, d.Code, fmt.Sprintf(format, args...))
+}
+
+// Wrap returns an *Error with the default message and err as the internal cause
+func (d *Definition) Wrap(err error) *Error {
+	e := d.New()
+	e.Err = err
+	return e
+}
+
+// Error returns the default message, so a Definition can be an errors.Is target
+func (d *Definition) Error() string {
+	return d.Message
+}
+
+// Lookup returns the definition of a code
+func Lookup(code string) (Definition, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	d, ok := catalog[code]
+	if !ok {
+		return Definition{}, false
+	}
+	return *d, true
+}
+
+// Catalog returns every defined code, built-in ones included, sorted by status then code
+func Catalog() []Definition {
+	catalogMu.RLock()
+	defs := make([]Definition, 0, len(catalog))
+	for _, d := range catalog {
+		defs = append(defs, *d)
+	}
+	catalogMu.RUnlock()
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].Status != defs[j].Status {
+			return defs[i].Status < defs[j].Status
+		}
+		return defs[i].Code < defs[j].Code
+	})
+	return defs
+}
+
+// CheckCode logs codes missing from the catalog, once per code
+// The error handlers call it before rendering, so typos in codes built with New show up in logs.
+func CheckCode(code string) {
+	if code == "" {
+		return
+	}
+	if _, ok := Lookup(code); ok {
+		return
+	}
+	if _, seen := warned.LoadOrStore(code, true); !seen {
+		log.Printf("errs: error code %q is not in the catalog; declare it with errs.Define", code)
+	}
+}
@@ -0,0 +1,169 @@
+package errs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/logging"
+)
+
+// Event is a single error occurrence passed to a Reporter
+type Event struct {
+	Err       error
+	Level     string // "error", or "fatal" for recovered panics
+	Time      time.Time
+	RequestID string
+	UserID    string
+	Method    string
+	URL       string
+	Route     string
+	Body      []byte // request body prefix captured by logging.CaptureBody, if any
+	Tags      map[string]string
+	Stack     []uintptr // where the error was created (see StackOf) or reported; most recent call first
+}
+
+// Reporter sends error events to an external service (Sentry, logs, ...)
+// Implementations must be safe for concurrent use and should not block the request.
+type Reporter interface {
+	Report(ctx context.Context, e *Event)
+}
+
+// ReporterFunc adapts a function to Reporter
+type ReporterFunc func(ctx context.Context, e *Event)
+
+// Report calls f
+func (f ReporterFunc) Report(ctx context.Context, e *Event) { f(ctx, e) }
+
+var (
+	mu       sync.RWMutex
+	reporter Reporter
+)
+
+// SetReporter installs the reporter used by Report, the recovery middleware,
+// the error-returning handler adapter and response.InternalServerError. nil disables reporting.
+// Example:
+//
+//	sentry, err := errs.NewSentry(errs.SentryConfig{DSN: os.Getenv("SENTRY_DSN"), Environment: "production"})
+//	errs.SetReporter(sentry)
+//	defer sentry.Flush(2 * time.Second)
+func SetReporter(r Reporter) {
+	mu.Lock()
+	defer mu.Unlock()
+	reporter = r
+}
+
+func current() Reporter {
+	mu.RLock()
+	defer mu.RUnlock()
+	return reporter
+}
+
+// Report sends err to the installed reporter, enriched with request ID, user ID and tags from ctx
+func Report(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+	if r := current(); r != nil {
+		r.Report(ctx, newEvent(ctx, err, "error"))
+	}
+}
+
+// ReportRequest is Report with the request method, URL and route attached
+func ReportRequest(r *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	if rep := current(); rep != nil {
+		rep.Report(r.Context(), requestEvent(r, err, "error"))
+	}
+}
+
+// ReportPanic reports a value recovered from a panic at level "fatal"
+// Call it from the deferred recover so the stack still contains the panicking frames.
+func ReportPanic(r *http.Request, recovered any) {
+	rep := current()
+	if rep == nil {
+		return
+	}
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+	rep.Report(r.Context(), requestEvent(r, fmt.Errorf("panic: %w", err), "fatal"))
+}
+
+func requestEvent(r *http.Request, err error, level string) *Event {
+	e := newEvent(r.Context(), err, level)
+	e.Method = r.Method
+	e.URL = r.URL.String()
+	e.Body, _ = logging.Body(r.Context())
+	if e.Route == "" {
+		e.Route = r.Pattern
+	}
+	if e.Route == "" {
+		e.Route = r.URL.Path
+	}
+	return e
+}
+
+func newEvent(ctx context.Context, err error, level string) *Event {
+	e := &Event{
+		Err:       err,
+		Level:     level,
+		Time:      time.Now().UTC(),
+		RequestID: logging.RequestID(ctx),
+		Tags:      map[string]string{},
+		Stack:     StackOf(err),
+	}
+	if e.Stack == nil {
+		e.Stack = callers()
+	}
+	if s := scopeFrom(ctx); s != nil {
+		s.mu.Lock()
+		e.UserID = s.userID
+		e.Route = s.route
+		for k, v := range s.tags {
+			e.Tags[k] = v
+		}
+		s.mu.Unlock()
+	}
+	return e
+}
+
+// callers captures the stack, skipping frames inside this package
+func callers() []uintptr {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(3, pcs)
+	pcs = pcs[:n]
+	frames := runtime.CallersFrames(pcs)
+	skip := 0
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "github.com/yoockh/go-api-utils/pkg/errs.") {
+			break
+		}
+		skip++
+		if !more {
+			break
+		}
+	}
+	return pcs[skip:]
+}
+
+// LogReporter writes events to the request-scoped logger (useful in development)
+func LogReporter() Reporter {
+	return ReporterFunc(func(ctx context.Context, e *Event) {
+		logging.FromContext(ctx).Error("error reported",
+			slog.String("level", e.Level),
+			slog.String("error", e.Err.Error()),
+			slog.String("user_id", e.UserID),
+			slog.String("route", e.Route),
+		)
+	})
+}
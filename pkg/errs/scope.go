@@ -0,0 +1,64 @@
+package errs
+
+import (
+	"context"
+	"sync"
+)
+
+// scope holds per-request data filled in by inner middleware (e.g. the authenticated user)
+// It is mutable so that outer middleware like Recover see values set further down the chain.
+type scope struct {
+	mu     sync.Mutex
+	userID string
+	route  string
+	tags   map[string]string
+}
+
+type scopeKey struct{}
+
+// WithScope returns ctx carrying an error-reporting scope; returns ctx unchanged if it already has one
+func WithScope(ctx context.Context) context.Context {
+	if scopeFrom(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeKey{}, &scope{tags: map[string]string{}})
+}
+
+// SetUser records the authenticated user ID on the scope in ctx (no-op without a scope)
+// Example:
+//
+//	ctx := errs.WithScope(r.Context())
+//	errs.SetUser(ctx, strconv.Itoa(int(userID)))
+func SetUser(ctx context.Context, userID string) {
+	if s := scopeFrom(ctx); s != nil {
+		s.mu.Lock()
+		s.userID = userID
+		s.mu.Unlock()
+	}
+}
+
+// SetRoute records the matched route pattern for frameworks that don't set http.Request.Pattern
+func SetRoute(ctx context.Context, route string) {
+	if s := scopeFrom(ctx); s != nil {
+		s.mu.Lock()
+		s.route = route
+		s.mu.Unlock()
+	}
+}
+
+// SetTag adds a tag reported with every event for this request (no-op without a scope)
+func SetTag(ctx context.Context, key, value string) {
+	if s := scopeFrom(ctx); s != nil {
+		s.mu.Lock()
+		s.tags[key] = value
+		s.mu.Unlock()
+	}
+}
+
+func scopeFrom(ctx context.Context) *scope {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(scopeKey{}).(*scope)
+	return s
+}
@@ -0,0 +1,248 @@
+package errs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SentryConfig configures the Sentry reporter
+type SentryConfig struct {
+	DSN         string // https://<public_key>@<host>/<project_id>
+	Environment string
+	Release     string
+	ServerName  string
+	Timeout     time.Duration // per-event HTTP timeout (default 5s)
+	HTTPClient  *http.Client
+	QueueSize   int // events waiting to be sent (default 100); further events are dropped
+}
+
+// Sentry reports events to Sentry (or a Sentry-compatible service such as GlitchTip)
+// using the envelope API; no SDK required. Events are sent in the background by a single
+// worker from a bounded queue, so an error storm or a slow Sentry can't pile up goroutines.
+type Sentry struct {
+	cfg      SentryConfig
+	endpoint string
+	auth     string
+	client   *http.Client
+	queue    chan sentryJob
+	dropped  atomic.Int64
+}
+
+// sentryJob is an encoded event, or a Flush marker when done is set
+type sentryJob struct {
+	body []byte
+	done chan struct{}
+}
+
+// NewSentry creates a Sentry reporter from a DSN
+// Example:
+//
+//	sentry, err := errs.NewSentry(errs.SentryConfig{DSN: os.Getenv("SENTRY_DSN")})
+//	errs.SetReporter(sentry)
+func NewSentry(cfg SentryConfig) (*Sentry, error) {
+	u, err := url.Parse(cfg.DSN)
+	if err != nil || u.User == nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid sentry DSN")
+	}
+	projectID := strings.Trim(u.Path[strings.LastIndex(u.Path, "/")+1:], "/")
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid sentry DSN: missing project ID")
+	}
+	pathPrefix := strings.TrimSuffix(u.Path[:strings.LastIndex(u.Path, "/")+1], "/")
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+	s := &Sentry{
+		cfg:      cfg,
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, pathPrefix, projectID),
+		auth:     "Sentry sentry_version=7, sentry_client=go-api-utils/1.0, sentry_key=" + u.User.Username(),
+		client:   client,
+		queue:    make(chan sentryJob, cfg.QueueSize),
+	}
+	go s.run()
+	return s, nil
+}
+
+// run sends queued events one at a time and releases Flush markers in order
+func (s *Sentry) run() {
+	for job := range s.queue {
+		if job.done != nil {
+			close(job.done)
+			continue
+		}
+		if err := s.send(job.body); err != nil {
+			log.Printf("sentry: failed to send event: %v", err)
+		}
+	}
+}
+
+// Report queues e for sending; failures are logged, never returned
+// When the queue is full the event is dropped (and counted in the log) rather than
+// blocking the request.
+func (s *Sentry) Report(ctx context.Context, e *Event) {
+	body, err := s.envelope(e)
+	if err != nil {
+		log.Printf("sentry: failed to encode event: %v", err)
+		return
+	}
+	select {
+	case s.queue <- sentryJob{body: body}:
+	default:
+		n := s.dropped.Add(1)
+		if n == 1 || n%100 == 0 {
+			log.Printf("sentry: queue full, %d events dropped so far", n)
+		}
+	}
+}
+
+// Flush waits up to timeout for the events queued before the call; returns false if it
+// timed out. Call it before the process exits.
+func (s *Sentry) Flush(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	done := make(chan struct{})
+	select {
+	case s.queue <- sentryJob{done: done}:
+	case <-timer.C:
+		return false
+	}
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (s *Sentry) send(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+func (s *Sentry) envelope(e *Event) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	eventID := hex.EncodeToString(id)
+
+	// Sentry expects frames ordered oldest call first
+	var frames []sentryFrame
+	iter := runtime.CallersFrames(e.Stack)
+	for {
+		f, more := iter.Next()
+		if f.Function != "" {
+			module, fn := splitFunction(f.Function)
+			frames = append([]sentryFrame{{
+				Function: fn, Module: module, AbsPath: f.File, Lineno: f.Line,
+				InApp: !strings.HasPrefix(f.Function, "runtime.") && strings.Contains(module, "."),
+			}}, frames...)
+		}
+		if !more {
+			break
+		}
+	}
+
+	tags := map[string]string{}
+	for k, v := range e.Tags {
+		tags[k] = v
+	}
+	if e.RequestID != "" {
+		tags["request_id"] = e.RequestID
+	}
+	if e.Route != "" {
+		tags["route"] = e.Route
+	}
+
+	event := map[string]any{
+		"event_id":    eventID,
+		"timestamp":   e.Time.Format(time.RFC3339Nano),
+		"level":       e.Level,
+		"platform":    "go",
+		"environment": s.cfg.Environment,
+		"release":     s.cfg.Release,
+		"server_name": s.cfg.ServerName,
+		"tags":        tags,
+		"exception": map[string]any{
+			"values": []map[string]any{{
+				"type":       fmt.Sprintf("%T", e.Err),
+				"value":      e.Err.Error(),
+				"stacktrace": map[string]any{"frames": frames},
+			}},
+		},
+	}
+	if e.UserID != "" {
+		event["user"] = map[string]string{"id": e.UserID}
+	}
+	if e.Method != "" {
+		req := map[string]string{"method": e.Method, "url": e.URL}
+		if len(e.Body) > 0 {
+			req["data"] = string(e.Body)
+		}
+		event["request"] = req
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, part := range []any{
+		map[string]string{"event_id": eventID, "sent_at": time.Now().UTC().Format(time.RFC3339Nano)},
+		map[string]string{"type": "event"},
+		event,
+	} {
+		if err := enc.Encode(part); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// splitFunction splits "github.com/x/y/pkg.(*T).Method" into module and function
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+2+dot:]
+}
@@ -0,0 +1,95 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+)
+
+// stackTracer is implemented by errors that captured the stack where they were created
+type stackTracer interface {
+	StackTrace() []uintptr
+}
+
+// wrapped adds internal context to an error without changing what clients see
+type wrapped struct {
+	msg   string
+	err   error
+	stack []uintptr
+}
+
+// Wrap annotates err with internal context, like fmt.Errorf("msg: %w", err)
+// The stack is captured here unless err already carries one. A typed *Error in the chain
+// still decides the status and client message, so the context only reaches logs and reports.
+// Returns nil when err is nil.
+// Example:
+//
+//	if err := repo.Save(ctx, order); err != nil {
+//		return errs.Wrap(err, "save order %d", order.ID)
+//	}
+func Wrap(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	w := &wrapped{msg: fmt.Sprintf(format, args...), err: err}
+	if StackOf(err) == nil {
+		w.stack = callers()
+	}
+	return w
+}
+
+func (w *wrapped) Error() string { return w.msg + ": " + w.err.Error() }
+
+func (w *wrapped) Unwrap() error { return w.err }
+
+func (w *wrapped) StackTrace() []uintptr { return w.stack }
+
+// Format prints the stack with %+v
+func (w *wrapped) Format(s fmt.State, verb rune) { format(s, verb, w) }
+
+// StackOf returns the stack captured deepest in err's chain (closest to where the error
+// was created), or nil when no error in the chain captured one
+func StackOf(err error) []uintptr {
+	var stack []uintptr
+	for err != nil {
+		if st, ok := err.(stackTracer); ok && len(st.StackTrace()) > 0 {
+			stack = st.StackTrace()
+		}
+		err = errors.Unwrap(err)
+	}
+	return stack
+}
+
+// FormatStack renders program counters as "function\n\tfile:line" lines
+func FormatStack(stack []uintptr) string {
+	if len(stack) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+// format implements fmt.Formatter: %+v adds the stack, other verbs print the message
+func format(s fmt.State, verb rune, err error) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, err.Error())
+		if stack := StackOf(err); len(stack) > 0 {
+			io.WriteString(s, "\n"+FormatStack(stack))
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	default:
+		io.WriteString(s, err.Error())
+	}
+}
@@ -0,0 +1,92 @@
+package errs
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors understood by the error handlers
+// Wrap them to add context; the wrapped message is what the client sees.
+// Example:
+//
+//	return fmt.Errorf("product %d: %w", id, errs.ErrNotFound) // 404 "product 42: not found"
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+
+	ErrPreconditionFailed   = errors.New("precondition failed")   // 412, If-Match mismatch
+	ErrPreconditionRequired = errors.New("precondition required") // 428, missing If-Match
+)
+
+// StatusCoder is implemented by errors that carry their own HTTP status
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// HTTPStatus maps err to an HTTP status code
+// StatusCoder wins, then the sentinels above, sql.ErrNoRows (404) and unique
+// constraint violations (409). Everything else is 500.
+func HTTPStatus(err error) int {
+	var sc StatusCoder
+	switch {
+	case errors.As(err, &sc):
+		return sc.StatusCode()
+	case errors.Is(err, ErrNotFound), errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict), IsUniqueViolation(err):
+		return http.StatusConflict
+	case errors.Is(err, ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, ErrPreconditionRequired):
+		return http.StatusPreconditionRequired
+	}
+	return http.StatusInternalServerError
+}
+
+// IsUniqueViolation reports whether err is a PostgreSQL unique_violation (SQLSTATE 23505)
+// Works with lib/pq and pgx errors, which both expose SQLState().
+func IsUniqueViolation(err error) bool {
+	var se interface{ SQLState() string }
+	return errors.As(err, &se) && se.SQLState() == "23505"
+}
+
+// IsSerializationFailure reports whether err is a PostgreSQL serialization_failure (SQLSTATE 40001)
+// Transactions failing with it under REPEATABLE READ or SERIALIZABLE must be retried as a whole.
+func IsSerializationFailure(err error) bool {
+	var se interface{ SQLState() string }
+	return errors.As(err, &se) && se.SQLState() == "40001"
+}
+
+// IsDeadlock reports whether err is a PostgreSQL deadlock_detected (SQLSTATE 40P01)
+func IsDeadlock(err error) bool {
+	var se interface{ SQLState() string }
+	return errors.As(err, &se) && se.SQLState() == "40P01"
+}
+
+// IsRetryableTx reports whether the transaction that returned err can succeed if run again
+func IsRetryableTx(err error) bool {
+	return IsSerializationFailure(err) || IsDeadlock(err)
+}
+
+// IsConnectionFailure reports whether err means the database could not be reached yet:
+// network errors (refused, reset, timeout, DNS), a dropped connection, or the SQLSTATEs
+// of a server that is starting up or full (class 08, 57P03, 53300). Authentication
+// failures, unknown databases and other server errors are permanent and report false.
+func IsConnectionFailure(err error) bool {
+	var se interface{ SQLState() string }
+	if errors.As(err, &se) {
+		code := se.SQLState()
+		return strings.HasPrefix(code, "08") || code == "57P03" || code == "53300"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,293 @@
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/repository"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrCycle is returned when the fixture tables reference each other in a foreign key cycle
+var ErrCycle = errors.New("fixtures: foreign key cycle between tables")
+
+// Row is one fixture row: column name -> value
+// Nested objects and arrays are stored as JSON, for json/jsonb columns.
+type Row map[string]interface{}
+
+// Set is a collection of fixture rows grouped by table
+type Set struct {
+	tables map[string][]Row
+	order  []string // tables in the order they were first loaded
+}
+
+// Load reads fixture files matching the patterns (default "*.yml", "*.yaml", "*.json")
+// A file holding a list fills the table named after the file (users.yml -> users);
+// a file holding a mapping lists several tables. An empty list only clears the table.
+// Example:
+//
+//	# fixtures/users.yml
+//	- id: 1
+//	  email: admin@example.com
+//	  settings: {theme: dark}
+//
+//	# fixtures/shop.yml
+//	products:
+//	  - {id: 1, name: Pen, price: 1.5}
+//	orders: []
+//
+//	set, err := fixtures.Load(os.DirFS("fixtures"))
+func Load(fsys fs.FS, patterns ...string) (*Set, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"*.yml", "*.yaml", "*.json"}
+	}
+	var names []string
+	for _, p := range patterns {
+		matches, err := fs.Glob(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, matches...)
+	}
+	sort.Strings(names)
+
+	s := &Set{tables: map[string][]Row{}}
+	for _, name := range slices.Compact(names) {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.parse(name, data); err != nil {
+			return nil, fmt.Errorf("fixtures: %s: %w", name, err)
+		}
+	}
+	return s, nil
+}
+
+// MustLoad is Load that panics on error, for package-level fixture sets
+func MustLoad(fsys fs.FS, patterns ...string) *Set {
+	s, err := Load(fsys, patterns...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Add appends rows to a table, for fixtures built in code
+func (s *Set) Add(table string, rows ...Row) *Set {
+	if s.tables == nil {
+		s.tables = map[string][]Row{}
+	}
+	if _, ok := s.tables[table]; !ok {
+		s.order = append(s.order, table)
+	}
+	s.tables[table] = append(s.tables[table], rows...)
+	return s
+}
+
+// Tables returns the table names in the set
+func (s *Set) Tables() []string {
+	return slices.Clone(s.order)
+}
+
+// Rows returns the fixture rows of a table
+func (s *Set) Rows(table string) []Row {
+	return s.tables[table]
+}
+
+// parse accepts JSON as well, since JSON documents are valid YAML
+func (s *Set) parse(name string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	switch root := doc.Content[0]; root.Kind {
+	case yaml.SequenceNode:
+		var rows []Row
+		if err := root.Decode(&rows); err != nil {
+			return err
+		}
+		s.Add(strings.TrimSuffix(path.Base(name), path.Ext(name)), rows...)
+	case yaml.MappingNode:
+		// Walk the node to keep the file's table order
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			var rows []Row
+			if err := root.Content[i+1].Decode(&rows); err != nil {
+				return fmt.Errorf("table %s: %w", root.Content[i].Value, err)
+			}
+			s.Add(root.Content[i].Value, rows...)
+		}
+	default:
+		return errors.New("expected a list of rows or a mapping of table -> rows")
+	}
+	return nil
+}
+
+// Apply replaces the contents of the fixture tables with the fixture rows
+// Tables are cleared children-first and filled parents-first using the foreign keys in
+// pg_constraint; serial sequences are moved past the inserted ids. With a *sql.DB everything
+// runs in one transaction; pass a *sql.Tx (e.g. dbtest.Tx) to roll it back with the test.
+// Tables outside the set that reference fixture tables are not cleared; add them as empty lists.
+// Example:
+//
+//	// seed local demo data
+//	if err := fixtures.MustLoad(os.DirFS("fixtures")).Apply(ctx, db); err != nil {
+//		log.Fatal(err)
+//	}
+func (s *Set) Apply(ctx context.Context, db repository.DBTX) error {
+	if beginner, ok := db.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+	}); ok {
+		tx, err := beginner.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		if err := s.Apply(ctx, tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+
+	order, err := s.insertOrder(ctx, db)
+	if err != nil {
+		return err
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		if _, err := db.ExecContext(ctx, "DELETE FROM "+order[i]); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", order[i], err)
+		}
+	}
+	for _, table := range order {
+		for i, row := range s.tables[table] {
+			query, args, err := insertQuery(table, row)
+			if err != nil {
+				return fmt.Errorf("fixtures: %s[%d]: %w", table, i, err)
+			}
+			if _, err := db.ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to insert %s[%d]: %w", table, i, err)
+			}
+		}
+		if hasColumn(s.tables[table], "id") {
+			// No-op when id has no sequence: setval(NULL, ...) returns NULL
+			_, err := db.ExecContext(ctx, fmt.Sprintf(
+				"SELECT setval(pg_get_serial_sequence('%s', 'id'), MAX(id)) FROM %s HAVING MAX(id) IS NOT NULL", table, table))
+			if err != nil {
+				return fmt.Errorf("failed to reset %s id sequence: %w", table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// insertOrder sorts the set's tables so referenced tables come before the tables referencing them
+func (s *Set) insertOrder(ctx context.Context, db repository.DBTX) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT conrelid::regclass::text, confrelid::regclass::text FROM pg_constraint WHERE contype = 'f'")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read foreign keys: %w", err)
+	}
+	defer rows.Close()
+	parents := map[string][]string{}
+	for rows.Next() {
+		var child, parent string
+		if err := rows.Scan(&child, &parent); err != nil {
+			return nil, err
+		}
+		if child != parent { // self references are satisfied by the file's row order
+			parents[child] = append(parents[child], parent)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sortTables(s.order, parents)
+}
+
+// sortTables orders tables parents-first, keeping the load order between unrelated tables
+func sortTables(tables []string, parents map[string][]string) ([]string, error) {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	inSet := map[string]bool{}
+	for _, t := range tables {
+		inSet[t] = true
+	}
+	order := make([]string, 0, len(tables))
+	var visit func(t string, path []string) error
+	visit = func(t string, path []string) error {
+		switch state[t] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrCycle, strings.Join(append(path, t), " -> "))
+		}
+		state[t] = visiting
+		for _, p := range parents[t] {
+			if inSet[p] {
+				if err := visit(p, append(path, t)); err != nil {
+					return err
+				}
+			}
+		}
+		state[t] = done
+		order = append(order, t)
+		return nil
+	}
+	for _, t := range tables {
+		if err := visit(t, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func insertQuery(table string, row Row) (string, []interface{}, error) {
+	columns := make([]string, 0, len(row))
+	for col := range row {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+	placeholders := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, col := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		switch v := row[col].(type) {
+		case Row, map[string]interface{}, []interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", nil, fmt.Errorf("column %s: %w", col, err)
+			}
+			args[i] = string(data)
+		default:
+			args[i] = v
+		}
+	}
+	if len(columns) == 0 {
+		return "INSERT INTO " + table + " DEFAULT VALUES", nil, nil
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", ")), args, nil
+}
+
+func hasColumn(rows []Row, col string) bool {
+	for _, row := range rows {
+		if _, ok := row[col]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,210 @@
+package graceful
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/options"
+)
+
+// ErrDraining is returned by Track once draining has started; it maps to 503 via
+// errs.HTTPStatus, so new WebSocket and SSE clients are turned away during shutdown
+var ErrDraining error = drainingError{}
+
+type drainingError struct{}
+
+func (drainingError) Error() string   { return "server is shutting down" }
+func (drainingError) StatusCode() int { return http.StatusServiceUnavailable }
+
+// Group tracks long-lived connections (WebSocket, SSE) that http.Server.Shutdown doesn't
+// wait for or can't end: hijacked connections are invisible to it and streams never go idle
+// Pass it as ws.Config.Drain and sse.Config.Drain, then drain it with Shutdown.
+type Group struct {
+	mu       sync.Mutex
+	conns    map[*tracked]struct{}
+	draining chan struct{}
+	started  bool
+	empty    chan struct{} // closed when draining and no connection is left
+}
+
+type tracked struct {
+	forceClose func()
+}
+
+// NewGroup creates an empty Group
+func NewGroup() *Group {
+	return &Group{
+		conns:    map[*tracked]struct{}{},
+		draining: make(chan struct{}),
+		empty:    make(chan struct{}),
+	}
+}
+
+// Track registers a connection; release must be called once it is closed
+// forceClose ends the connection at once and is called when the drain deadline passes.
+// Track fails with ErrDraining after draining started.
+func (g *Group) Track(forceClose func()) (release func(), err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started {
+		return nil, ErrDraining
+	}
+	t := &tracked{forceClose: forceClose}
+	g.conns[t] = struct{}{}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			delete(g.conns, t)
+			if g.started && len(g.conns) == 0 {
+				close(g.empty)
+			}
+		})
+	}, nil
+}
+
+// Draining is closed when draining starts; connections then say goodbye to their clients
+// (ws: close frame 1001 "going away", sse: a "close" event) and close
+func (g *Group) Draining() <-chan struct{} {
+	return g.draining
+}
+
+// Active returns the number of tracked connections
+func (g *Group) Active() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.conns)
+}
+
+// start begins draining; it is idempotent
+func (g *Group) start() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started {
+		return
+	}
+	g.started = true
+	close(g.draining)
+	if len(g.conns) == 0 {
+		close(g.empty)
+	}
+}
+
+// Drain starts draining and waits until every connection has closed or ctx is done
+// When ctx ends first the remaining connections are force-closed and an error reporting
+// how many is returned.
+func (g *Group) Drain(ctx context.Context) error {
+	g.start()
+	select {
+	case <-g.empty:
+		return nil
+	case <-ctx.Done():
+	}
+
+	g.mu.Lock()
+	remaining := make([]*tracked, 0, len(g.conns))
+	for t := range g.conns {
+		remaining = append(remaining, t)
+	}
+	g.mu.Unlock()
+	for _, t := range remaining {
+		t.forceClose()
+	}
+	return fmt.Errorf("force-closed %d connections after the drain deadline: %w", len(remaining), ctx.Err())
+}
+
+// Shutdown stops srv and drains groups within timeout
+// srv stops accepting connections at once, the groups tell their clients to go away, and
+// both in-flight requests and long-lived connections get until timeout to finish; whatever
+// is left then is force-closed.
+// Example:
+//
+//	drain := graceful.NewGroup()
+//	mux.Handle("GET /ws", ws.Handler(ws.Config{JWT: &jwtConfig, Drain: drain}, chat))
+//	mux.Handle("GET /events", sse.Handler(sse.Config{JWT: &jwtConfig, Drain: drain}, events))
+//
+//	srv := &http.Server{Addr: ":8080", Handler: mux}
+//	go srv.ListenAndServe()
+//	<-ctx.Done() // signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+//	if err := graceful.Shutdown(srv, 30*time.Second, drain); err != nil {
+//		log.Printf("shutdown: %v", err)
+//	}
+func Shutdown(srv *http.Server, timeout time.Duration, groups ...*Group) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, g := range groups {
+		g.start() // every group says goodbye right away, not one after another
+	}
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- srv.Shutdown(ctx) }() // closes the listeners immediately
+
+	var errList []error
+	for _, g := range groups {
+		if err := g.Drain(ctx); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	if err := <-shutdown; err != nil {
+		srv.Close()
+		errList = append(errList, fmt.Errorf("failed to shut down server: %w", err))
+	}
+	return errors.Join(errList...)
+}
+
+// ListenAndServe runs srv until ctx is done, then calls Shutdown with timeout
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+//	defer stop()
+//	srv := &http.Server{Addr: ":" + port, Handler: handler}
+//	if err := graceful.ListenAndServe(ctx, srv, 30*time.Second, drain); err != nil {
+//		log.Fatal(err)
+//	}
+func ListenAndServe(ctx context.Context, srv *http.Server, timeout time.Duration, groups ...*Group) error {
+	serve := make(chan error, 1)
+	go func() { serve <- srv.ListenAndServe() }()
+	select {
+	case err := <-serve:
+		return err
+	case <-ctx.Done():
+	}
+	err := Shutdown(srv, timeout, groups...)
+	if serr := <-serve; !errors.Is(serr, http.ErrServerClosed) {
+		err = errors.Join(err, serr)
+	}
+	return err
+}
+
+// NewServer creates an http.Server with timeouts set, so slow or idle clients can't hold
+// connections forever
+// Options: options.WithTimeout for reads and writes (default 30s; raise it for long
+// downloads, pkg/sse and pkg/ws manage their own deadlines), options.WithLogger for the
+// server's error log (TLS handshake failures, handler panics).
+// Example:
+//
+//	srv := graceful.NewServer(":"+port, handler, options.WithTimeout(time.Minute), options.WithLogger(logger))
+//	err := graceful.ListenAndServe(ctx, srv, 30*time.Second, drain)
+func NewServer(addr string, handler http.Handler, opts ...options.Option) *http.Server {
+	o := options.Apply(opts...)
+	timeout := cmp.Or(o.Timeout, 30*time.Second)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: min(timeout, 10*time.Second),
+		ReadTimeout:       timeout,
+		WriteTimeout:      timeout,
+		IdleTimeout:       2 * time.Minute,
+	}
+	if o.Logger != nil {
+		srv.ErrorLog = slog.NewLogLogger(o.Logger.Handler(), slog.LevelError)
+	}
+	return srv
+}
@@ -0,0 +1,309 @@
+package hub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/sse"
+	"github.com/yoockh/go-api-utils/pkg/ws"
+)
+
+// Message is one broadcast
+// Topic and UserID select the receivers: the subscribers of Topic, the connections of
+// UserID, the connections of UserID subscribed to Topic, or everyone when both are empty.
+type Message struct {
+	Topic  string          `json:"topic,omitempty"`
+	Event  string          `json:"event,omitempty"`
+	Data   json.RawMessage `json:"data"`
+	UserID uint            `json:"user_id,omitempty"`
+}
+
+// Subscriber receives messages from a Hub; WS and SSE adapt connections and streams
+type Subscriber interface {
+	// UserID is the authenticated user of the subscriber, 0 for anonymous ones
+	UserID() uint
+	// Done is closed when the subscriber goes away; the hub then forgets it
+	Done() <-chan struct{}
+	// Deliver queues m without blocking; an error drops the subscriber from the hub
+	Deliver(m Message) error
+}
+
+// WS adapts a WebSocket connection; messages arrive as JSON text messages
+// {"topic": ..., "event": ..., "data": ...}
+func WS(c *ws.Conn) Subscriber {
+	return wsSubscriber{c}
+}
+
+type wsSubscriber struct{ *ws.Conn }
+
+func (s wsSubscriber) Deliver(m Message) error {
+	m.UserID = 0
+	return s.SendJSON(m)
+}
+
+// SSE adapts an event stream; messages arrive as events named after Message.Event (or the
+// topic when Event is empty) with Data as their data
+func SSE(s *sse.Stream) Subscriber {
+	return sseSubscriber{s}
+}
+
+type sseSubscriber struct{ *sse.Stream }
+
+func (s sseSubscriber) Deliver(m Message) error {
+	name := m.Event
+	if name == "" {
+		name = m.Topic
+	}
+	return s.Send(name, m.Data)
+}
+
+// Bridge relays broadcasts between the hubs of several replicas (see NewRedis)
+type Bridge interface {
+	// Publish sends payload to every hub on the bridge, this one included
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe calls fn with every payload published on the bridge until ctx is done or the
+	// connection fails
+	Subscribe(ctx context.Context, fn func(payload []byte)) error
+}
+
+// Config configures a Hub
+type Config struct {
+	// Bridge relays broadcasts to the other replicas; nil keeps them in this process
+	Bridge Bridge
+	// OnDrop is called when a subscriber is dropped because Deliver failed, typically a
+	// client too slow to keep up (ws.ErrSlowClient, sse.ErrSlowClient)
+	OnDrop func(sub Subscriber, err error)
+}
+
+// Hub fans messages out to WebSocket and SSE subscribers by topic and by user
+// Delivery never blocks: every subscriber has its own send buffer and a subscriber whose
+// buffer is full is closed and dropped instead of slowing the broadcast down.
+type Hub struct {
+	cfg    Config
+	origin string // tags this hub's bridge messages so they aren't delivered twice
+
+	mu     sync.RWMutex
+	subs   map[Subscriber]map[string]bool // subscriber -> topics
+	topics map[string]map[Subscriber]bool
+	users  map[uint]map[Subscriber]bool
+}
+
+// envelope is a message on the bridge
+type envelope struct {
+	Origin  string  `json:"origin"`
+	Message Message `json:"message"`
+}
+
+// New creates a Hub; with a Bridge it relays messages from the other replicas until ctx is done
+// Example:
+//
+//	bridge, _ := hub.NewRedis(hub.RedisConfig{Addr: os.Getenv("REDIS_ADDR")})
+//	h := hub.New(ctx, hub.Config{Bridge: bridge})
+//
+//	mux.Handle("GET /ws", ws.Handler(wsCfg, func(c *ws.Conn) {
+//		h.Subscribe(hub.WS(c), "orders")
+//		for {
+//			if _, _, err := c.ReadMessage(); err != nil {
+//				return
+//			}
+//		}
+//	}))
+//	mux.Handle("GET /events", sse.Handler(sseCfg, func(s *sse.Stream) {
+//		h.Subscribe(hub.SSE(s), "orders")
+//		<-s.Done()
+//	}))
+//
+//	// in the order service, on any replica
+//	h.Publish(ctx, "orders", "order.shipped", order)
+//	h.PublishToUser(ctx, order.UserID, "order.status", order)
+func New(ctx context.Context, cfg Config) *Hub {
+	var b [8]byte
+	rand.Read(b[:])
+	h := &Hub{
+		cfg:    cfg,
+		origin: hex.EncodeToString(b[:]),
+		subs:   map[Subscriber]map[string]bool{},
+		topics: map[string]map[Subscriber]bool{},
+		users:  map[uint]map[Subscriber]bool{},
+	}
+	if cfg.Bridge != nil {
+		go h.relay(ctx)
+	}
+	return h
+}
+
+// Subscribe adds sub to topics; without topics it only receives messages for its user and
+// broadcasts to everyone
+// Subscribing again adds topics. The subscriber is forgotten when its Done channel closes.
+func (h *Hub) Subscribe(sub Subscriber, topics ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subscribed, ok := h.subs[sub]
+	if !ok {
+		subscribed = map[string]bool{}
+		h.subs[sub] = subscribed
+		add(h.users, sub.UserID(), sub)
+		go func() {
+			<-sub.Done()
+			h.Unsubscribe(sub)
+		}()
+	}
+	for _, topic := range topics {
+		subscribed[topic] = true
+		add(h.topics, topic, sub)
+	}
+}
+
+// Unsubscribe removes sub from topics, or from the hub when no topics are given
+func (h *Hub) Unsubscribe(sub Subscriber, topics ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subscribed, ok := h.subs[sub]
+	if !ok {
+		return
+	}
+	if len(topics) == 0 {
+		for topic := range subscribed {
+			topics = append(topics, topic)
+		}
+		delete(h.subs, sub)
+		remove(h.users, sub.UserID(), sub)
+	}
+	for _, topic := range topics {
+		delete(subscribed, topic)
+		remove(h.topics, topic, sub)
+	}
+}
+
+// Subscribers returns the number of subscribers in this process
+func (h *Hub) Subscribers() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
+
+// Publish sends v, encoded as JSON, to the subscribers of topic
+func (h *Hub) Publish(ctx context.Context, topic, event string, v interface{}) error {
+	return h.publish(ctx, Message{Topic: topic, Event: event}, v)
+}
+
+// PublishToUser sends v, encoded as JSON, to every connection of userID whatever its topics
+func (h *Hub) PublishToUser(ctx context.Context, userID uint, event string, v interface{}) error {
+	return h.publish(ctx, Message{Event: event, UserID: userID}, v)
+}
+
+func (h *Hub) publish(ctx context.Context, m Message, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode hub message: %w", err)
+	}
+	m.Data = data
+	return h.Send(ctx, m)
+}
+
+// Send delivers m to the matching subscribers of this process, then publishes it on the Bridge
+// Local delivery doesn't depend on the bridge; its error only means the other replicas
+// missed m.
+func (h *Hub) Send(ctx context.Context, m Message) error {
+	h.deliver(m)
+	if h.cfg.Bridge == nil {
+		return nil
+	}
+	payload, err := json.Marshal(envelope{Origin: h.origin, Message: m})
+	if err != nil {
+		return fmt.Errorf("failed to encode hub message: %w", err)
+	}
+	if err := h.cfg.Bridge.Publish(ctx, payload); err != nil {
+		return fmt.Errorf("failed to publish hub message: %w", err)
+	}
+	return nil
+}
+
+// deliver fans m out to the local subscribers it targets, dropping those that fail
+func (h *Hub) deliver(m Message) {
+	h.mu.RLock()
+	var targets []Subscriber
+	switch {
+	case m.Topic != "" && m.UserID != 0:
+		for sub := range h.users[m.UserID] {
+			if h.subs[sub][m.Topic] {
+				targets = append(targets, sub)
+			}
+		}
+	case m.Topic != "":
+		targets = keys(h.topics[m.Topic])
+	case m.UserID != 0:
+		targets = keys(h.users[m.UserID])
+	default:
+		for sub := range h.subs {
+			targets = append(targets, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range targets {
+		if err := sub.Deliver(m); err != nil {
+			h.Unsubscribe(sub)
+			if h.cfg.OnDrop != nil && !errors.Is(err, ws.ErrClosed) && !errors.Is(err, sse.ErrClosed) {
+				h.cfg.OnDrop(sub, err)
+			}
+		}
+	}
+}
+
+// relay delivers the bridge messages of other replicas, reconnecting with backoff
+func (h *Hub) relay(ctx context.Context) {
+	backoff := 100 * time.Millisecond
+	for {
+		start := time.Now()
+		err := h.cfg.Bridge.Subscribe(ctx, func(payload []byte) {
+			var e envelope
+			if json.Unmarshal(payload, &e) != nil || e.Origin == h.origin {
+				return
+			}
+			h.deliver(e.Message)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > time.Minute {
+			backoff = 100 * time.Millisecond
+		}
+		log.Printf("hub: bridge subscription failed: %v (retrying in %s)", err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func add[K comparable](m map[K]map[Subscriber]bool, k K, sub Subscriber) {
+	if m[k] == nil {
+		m[k] = map[Subscriber]bool{}
+	}
+	m[k][sub] = true
+}
+
+func remove[K comparable](m map[K]map[Subscriber]bool, k K, sub Subscriber) {
+	delete(m[k], sub)
+	if len(m[k]) == 0 {
+		delete(m, k)
+	}
+}
+
+func keys(m map[Subscriber]bool) []Subscriber {
+	out := make([]Subscriber, 0, len(m))
+	for sub := range m {
+		out = append(out, sub)
+	}
+	return out
+}
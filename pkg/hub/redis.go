@@ -0,0 +1,241 @@
+package hub
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisConfig configures the Redis pub/sub bridge
+type RedisConfig struct {
+	Addr      string // host:port (default "localhost:6379")
+	Username  string // ACL user; empty uses AUTH with the password only
+	Password  string
+	TLSConfig *tls.Config // nil for plain TCP
+	Channel   string      // pub/sub channel shared by the replicas (default "hub")
+
+	DialTimeout time.Duration // default 5s
+	// WriteTimeout bounds a PUBLISH when ctx has no earlier deadline (default 5s)
+	WriteTimeout time.Duration
+}
+
+// Redis is a Bridge over Redis PUBLISH/SUBSCRIBE, speaking RESP directly
+// Publishes share one connection, redialed after a failure; each Subscribe holds its own.
+type Redis struct {
+	cfg RedisConfig
+
+	mu  sync.Mutex // guards pub
+	pub *redisConn
+}
+
+// NewRedis creates a Redis bridge; connections are dialed on first use
+func NewRedis(cfg RedisConfig) (*Redis, error) {
+	if cfg.Addr == "" {
+		cfg.Addr = "localhost:6379"
+	}
+	if _, _, err := net.SplitHostPort(cfg.Addr); err != nil {
+		return nil, fmt.Errorf("invalid redis address %q: %w", cfg.Addr, err)
+	}
+	if cfg.Channel == "" {
+		cfg.Channel = "hub"
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 5 * time.Second
+	}
+	return &Redis{cfg: cfg}, nil
+}
+
+// Publish sends payload on the channel
+func (r *Redis) Publish(ctx context.Context, payload []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		if r.pub == nil {
+			c, err := r.dial(ctx)
+			if err != nil {
+				return err
+			}
+			r.pub = c
+		}
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			deadline = time.Now().Add(r.cfg.WriteTimeout)
+		}
+		r.pub.nc.SetDeadline(deadline)
+		_, err := r.pub.do("PUBLISH", []byte(r.cfg.Channel), payload)
+		if err == nil {
+			return nil
+		}
+		var rerr redisError
+		if errors.As(err, &rerr) {
+			return err
+		}
+		// a broken connection, e.g. after a Redis restart: redial once
+		r.pub.nc.Close()
+		r.pub = nil
+		if attempt > 0 || ctx.Err() != nil {
+			return fmt.Errorf("redis publish failed: %w", err)
+		}
+	}
+}
+
+// Subscribe calls fn with every payload on the channel until ctx is done or the connection fails
+func (r *Redis) Subscribe(ctx context.Context, fn func(payload []byte)) error {
+	c, err := r.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.nc.Close()
+	stop := context.AfterFunc(ctx, func() { c.nc.Close() })
+	defer stop()
+
+	if err := c.write("SUBSCRIBE", []byte(r.cfg.Channel)); err != nil {
+		return fmt.Errorf("redis subscribe failed: %w", err)
+	}
+	for {
+		reply, err := c.read()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("redis subscription failed: %w", err)
+		}
+		// ["message", channel, payload]; subscribe confirmations are ignored
+		if msg, ok := reply.([]interface{}); ok && len(msg) == 3 {
+			if kind, _ := msg[0].([]byte); string(kind) == "message" {
+				payload, _ := msg[2].([]byte)
+				fn(payload)
+			}
+		}
+	}
+}
+
+// Close closes the publishing connection; subscriptions end with their context
+func (r *Redis) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pub == nil {
+		return nil
+	}
+	err := r.pub.nc.Close()
+	r.pub = nil
+	return err
+}
+
+// dial connects and authenticates
+func (r *Redis) dial(ctx context.Context) (*redisConn, error) {
+	d := net.Dialer{Timeout: r.cfg.DialTimeout}
+	nc, err := d.DialContext(ctx, "tcp", r.cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	if r.cfg.TLSConfig != nil {
+		cfg := r.cfg.TLSConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName, _, _ = net.SplitHostPort(r.cfg.Addr)
+		}
+		nc = tls.Client(nc, cfg)
+	}
+	c := &redisConn{nc: nc, br: bufio.NewReader(nc)}
+	if r.cfg.Password != "" {
+		args := [][]byte{[]byte(r.cfg.Password)}
+		if r.cfg.Username != "" {
+			args = append([][]byte{[]byte(r.cfg.Username)}, args...)
+		}
+		nc.SetDeadline(time.Now().Add(r.cfg.DialTimeout))
+		if _, err := c.do("AUTH", args...); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("redis authentication failed: %w", err)
+		}
+		nc.SetDeadline(time.Time{})
+	}
+	return c, nil
+}
+
+// redisError is an error reply (-ERR ...)
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+type redisConn struct {
+	nc net.Conn
+	br *bufio.Reader
+}
+
+func (c *redisConn) do(cmd string, args ...[]byte) (interface{}, error) {
+	if err := c.write(cmd, args...); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// write sends a command as a RESP array of bulk strings
+func (c *redisConn) write(cmd string, args ...[]byte) error {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)+1), 10)
+	buf = append(buf, "\r\n"...)
+	for _, arg := range append([][]byte{[]byte(cmd)}, args...) {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, "\r\n"...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	_, err := c.nc.Write(buf)
+	return err
+}
+
+// read parses one RESP reply: simple strings and bulk strings as []byte, integers as int64,
+// arrays as []interface{}, error replies as redisError
+func (c *redisConn) read() (interface{}, error) {
+	line, err := c.br.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("malformed redis reply")
+	}
+	kind, body := line[0], string(line[1:len(line)-2])
+	switch kind {
+	case '+':
+		return []byte(body), nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err // $-1 is a nil reply
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.br, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply type %q", kind)
+}
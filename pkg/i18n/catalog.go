@@ -0,0 +1,323 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// PluralRule returns the CLDR plural category ("zero", "one", "two", "few", "many" or
+// "other") of an integer count
+type PluralRule func(n int64) string
+
+// message holds the forms of one translation; plain messages only have "other"
+type message map[string]string
+
+// Catalog holds the translations of every locale
+// Messages are looked up in the requested locale, then its base language ("pt" for
+// "pt-BR"), then the default locale.
+type Catalog struct {
+	defaultLocale string
+
+	mu       sync.RWMutex
+	messages map[string]map[string]message // locale -> key -> forms
+	plurals  map[string]PluralRule         // base language -> rule
+}
+
+// New creates an empty Catalog; defaultLocale is used when nothing better matches
+func New(defaultLocale string) *Catalog {
+	return &Catalog{
+		defaultLocale: Canonical(defaultLocale),
+		messages:      map[string]map[string]message{},
+		plurals:       map[string]PluralRule{},
+	}
+}
+
+// Load creates a Catalog from the .json and .toml files in dir of fsys (typically an embed.FS)
+// Files are named after their locale (locales/en.json, locales/pt-BR.toml) or grouped in
+// a directory per locale (locales/id/errors.json). Nested objects become dotted keys and
+// objects of plural forms (one, other, ...) become plural messages:
+//
+//	{
+//	  "greeting": "Hello, {name}!",
+//	  "cart": {"items": {"zero": "Your cart is empty", "one": "{count} item", "other": "{count} items"}},
+//	  "errors": {"not_found": "{resource} not found"},
+//	  "validation": {"required": "{field} is required"}
+//	}
+//
+// Example:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	catalog, err := i18n.Load(locales, "locales", "en")
+func Load(fsys fs.FS, dir, defaultLocale string) (*Catalog, error) {
+	c := New(defaultLocale)
+	if err := c.LoadFS(fsys, dir); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadFS adds the translation files in dir of fsys (see Load)
+func (c *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	return fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		ext := path.Ext(p)
+		if ext != ".json" && ext != ".toml" {
+			return nil
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, dir), "/")
+		locale, _, nested := strings.Cut(rel, "/")
+		if !nested {
+			locale = strings.TrimSuffix(rel, ext)
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		var tree map[string]interface{}
+		if ext == ".toml" {
+			err = toml.Unmarshal(data, &tree)
+		} else {
+			err = json.Unmarshal(data, &tree)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", p, err)
+		}
+		return c.Add(locale, tree)
+	})
+}
+
+// Add merges messages into locale; values are strings, plural forms or nested objects as in Load
+// Example:
+//
+//	catalog.Add("id", map[string]interface{}{
+//		"errors": map[string]interface{}{"not_found": "{resource} tidak ditemukan"},
+//		"resources": map[string]interface{}{"product": "produk"},
+//	})
+func (c *Catalog) Add(locale string, messages map[string]interface{}) error {
+	locale = Canonical(locale)
+	if locale == "" {
+		return fmt.Errorf("i18n: invalid locale")
+	}
+	flat := map[string]message{}
+	if err := flatten(flat, "", messages); err != nil {
+		return fmt.Errorf("i18n: %s: %w", locale, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]message{}
+	}
+	for key, m := range flat {
+		c.messages[locale][key] = m
+	}
+	return nil
+}
+
+func flatten(out map[string]message, prefix string, tree map[string]interface{}) error {
+	for key, v := range tree {
+		key = prefix + key
+		switch v := v.(type) {
+		case string:
+			out[key] = message{"other": v}
+		case map[string]interface{}:
+			if forms, ok := pluralForms(v); ok {
+				out[key] = forms
+				continue
+			}
+			if err := flatten(out, key+".", v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: expected a string or an object, got %T", key, v)
+		}
+	}
+	return nil
+}
+
+var categories = map[string]bool{"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true}
+
+// pluralForms reports whether v is an object of plural forms: string values under CLDR
+// category names, "other" included
+func pluralForms(v map[string]interface{}) (message, bool) {
+	if _, ok := v["other"].(string); !ok {
+		return nil, false
+	}
+	forms := message{}
+	for k, form := range v {
+		s, ok := form.(string)
+		if !ok || !categories[k] {
+			return nil, false
+		}
+		forms[k] = s
+	}
+	return forms, true
+}
+
+// SetPluralRule sets the plural rule of a language ("ar", "cy", ...), replacing the built-in one
+func (c *Catalog) SetPluralRule(lang string, rule PluralRule) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.plurals[base(Canonical(lang))] = rule
+}
+
+// DefaultLocale returns the locale used when nothing better matches
+func (c *Catalog) DefaultLocale() string {
+	return c.defaultLocale
+}
+
+// Locales returns the locales with messages, sorted
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	locales := make([]string, 0, len(c.messages))
+	for l := range c.messages {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Has reports whether locale has messages
+func (c *Catalog) Has(locale string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.messages[Canonical(locale)] != nil
+}
+
+// Lookup translates key into locale; ok is false when no locale in the fallback chain has it
+// args are key/value pairs filling the {name} placeholders; a "count" argument selects the
+// plural form.
+func (c *Catalog) Lookup(locale, key string, args ...interface{}) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, l := range c.chain(Canonical(locale)) {
+		m, ok := c.messages[l][key]
+		if !ok {
+			continue
+		}
+		vars := pairs(args)
+		form := m["other"]
+		if count, ok := vars["count"]; ok {
+			if f, ok := m[c.category(l, m, count)]; ok {
+				form = f
+			}
+		}
+		return interpolate(form, vars), true
+	}
+	return "", false
+}
+
+// Translate is Lookup returning key itself for missing messages, so they stand out
+func (c *Catalog) Translate(locale, key string, args ...interface{}) string {
+	if s, ok := c.Lookup(locale, key, args...); ok {
+		return s
+	}
+	return key
+}
+
+// chain returns the locales to try in order: locale, its base language, the default locale
+func (c *Catalog) chain(locale string) []string {
+	chain := make([]string, 0, 3)
+	for _, l := range []string{locale, base(locale), c.defaultLocale, base(c.defaultLocale)} {
+		if l != "" && !contains(chain, l) {
+			chain = append(chain, l)
+		}
+	}
+	return chain
+}
+
+// category returns the plural category of count in locale; "zero" wins for 0 when m
+// defines it, as a convenience over CLDR
+func (c *Catalog) category(locale string, m message, count interface{}) string {
+	n, ok := integer(count)
+	if !ok {
+		return "other"
+	}
+	if _, ok := m["zero"]; ok && n == 0 {
+		return "zero"
+	}
+	rule := c.plurals[base(locale)]
+	if rule == nil {
+		rule = builtinRule(base(locale))
+	}
+	return rule(n)
+}
+
+// pairs turns key/value arguments into a map; a single map argument is used as is
+func pairs(args []interface{}) map[string]interface{} {
+	if len(args) == 1 {
+		if m, ok := args[0].(map[string]interface{}); ok {
+			return m
+		}
+	}
+	vars := make(map[string]interface{}, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		vars[fmt.Sprint(args[i])] = args[i+1]
+	}
+	return vars
+}
+
+// interpolate replaces {name} placeholders; unknown ones are left as they are
+func interpolate(s string, vars map[string]interface{}) string {
+	if len(vars) == 0 || !strings.Contains(s, "{") {
+		return s
+	}
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(s[open:], '}')
+		if end < 0 {
+			break
+		}
+		name := s[open+1 : open+end]
+		b.WriteString(s[:open])
+		if v, ok := vars[name]; ok {
+			fmt.Fprint(&b, v)
+		} else {
+			b.WriteString(s[open : open+end+1])
+		}
+		s = s[open+end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// integer converts an integral count of any numeric type
+func integer(v interface{}) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		return int64(f), f == float64(int64(f))
+	}
+	return 0, false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
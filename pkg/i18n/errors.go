@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// LocalizeError returns a copy of e with its message and field messages in the locale of
+// ctx; response.WriteError calls it, so handlers return untranslated errors
+//
+//   - the message uses e.Key ("errors.not_found" for errs.NotFound, "errors.<code>" for
+//     errs.Define codes, see errs.Error.WithKey) with e.Args; a "resource" argument is
+//     itself translated with "resources.<name>" when the catalog has it
+//   - validator failures kept as the cause (errs.Validation(verrs.Fields()).Wrap(verrs))
+//     use "validation.<rule>" with {field} and {param}; field names are translated with
+//     "fields.<name>"
+//
+// Messages without a translation are kept, so partial catalogs are fine.
+func LocalizeError(ctx context.Context, e *errs.Error) *errs.Error {
+	if e == nil || Locale(ctx) == "" {
+		return e
+	}
+	out := *e
+	if e.Key != "" {
+		args := append([]any(nil), e.Args...)
+		for i := 0; i+1 < len(args); i += 2 {
+			if args[i] == "resource" {
+				if name, ok := args[i+1].(string); ok {
+					args[i+1] = translated(ctx, "resources."+name, name)
+				}
+			}
+		}
+		if msg, ok := Lookup(ctx, e.Key, args...); ok {
+			out.Message = msg
+		}
+	}
+
+	var verrs validator.ValidationErrors
+	if len(e.Fields) > 0 && errors.As(e.Err, &verrs) {
+		out.Fields = make(map[string][]string, len(e.Fields))
+		for _, fe := range verrs {
+			msg, ok := Lookup(ctx, "validation."+fe.Rule, "field", translated(ctx, "fields."+fe.Field, fe.Field), "param", fe.Param)
+			if !ok {
+				msg = fe.Message
+			}
+			out.Fields[fe.Field] = append(out.Fields[fe.Field], msg)
+		}
+	}
+	return &out
+}
+
+// translated returns the translation of key, or fallback
+func translated(ctx context.Context, key, fallback string) string {
+	if s, ok := Lookup(ctx, key); ok {
+		return s
+	}
+	return fallback
+}
@@ -0,0 +1,210 @@
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LanguageRange is one entry of an Accept-Language header
+type LanguageRange struct {
+	Tag     string  // canonical tag, e.g. "pt-BR", or "*"
+	Quality float64 // q-value in [0, 1]
+}
+
+// ParseAcceptLanguage parses an Accept-Language header, most preferred first
+// Malformed entries are skipped; ties keep header order.
+// Example:
+//
+//	i18n.ParseAcceptLanguage("id-ID, en;q=0.8, *;q=0.1")
+//	// [{id-ID 1} {en 0.8} {* 0.1}]
+func ParseAcceptLanguage(header string) []LanguageRange {
+	var ranges []LanguageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag != "*" {
+			tag = Canonical(tag)
+		}
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f < 0 || f > 1 {
+				continue
+			}
+			q = f
+		}
+		ranges = append(ranges, LanguageRange{Tag: tag, Quality: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].Quality > ranges[j].Quality })
+	return ranges
+}
+
+// Canonical normalizes a language tag: "pt_br" and "PT-br" become "pt-BR", "zh-hant-tw"
+// becomes "zh-Hant-TW"; "" is returned for malformed tags
+func Canonical(tag string) string {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	for i, p := range parts {
+		if p == "" || len(p) > 8 || strings.Trim(p, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789") != "" {
+			return ""
+		}
+		switch {
+		case i == 0:
+			if len(p) < 2 || len(p) > 3 {
+				return ""
+			}
+			parts[i] = strings.ToLower(p)
+		case len(p) == 4: // script
+			parts[i] = strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		case len(p) == 2 || len(p) == 3: // region
+			parts[i] = strings.ToUpper(p)
+		default:
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// base returns the language of a tag: "pt" for "pt-BR"
+func base(tag string) string {
+	lang, _, _ := strings.Cut(tag, "-")
+	return lang
+}
+
+// Match returns the supported locale the Accept-Language header prefers
+// A range matches a locale exactly, by base language ("pt" accepts "pt-BR" and "pt-BR"
+// accepts "pt"), or through "*". The default locale is returned when nothing matches.
+func (c *Catalog) Match(header string) string {
+	locales := c.Locales()
+	for _, r := range ParseAcceptLanguage(header) {
+		if r.Quality == 0 {
+			continue
+		}
+		if l := matchLocale(r.Tag, locales); l != "" {
+			return l
+		}
+		if r.Tag == "*" {
+			break
+		}
+	}
+	return c.defaultLocale
+}
+
+// matchLocale finds tag in locales: exactly, then its base language, then any regional
+// variant of it
+func matchLocale(tag string, locales []string) string {
+	if tag == "" || tag == "*" {
+		return ""
+	}
+	lang := base(tag)
+	for _, want := range []string{tag, lang} {
+		for _, l := range locales {
+			if l == want {
+				return l
+			}
+		}
+	}
+	for _, l := range locales {
+		if base(l) == lang {
+			return l
+		}
+	}
+	return ""
+}
+
+// Config configures the locale detection of Middleware
+type Config struct {
+	QueryParam string // query parameter overriding the locale (default "lang")
+	Cookie     string // cookie remembering the locale (default "lang")
+}
+
+type ctxKey struct{}
+
+type localizer struct {
+	catalog *Catalog
+	locale  string
+}
+
+// Middleware detects the request locale and stores it with the catalog for T, Locale and
+// localized error responses (response.WriteError)
+// The locale comes from ?lang=, then the lang cookie, then Accept-Language, then the
+// catalog's default; values of unsupported locales are skipped. Content-Language is set
+// on the response.
+// Example:
+//
+//	catalog, err := i18n.Load(locales, "locales", "en")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	handler := i18n.Middleware(catalog, i18n.Config{})(mux)
+func Middleware(c *Catalog, cfg Config) func(http.Handler) http.Handler {
+	if cfg.QueryParam == "" {
+		cfg.QueryParam = "lang"
+	}
+	if cfg.Cookie == "" {
+		cfg.Cookie = "lang"
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := ""
+			if v := r.URL.Query().Get(cfg.QueryParam); v != "" {
+				locale = matchLocale(Canonical(v), c.Locales())
+			}
+			if locale == "" {
+				if cookie, err := r.Cookie(cfg.Cookie); err == nil {
+					locale = matchLocale(Canonical(cookie.Value), c.Locales())
+				}
+			}
+			if locale == "" {
+				locale = c.Match(r.Header.Get("Accept-Language"))
+			}
+			w.Header().Set("Content-Language", locale)
+			w.Header().Add("Vary", "Accept-Language")
+			next.ServeHTTP(w, r.WithContext(WithLocale(r.Context(), c, locale)))
+		})
+	}
+}
+
+// WithLocale returns ctx translating with c into locale, for code outside HTTP requests
+// Example:
+//
+//	ctx = i18n.WithLocale(ctx, catalog, user.Locale)
+//	subject := i18n.T(ctx, "emails.order_shipped.subject", "order", order.Number)
+func WithLocale(ctx context.Context, c *Catalog, locale string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, localizer{catalog: c, locale: Canonical(locale)})
+}
+
+// Locale returns the locale of ctx, "" without Middleware or WithLocale
+func Locale(ctx context.Context) string {
+	l, _ := ctx.Value(ctxKey{}).(localizer)
+	return l.locale
+}
+
+// T translates key into the locale of ctx
+// args are key/value pairs for the {name} placeholders; "count" also selects the plural
+// form. Missing keys, and contexts without a catalog, return key.
+// Example:
+//
+//	i18n.T(ctx, "greeting", "name", user.Name)   // "Halo, Budi!"
+//	i18n.T(ctx, "cart.items", "count", len(items)) // "3 items" / "1 item"
+func T(ctx context.Context, key string, args ...interface{}) string {
+	l, ok := ctx.Value(ctxKey{}).(localizer)
+	if !ok || l.catalog == nil {
+		return key
+	}
+	return l.catalog.Translate(l.locale, key, args...)
+}
+
+// Lookup is T reporting whether a translation exists instead of returning the key
+func Lookup(ctx context.Context, key string, args ...interface{}) (string, bool) {
+	l, ok := ctx.Value(ctxKey{}).(localizer)
+	if !ok || l.catalog == nil {
+		return "", false
+	}
+	return l.catalog.Lookup(l.locale, key, args...)
+}
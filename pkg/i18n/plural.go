@@ -0,0 +1,100 @@
+package i18n
+
+// builtinRule returns the CLDR cardinal rule of a base language for integer counts
+// Languages without a rule here use the English one/other rule; SetPluralRule adds others.
+func builtinRule(lang string) PluralRule {
+	switch lang {
+	case "id", "ms", "ja", "zh", "ko", "th", "vi", "lo", "km", "my":
+		return pluralOther
+	case "fr", "hi", "bn", "fa":
+		return pluralZeroOne
+	case "ru", "uk", "be", "sr", "hr", "bs":
+		return pluralEastSlavic
+	case "pl":
+		return pluralPolish
+	case "cs", "sk":
+		return pluralCzech
+	case "ar":
+		return pluralArabic
+	}
+	return pluralOne
+}
+
+// pluralOther: no plural forms (Indonesian, Japanese, Chinese, ...)
+func pluralOther(int64) string { return "other" }
+
+// pluralOne: 1 is "one" (English, German, Spanish, ...)
+func pluralOne(n int64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralZeroOne: 0 and 1 are "one" (French, Hindi, ...)
+func pluralZeroOne(n int64) string {
+	if n == 0 || n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralEastSlavic: 1, 21, 31 "one"; 2-4, 22-24 "few"; the rest "many" (Russian, Ukrainian, ...)
+func pluralEastSlavic(n int64) string {
+	n = abs(n)
+	switch mod10, mod100 := n%10, n%100; {
+	case mod10 == 1 && mod100 != 11:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	}
+	return "many"
+}
+
+// pluralPolish: 1 "one"; 2-4, 22-24 "few"; the rest "many"
+func pluralPolish(n int64) string {
+	n = abs(n)
+	if n == 1 {
+		return "one"
+	}
+	if mod10, mod100 := n%10, n%100; mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14) {
+		return "few"
+	}
+	return "many"
+}
+
+// pluralCzech: 1 "one"; 2-4 "few"; the rest "other" (Czech, Slovak)
+func pluralCzech(n int64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case n >= 2 && n <= 4:
+		return "few"
+	}
+	return "other"
+}
+
+// pluralArabic: 0 "zero", 1 "one", 2 "two", 3-10 "few", 11-99 "many" (mod 100), the rest "other"
+func pluralArabic(n int64) string {
+	n = abs(n)
+	switch mod100 := n % 100; {
+	case n == 0:
+		return "zero"
+	case n == 1:
+		return "one"
+	case n == 2:
+		return "two"
+	case mod100 >= 3 && mod100 <= 10:
+		return "few"
+	case mod100 >= 11:
+		return "many"
+	}
+	return "other"
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,191 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/storage"
+)
+
+var (
+	ErrTooLarge          = errors.New("image file is too large")
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrInvalidDimensions = errors.New("image dimensions out of allowed range")
+)
+
+// DefaultMaxPixels caps width x height when Options.MaxPixels is 0
+// A small file can declare huge dimensions (a decompression bomb); 40 MP already decodes
+// to 160 MB of RGBA.
+const DefaultMaxPixels = 40_000_000
+
+// Options controls validation of uploaded images
+// Zero values disable the corresponding check (except Formats, which defaults to jpeg/png/gif,
+// and MaxPixels, which defaults to DefaultMaxPixels).
+type Options struct {
+	MaxBytes  int64
+	MinWidth  int
+	MinHeight int
+	MaxWidth  int
+	MaxHeight int
+	MaxPixels int64    // width x height, default DefaultMaxPixels, negative for no limit
+	Formats   []string // "jpeg", "png", "gif"
+	Quality   int      // JPEG quality for re-encoding, default 85
+}
+
+// Variant describes a resized copy to generate
+// The image is scaled to fit within Width x Height keeping aspect ratio;
+// with Crop it fills the box exactly and the overflow is center-cropped.
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+// Stored describes an image written to storage by Process
+type Stored struct {
+	Name   string `json:"name"`
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// Validate reads r, checks size/format/dimensions and returns the decoded image
+// EXIF orientation is applied so the returned image is upright. Images over MaxPixels
+// are rejected from their header, before any pixel is decoded.
+// Example:
+//
+//	img, format, err := images.Validate(file, images.Options{MaxBytes: 5 << 20, MaxWidth: 6000, MaxHeight: 6000})
+func Validate(r io.Reader, opts Options) (image.Image, string, error) {
+	var data []byte
+	var err error
+	if opts.MaxBytes > 0 {
+		data, err = io.ReadAll(io.LimitReader(r, opts.MaxBytes+1))
+		if err == nil && int64(len(data)) > opts.MaxBytes {
+			return nil, "", ErrTooLarge
+		}
+	} else {
+		data, err = io.ReadAll(r)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// Check header first so huge images are rejected before full decoding
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if !allowedFormat(format, opts.Formats) {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if (opts.MinWidth > 0 && cfg.Width < opts.MinWidth) ||
+		(opts.MinHeight > 0 && cfg.Height < opts.MinHeight) ||
+		(opts.MaxWidth > 0 && cfg.Width > opts.MaxWidth) ||
+		(opts.MaxHeight > 0 && cfg.Height > opts.MaxHeight) {
+		return nil, "", fmt.Errorf("%w: %dx%d", ErrInvalidDimensions, cfg.Width, cfg.Height)
+	}
+	maxPixels := opts.MaxPixels
+	if maxPixels == 0 {
+		maxPixels = DefaultMaxPixels
+	}
+	if maxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return nil, "", fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrInvalidDimensions, cfg.Width, cfg.Height, maxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+	if format == "jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
+
+// Encode writes img in the given format; metadata (EXIF, comments) is never written
+// GIF input is encoded as PNG since animation is not preserved.
+func Encode(w io.Writer, img image.Image, format string, quality int) (string, error) {
+	switch format {
+	case "jpeg":
+		if quality <= 0 || quality > 100 {
+			quality = 85
+		}
+		return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case "png", "gif":
+		return "image/png", png.Encode(w, img)
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Process validates r, stores an EXIF-stripped original and all variants under keyPrefix
+// Keys look like "<keyPrefix>/original.jpg", "<keyPrefix>/thumb.jpg".
+// Example:
+//
+//	stored, err := images.Process(ctx, store, "avatars/42", file, images.Options{MaxBytes: 5 << 20},
+//	    images.Variant{Name: "thumb", Width: 128, Height: 128, Crop: true},
+//	    images.Variant{Name: "medium", Width: 512, Height: 512},
+//	)
+func Process(ctx context.Context, store storage.Storage, keyPrefix string, r io.Reader, opts Options, variants ...Variant) ([]Stored, error) {
+	img, format, err := Validate(r, opts)
+	if err != nil {
+		return nil, err
+	}
+	keyPrefix = strings.TrimRight(keyPrefix, "/")
+	ext := ".png"
+	if format == "jpeg" {
+		ext = ".jpg"
+	}
+
+	all := append([]Variant{{Name: "original"}}, variants...)
+	stored := make([]Stored, 0, len(all))
+	for _, v := range all {
+		out := img
+		if v.Width > 0 || v.Height > 0 {
+			if v.Crop {
+				out = Fill(img, v.Width, v.Height)
+			} else {
+				out = Fit(img, v.Width, v.Height)
+			}
+		}
+
+		var buf bytes.Buffer
+		contentType, err := Encode(&buf, out, format, opts.Quality)
+		if err != nil {
+			return stored, fmt.Errorf("failed to encode %s: %w", v.Name, err)
+		}
+		key := keyPrefix + "/" + v.Name + ext
+		if err := store.Put(ctx, key, &buf, contentType); err != nil {
+			return stored, fmt.Errorf("failed to store %s: %w", v.Name, err)
+		}
+		b := out.Bounds()
+		stored = append(stored, Stored{Name: v.Name, Key: key, URL: store.URL(key), Width: b.Dx(), Height: b.Dy()})
+	}
+	return stored, nil
+}
+
+func allowedFormat(format string, formats []string) bool {
+	if len(formats) == 0 {
+		formats = []string{"jpeg", "png", "gif"}
+	}
+	for _, f := range formats {
+		f = strings.ToLower(f)
+		if f == "jpg" {
+			f = "jpeg"
+		}
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package images
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Fit scales img down to fit within maxW x maxH, keeping aspect ratio
+// A zero bound means "unconstrained" on that axis.
+// Example:
+//
+//	thumb := images.Fit(img, 256, 256)
+func Fit(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 || (maxW <= 0 && maxH <= 0) {
+		return img
+	}
+	nw, nh := w, h
+	if maxW > 0 && nw > maxW {
+		nh = nh * maxW / nw
+		nw = maxW
+	}
+	if maxH > 0 && nh > maxH {
+		nw = nw * maxH / nh
+		nh = maxH
+	}
+	if nw == w && nh == h {
+		return img
+	}
+	return Resize(img, max(nw, 1), max(nh, 1))
+}
+
+// Fill scales img to cover w x h and center-crops the overflow
+// Use this for square avatars and fixed-size thumbnails.
+func Fill(img image.Image, w, h int) image.Image {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 || sw == 0 || sh == 0 {
+		return Fit(img, w, h)
+	}
+	// Crop source to target aspect ratio first
+	cw, ch := sw, sw*h/w
+	if ch > sh {
+		cw, ch = sh*w/h, sh
+	}
+	x0 := b.Min.X + (sw-cw)/2
+	y0 := b.Min.Y + (sh-ch)/2
+	cropped := toRGBA(img).SubImage(image.Rect(x0, y0, x0+cw, y0+ch))
+	return Resize(cropped, w, h)
+}
+
+// Resize scales img to exactly w x h using area averaging
+// Area averaging gives smooth thumbnails when downscaling without extra dependencies.
+func Resize(img image.Image, w, h int) *image.RGBA {
+	src := toRGBA(img)
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if sw == 0 || sh == 0 {
+		return dst
+	}
+
+	for y := 0; y < h; y++ {
+		sy0 := y * sh / h
+		sy1 := max((y+1)*sh/h, sy0+1)
+		for x := 0; x < w; x++ {
+			sx0 := x * sw / w
+			sx1 := max((x+1)*sw/w, sx0+1)
+
+			// uint64: a uint32 sum overflows past ~16.8M source pixels per target pixel
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				off := src.PixOffset(sb.Min.X+sx0, sb.Min.Y+sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint64(src.Pix[off])
+					g += uint64(src.Pix[off+1])
+					bl += uint64(src.Pix[off+2])
+					a += uint64(src.Pix[off+3])
+					off += 4
+					n++
+				}
+			}
+			d := dst.PixOffset(x, y)
+			dst.Pix[d] = uint8(r / n)
+			dst.Pix[d+1] = uint8(g / n)
+			dst.Pix[d+2] = uint8(bl / n)
+			dst.Pix[d+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// toRGBA returns img as *image.RGBA, converting only when necessary
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	b := img.Bounds()
+	rgba := image.NewRGBA(b)
+	draw.Draw(rgba, b, img, b.Min, draw.Src)
+	return rgba
+}
@@ -0,0 +1,428 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// MediaType is the JSON:API media type
+const MediaType = "application/vnd.api+json"
+
+var (
+	ErrNotResource = errors.New("jsonapi: value is not a struct or slice of structs")
+	ErrMissingID   = errors.New("jsonapi: resource has no id field")
+)
+
+// Document is a top-level JSON:API document
+type Document struct {
+	Data     interface{}       `json:"data,omitempty"`
+	Included []*Resource       `json:"included,omitempty"`
+	Errors   []*Error          `json:"errors,omitempty"`
+	Meta     interface{}       `json:"meta,omitempty"`
+	Links    response.Links    `json:"links,omitempty"`
+	JSONAPI  map[string]string `json:"jsonapi,omitempty"`
+}
+
+// Resource is a JSON:API resource object
+type Resource struct {
+	Type          string                   `json:"type"`
+	ID            string                   `json:"id"`
+	Attributes    map[string]interface{}   `json:"attributes,omitempty"`
+	Relationships map[string]*Relationship `json:"relationships,omitempty"`
+	Links         response.Links           `json:"links,omitempty"`
+}
+
+// Identifier is a resource identifier object ({type, id})
+type Identifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Relationship holds resource linkage: *Identifier, nil, or []Identifier
+type Relationship struct {
+	Data interface{} `json:"data"`
+}
+
+// Error is a JSON:API error object
+type Error struct {
+	Status string       `json:"status,omitempty"`
+	Code   string       `json:"code,omitempty"`
+	Title  string       `json:"title,omitempty"`
+	Detail string       `json:"detail,omitempty"`
+	Source *ErrorSource `json:"source,omitempty"`
+}
+
+// ErrorSource points at the part of the request that caused an error
+type ErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// Marshal converts a struct (or pointer) or a slice of them into a document
+// The id field is tagged `jsonapi:"primary,<type>"`; without a tag a field named ID
+// is used and the type is the snake_case struct name. Fields tagged
+// `jsonapi:"relation,<name>"` become relationships and their values are added to
+// "included"; every other exported field is an attribute named by its json tag.
+// Example:
+//
+//	type Book struct {
+//		ID     uint    `json:"id" jsonapi:"primary,books"`
+//		Title  string  `json:"title"`
+//		Author *Author `json:"author,omitempty" jsonapi:"relation,author"`
+//	}
+//	doc, err := jsonapi.Marshal(books, meta)
+func Marshal(v interface{}, meta interface{}) (*Document, error) {
+	m := &marshaler{seen: map[Identifier]bool{}}
+	doc := &Document{Meta: meta}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return doc, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		res, err := m.resource(rv)
+		if err != nil {
+			return nil, err
+		}
+		doc.Data = res
+	case reflect.Slice, reflect.Array:
+		data := make([]*Resource, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item := reflect.Indirect(rv.Index(i))
+			if item.Kind() == reflect.Interface {
+				item = reflect.Indirect(item.Elem())
+			}
+			if item.Kind() != reflect.Struct {
+				return nil, ErrNotResource
+			}
+			res, err := m.resource(item)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, res)
+		}
+		doc.Data = data
+	default:
+		return nil, ErrNotResource
+	}
+
+	if err := m.includeAll(); err != nil {
+		return nil, err
+	}
+	doc.Included = m.included
+	return doc, nil
+}
+
+// Write sends v as a JSON:API document with the given status
+// Example:
+//
+//	jsonapi.Write(w, http.StatusOK, books, map[string]any{"total": total})
+//	// Echo: return jsonapi.Write(c.Response(), http.StatusOK, book, nil)
+func Write(w http.ResponseWriter, status int, v interface{}, meta interface{}) error {
+	doc, err := Marshal(v, meta)
+	if err != nil {
+		WriteErrors(w, http.StatusInternalServerError, &Error{Title: "internal server error"})
+		return fmt.Errorf("failed to marshal jsonapi document: %w", err)
+	}
+	return writeDocument(w, status, doc)
+}
+
+// WriteErrors sends an errors document; statuses left empty are set from status
+// Example:
+//
+//	jsonapi.WriteErrors(w, http.StatusNotFound, &jsonapi.Error{Title: "book not found"})
+func WriteErrors(w http.ResponseWriter, status int, errs ...*Error) error {
+	return writeDocument(w, status, &Document{Errors: withStatus(status, errs)})
+}
+
+// ValidationErrors converts validator field messages into errors pointing at
+// /data/attributes/<field>, sorted by field name
+// Example:
+//
+//	jsonapi.WriteErrors(w, http.StatusUnprocessableEntity, jsonapi.ValidationErrors(verrs.Fields())...)
+func ValidationErrors(fields map[string][]string) []*Error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []*Error
+	for _, name := range names {
+		for _, msg := range fields[name] {
+			errs = append(errs, &Error{
+				Status: strconv.Itoa(http.StatusUnprocessableEntity),
+				Title:  "validation failed",
+				Detail: msg,
+				Source: &ErrorSource{Pointer: "/data/attributes/" + name},
+			})
+		}
+	}
+	return errs
+}
+
+// Register makes response.Negotiate render JSON:API for clients that accept MediaType
+// The standard envelope is translated: Data becomes the primary data, Error and
+// Errors become error objects, and LinkedResponse meta/links are kept.
+// Example:
+//
+//	jsonapi.Register()
+//	handler := middleware.Negotiate()(mux) // Accept: application/vnd.api+json
+func Register() {
+	response.RegisterRenderer(MediaType, Render)
+}
+
+// Use forces JSON:API rendering for every response.Negotiate call under a route group
+// Mount it inside middleware.Negotiate, which would otherwise pick the type from Accept.
+// Example:
+//
+//	mux.Handle("/v2/", jsonapi.Use(v2))
+//	// Echo: v2 := e.Group("/v2", echo.WrapMiddleware(jsonapi.Use))
+func Use(next http.Handler) http.Handler {
+	Register()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(response.WithMediaType(r.Context(), MediaType)))
+	})
+}
+
+// Render writes v as JSON:API; it is the response.Renderer installed by Register
+func Render(w io.Writer, v interface{}) error {
+	var doc *Document
+	var err error
+	switch x := v.(type) {
+	case *Document:
+		doc = x
+	case Document:
+		doc = &x
+	case response.Response:
+		doc, err = fromEnvelope(x, nil, nil)
+	case response.LinkedResponse:
+		doc, err = fromEnvelope(x.Response, x.Meta, x.Links)
+	default:
+		doc, err = Marshal(v, nil)
+	}
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+func fromEnvelope(r response.Response, meta interface{}, links response.Links) (*Document, error) {
+	if !r.Success {
+		errs := ValidationErrors(r.Errors)
+		if len(errs) == 0 {
+			errs = []*Error{{Title: r.Error}}
+		}
+		return &Document{Errors: errs, Meta: meta}, nil
+	}
+	doc, err := Marshal(r.Data, meta)
+	if err != nil {
+		return nil, err
+	}
+	doc.Links = links
+	return doc, nil
+}
+
+func writeDocument(w http.ResponseWriter, status int, doc *Document) error {
+	w.Header().Set("Content-Type", MediaType)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(doc)
+}
+
+func withStatus(status int, errs []*Error) []*Error {
+	for _, e := range errs {
+		if e.Status == "" {
+			e.Status = strconv.Itoa(status)
+		}
+	}
+	return errs
+}
+
+// marshaler collects related resources for "included", once per type/id
+type marshaler struct {
+	seen     map[Identifier]bool
+	pending  []reflect.Value
+	included []*Resource
+}
+
+func (m *marshaler) resource(v reflect.Value) (*Resource, error) {
+	id, ok := identity(v)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingID, v.Type())
+	}
+	m.seen[id] = true
+	res := &Resource{Type: id.Type, ID: id.ID, Attributes: map[string]interface{}{}}
+	if err := m.fields(res, v); err != nil {
+		return nil, err
+	}
+	if len(res.Attributes) == 0 {
+		res.Attributes = nil
+	}
+	return res, nil
+}
+
+func (m *marshaler) fields(res *Resource, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+		jsonName, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		kind, arg, _ := strings.Cut(f.Tag.Get("jsonapi"), ",")
+
+		if f.Anonymous && jsonName == "" && kind == "" {
+			if ev := reflect.Indirect(fv); ev.Kind() == reflect.Struct {
+				if err := m.fields(res, ev); err != nil {
+					return err
+				}
+				continue // embedded fields are flattened, like encoding/json
+			}
+		}
+		if !f.IsExported() || kind == "-" || kind == "primary" || (kind == "" && (f.Name == "ID" || jsonName == "-")) {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+
+		if kind == "relation" {
+			if arg == "" {
+				arg = jsonName
+			}
+			rel, err := m.relationship(fv)
+			if err != nil {
+				return fmt.Errorf("relationship %q: %w", arg, err)
+			}
+			if res.Relationships == nil {
+				res.Relationships = map[string]*Relationship{}
+			}
+			res.Relationships[arg] = rel
+			continue
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		res.Attributes[jsonName] = fv.Interface()
+	}
+	return nil
+}
+
+func (m *marshaler) relationship(v reflect.Value) (*Relationship, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return &Relationship{}, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		id, err := m.link(v)
+		if err != nil {
+			return nil, err
+		}
+		return &Relationship{Data: &id}, nil
+	case reflect.Slice, reflect.Array:
+		ids := make([]Identifier, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item := reflect.Indirect(v.Index(i))
+			if item.Kind() != reflect.Struct {
+				return nil, ErrNotResource
+			}
+			id, err := m.link(item)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return &Relationship{Data: ids}, nil
+	}
+	return nil, ErrNotResource
+}
+
+// link returns the identifier of a related struct and queues it for "included"
+func (m *marshaler) link(v reflect.Value) (Identifier, error) {
+	id, ok := identity(v)
+	if !ok {
+		return id, fmt.Errorf("%w: %s", ErrMissingID, v.Type())
+	}
+	m.pending = append(m.pending, v)
+	return id, nil
+}
+
+// includeAll marshals queued related resources, including their own relations
+func (m *marshaler) includeAll() error {
+	for len(m.pending) > 0 {
+		v := m.pending[0]
+		m.pending = m.pending[1:]
+		if id, _ := identity(v); m.seen[id] {
+			continue
+		}
+		res, err := m.resource(v)
+		if err != nil {
+			return err
+		}
+		m.included = append(m.included, res)
+	}
+	return nil
+}
+
+// identity finds the type and id of a struct from its primary tag or ID field
+func identity(v reflect.Value) (Identifier, bool) {
+	t := v.Type()
+	var fallback *Identifier
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		kind, arg, _ := strings.Cut(f.Tag.Get("jsonapi"), ",")
+		if f.Anonymous && kind == "" {
+			if ev := reflect.Indirect(v.Field(i)); ev.Kind() == reflect.Struct {
+				if id, ok := identity(ev); ok {
+					if id.Type == snakeCase(ev.Type().Name()) {
+						id.Type = snakeCase(t.Name()) // gorm.Model-style ID: type of the outer struct
+					}
+					fallback = &id
+				}
+				continue
+			}
+		}
+		switch {
+		case kind == "primary":
+			if arg == "" {
+				arg = snakeCase(t.Name())
+			}
+			return Identifier{Type: arg, ID: fmt.Sprint(v.Field(i).Interface())}, true
+		case kind == "" && f.Name == "ID" && fallback == nil:
+			fallback = &Identifier{Type: snakeCase(t.Name()), ID: fmt.Sprint(v.Field(i).Interface())}
+		}
+	}
+	if fallback == nil {
+		return Identifier{}, false
+	}
+	return *fallback, true
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+type bodyKey struct{}
+
+type capturedBody struct {
+	data      []byte
+	truncated bool
+}
+
+// CaptureBody keeps the first maxBytes of each request body (default 64 KiB) in the
+// request context for audit logs, error reports and debugging (see Body)
+// The body is not consumed: handlers still read all of it with ParseJSON or io.ReadAll,
+// and limits such as http.MaxBytesReader keep working. Register it before middleware
+// that needs the payload and keep maxBytes small, since the prefix lives for the request.
+// Example:
+//
+//	handler := logging.Middleware(logger)(logging.CaptureBody(16 << 10)(mux))
+//
+//	// in an error path
+//	body, truncated := logging.Body(r.Context())
+func CaptureBody(maxBytes int64) func(http.Handler) http.Handler {
+	if maxBytes <= 0 {
+		maxBytes = 64 << 10
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// read one byte past the cap to know whether the body was cut
+			buf, _ := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+
+			c := capturedBody{data: buf, truncated: int64(len(buf)) > maxBytes}
+			if c.truncated {
+				c.data = buf[:maxBytes:maxBytes]
+			}
+			next.ServeHTTP(w, r.WithContext(WithBody(r.Context(), c.data, c.truncated)))
+		})
+	}
+}
+
+// WithBody returns ctx carrying a captured request body
+// Middleware that already reads the whole body (e.g. signature verification) can use it
+// to share the payload instead of requiring CaptureBody.
+func WithBody(ctx context.Context, body []byte, truncated bool) context.Context {
+	return context.WithValue(ctx, bodyKey{}, capturedBody{data: body, truncated: truncated})
+}
+
+// Body returns the request body captured by CaptureBody and whether it was cut at the cap
+// It returns nil for requests without a body or when CaptureBody did not run.
+// The slice is shared by everyone reading the context and must not be modified.
+func Body(ctx context.Context) (body []byte, truncated bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	c, _ := ctx.Value(bodyKey{}).(capturedBody)
+	return c.data, c.truncated
+}
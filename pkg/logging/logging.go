@@ -0,0 +1,168 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ctxKey struct{}
+
+// holder lets later middleware (e.g. auth) enrich the request logger in place
+type holder struct {
+	mu     sync.RWMutex
+	logger *slog.Logger
+}
+
+// New creates a slog logger writing to w at the given level
+// format is "json" or "text" (default).
+// Example:
+//
+//	logger := logging.New(os.Stdout, "info", "json")
+//	slog.SetDefault(logger)
+func New(w io.Writer, level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: ParseLevel(level)}
+	if strings.EqualFold(format, "json") {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// Options configures NewWithOptions
+type Options struct {
+	Level  string    // debug | info | warn | error
+	Format string    // json | text
+	Output io.Writer // defaults to os.Stdout (ignored when File is set)
+
+	// File enables built-in rotation; use Output for a custom rotating writer instead
+	File        string
+	MaxSize     int64         // rotate after this many bytes (0 = no size rotation)
+	RotateEvery time.Duration // rotate after this duration (0 = no time rotation)
+	MaxBackups  int           // rotated files to keep (0 = keep all)
+
+	// Sampling drops repetitive low-level logs when set
+	Sampling *SamplingConfig
+}
+
+// NewWithOptions creates a logger with optional file rotation and sampling
+// Example:
+//
+//	logger, err := logging.NewWithOptions(logging.Options{
+//	    Level: "debug", Format: "json",
+//	    File: "logs/app.log", MaxSize: 100 << 20, MaxBackups: 7,
+//	    Sampling: &logging.SamplingConfig{MaxLevel: slog.LevelDebug, First: 10, Thereafter: 100},
+//	})
+func NewWithOptions(opts Options) (*slog.Logger, error) {
+	w := opts.Output
+	if opts.File != "" {
+		rf, err := NewRotatingFile(opts.File, opts.MaxSize, opts.RotateEvery, opts.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		w = rf
+	}
+	if w == nil {
+		w = os.Stdout
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: ParseLevel(opts.Level)}
+	var h slog.Handler
+	if strings.EqualFold(opts.Format, "json") {
+		h = slog.NewJSONHandler(w, handlerOpts)
+	} else {
+		h = slog.NewTextHandler(w, handlerOpts)
+	}
+	if opts.Sampling != nil {
+		h = NewSamplingHandler(h, *opts.Sampling)
+	}
+	return slog.New(h), nil
+}
+
+// FromEnv creates a logger from environment variables:
+// LOG_LEVEL, LOG_FORMAT, LOG_FILE, LOG_MAX_SIZE_MB, LOG_ROTATE_EVERY (e.g. "24h"), LOG_MAX_BACKUPS,
+// LOG_SAMPLE_FIRST and LOG_SAMPLE_THEREAFTER (sampling of debug logs per second).
+// Falls back to stdout if the log file cannot be opened.
+func FromEnv() *slog.Logger {
+	opts := Options{
+		Level:      os.Getenv("LOG_LEVEL"),
+		Format:     os.Getenv("LOG_FORMAT"),
+		File:       os.Getenv("LOG_FILE"),
+		MaxSize:    int64(envInt("LOG_MAX_SIZE_MB")) << 20,
+		MaxBackups: envInt("LOG_MAX_BACKUPS"),
+	}
+	if d, err := time.ParseDuration(os.Getenv("LOG_ROTATE_EVERY")); err == nil {
+		opts.RotateEvery = d
+	}
+	if first, thereafter := envInt("LOG_SAMPLE_FIRST"), envInt("LOG_SAMPLE_THEREAFTER"); first > 0 || thereafter > 0 {
+		opts.Sampling = &SamplingConfig{MaxLevel: slog.LevelDebug, First: first, Thereafter: thereafter, Tick: time.Second}
+	}
+
+	logger, err := NewWithOptions(opts)
+	if err != nil {
+		log.Printf("logging: %v, falling back to stdout", err)
+		opts.File = ""
+		logger, _ = NewWithOptions(opts)
+	}
+	return logger
+}
+
+func envInt(key string) int {
+	n, _ := strconv.Atoi(os.Getenv(key))
+	return n
+}
+
+// ParseLevel converts a level name to slog.Level (default info)
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// NewContext returns ctx carrying logger
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &holder{logger: logger})
+}
+
+// FromContext returns the request-scoped logger, or slog.Default() if none is attached
+// Example:
+//
+//	logging.FromContext(r.Context()).Info("order created", "order_id", order.ID)
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if h, ok := ctx.Value(ctxKey{}).(*holder); ok {
+			h.mu.RLock()
+			defer h.mu.RUnlock()
+			return h.logger
+		}
+	}
+	return slog.Default()
+}
+
+// With adds attributes to the logger in ctx
+// If ctx already carries a request logger it is updated in place, so middleware that runs
+// after the logging middleware (auth, tenant resolution) can add fields like user_id.
+// Example:
+//
+//	ctx = logging.With(ctx, "user_id", claims.UserID)
+func With(ctx context.Context, args ...any) context.Context {
+	if h, ok := ctx.Value(ctxKey{}).(*holder); ok {
+		h.mu.Lock()
+		h.logger = h.logger.With(args...)
+		h.mu.Unlock()
+		return ctx
+	}
+	return NewContext(ctx, slog.Default().With(args...))
+}
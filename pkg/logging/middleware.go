@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/yoockh/go-api-utils/pkg/realip"
+)
+
+// Middleware attaches a request-scoped logger (request_id, method, route, client_ip) to the request context
+// The request ID is taken from X-Request-ID if present, otherwise generated, and echoed in the response.
+// client_ip is the address resolved by realip.Middleware when it runs first. route is resolved
+// when a line is logged: the pattern ServeMux matched (e.g. "GET /products/{id}"), the one
+// given to SetRoute, or the raw path for unmatched requests.
+// Example:
+//
+//	handler := logging.Middleware(logger)(mux)
+//
+//	// inside handlers
+//	logging.FromContext(r.Context()).Info("product created", "id", p.ID)
+func Middleware(base *slog.Logger) func(http.Handler) http.Handler {
+	if base == nil {
+		base = slog.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !ValidRequestID(id) {
+				id = NewRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			route := &routeValue{}
+			logger := slog.New(routeHandler{base.Handler(), route}).With(
+				slog.String("request_id", id),
+				slog.String("method", r.Method),
+				slog.String("client_ip", realip.FromRequest(r)),
+			)
+
+			ctx := WithRequestID(r.Context(), id)
+			ctx = NewContext(ctx, logger)
+			ctx = context.WithValue(ctx, routeKey{}, route)
+			// ServeMux writes the matched pattern into the request it is given
+			route.req = r.WithContext(ctx)
+			next.ServeHTTP(w, route.req)
+		})
+	}
+}
+
+type routeKey struct{}
+
+// routeValue is the route of a request, resolved at log time since it is only known once
+// the request has been routed
+type routeValue struct {
+	req   *http.Request
+	route atomic.Pointer[string]
+}
+
+func (v *routeValue) String() string {
+	if route := v.route.Load(); route != nil {
+		return *route
+	}
+	if v.req.Pattern != "" {
+		return v.req.Pattern
+	}
+	return v.req.URL.Path
+}
+
+// routeHandler adds the route to every record; handlers resolve attributes given to With
+// right away, which is before routing
+type routeHandler struct {
+	slog.Handler
+	route *routeValue
+}
+
+func (h routeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(slog.String("route", h.route.String()))
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h routeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return routeHandler{h.Handler.WithAttrs(attrs), h.route}
+}
+
+func (h routeHandler) WithGroup(name string) slog.Handler {
+	return routeHandler{h.Handler.WithGroup(name), h.route}
+}
+
+// SetRoute sets the route logged by the request logger in ctx
+// Use it with routers that don't set http.Request.Pattern (Echo, chi) or behind middleware
+// that copies the request before ServeMux sees it; no-op outside Middleware.
+// Example:
+//
+//	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+//		return func(c echo.Context) error {
+//			logging.SetRoute(c.Request().Context(), c.Request().Method+" "+c.Path())
+//			return next(c)
+//		}
+//	})
+func SetRoute(ctx context.Context, route string) {
+	if v, ok := ctx.Value(routeKey{}).(*routeValue); ok {
+		v.route.Store(&route)
+	}
+}
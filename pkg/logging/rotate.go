@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotatingFile is an io.WriteCloser that rotates the log file by size and/or age
+// Rotated files are renamed to "<name>.<timestamp>" and only the newest MaxBackups are kept.
+// Any io.Writer (e.g. lumberjack.Logger) can be used instead via Options.Output.
+// Example:
+//
+//	f, _ := logging.NewRotatingFile("logs/app.log", 100<<20, 24*time.Hour, 7)
+//	logger := logging.New(f, "info", "json")
+type RotatingFile struct {
+	path       string
+	maxBytes   int64
+	every      time.Duration
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// NewRotatingFile opens (or creates) path for appending
+// maxBytes <= 0 disables size rotation, every <= 0 disables time rotation,
+// maxBackups <= 0 keeps all rotated files.
+func NewRotatingFile(path string, maxBytes int64, every time.Duration, maxBackups int) (*RotatingFile, error) {
+	rf := &RotatingFile{path: path, maxBytes: maxBytes, every: every, maxBackups: maxBackups}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// Write appends p, rotating first if the size or age limit would be exceeded
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.file == nil {
+		if err := rf.open(); err != nil {
+			return 0, err
+		}
+	}
+	if (rf.maxBytes > 0 && rf.size+int64(len(p)) > rf.maxBytes && rf.size > 0) ||
+		(rf.every > 0 && time.Since(rf.opened) >= rf.every) {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := rf.file.Write(p)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Rotate forces a rotation (e.g. on SIGHUP)
+func (rf *RotatingFile) Rotate() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	return rf.rotate()
+}
+
+// Close closes the current file
+func (rf *RotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	rf.file = f
+	rf.size = info.Size()
+	rf.opened = time.Now()
+	return nil
+}
+
+func (rf *RotatingFile) rotate() error {
+	if rf.file != nil {
+		rf.file.Close()
+		rf.file = nil
+	}
+	backup := rf.path + "." + time.Now().Format("20060102-150405.000")
+	if err := os.Rename(rf.path, backup); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := rf.open(); err != nil {
+		return err
+	}
+	rf.prune()
+	return nil
+}
+
+// prune removes the oldest backups beyond maxBackups
+func (rf *RotatingFile) prune() {
+	if rf.maxBackups <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(rf.path + ".*")
+	if err != nil {
+		return
+	}
+	var backups []string
+	prefix := rf.path + "."
+	for _, m := range matches {
+		if strings.HasPrefix(m, prefix) {
+			backups = append(backups, m)
+		}
+	}
+	if len(backups) <= rf.maxBackups {
+		return
+	}
+	sort.Strings(backups) // timestamp suffix sorts chronologically
+	for _, old := range backups[:len(backups)-rf.maxBackups] {
+		os.Remove(old)
+	}
+}
@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// SamplingConfig limits repetitive logs at or below MaxLevel
+// Within each Tick, the first First records with the same message are logged,
+// then only every Thereafter-th one. Records above MaxLevel are never dropped.
+type SamplingConfig struct {
+	MaxLevel   slog.Level
+	First      int
+	Thereafter int
+	Tick       time.Duration
+}
+
+type sampleCounter struct {
+	reset time.Time
+	n     int
+}
+
+type samplingState struct {
+	mu     sync.Mutex
+	counts map[string]*sampleCounter
+}
+
+// SamplingHandler wraps another slog.Handler and drops excess low-level records
+type SamplingHandler struct {
+	next   slog.Handler
+	config SamplingConfig
+	state  *samplingState
+}
+
+// NewSamplingHandler wraps h with sampling
+// Example:
+//
+//	h := logging.NewSamplingHandler(slog.NewJSONHandler(os.Stdout, nil), logging.SamplingConfig{
+//	    MaxLevel: slog.LevelDebug, First: 10, Thereafter: 100, Tick: time.Second,
+//	})
+//	logger := slog.New(h)
+func NewSamplingHandler(h slog.Handler, config SamplingConfig) *SamplingHandler {
+	if config.First < 0 {
+		config.First = 0
+	}
+	if config.Thereafter < 1 {
+		config.Thereafter = 1
+	}
+	if config.Tick <= 0 {
+		config.Tick = time.Second
+	}
+	return &SamplingHandler{next: h, config: config, state: &samplingState{counts: map[string]*sampleCounter{}}}
+}
+
+// Enabled delegates to the wrapped handler
+func (s *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return s.next.Enabled(ctx, level)
+}
+
+// Handle forwards the record unless it is sampled out
+func (s *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level > s.config.MaxLevel || s.allow(r) {
+		return s.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler sharing the same sampling counters
+func (s *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: s.next.WithAttrs(attrs), config: s.config, state: s.state}
+}
+
+// WithGroup returns a handler sharing the same sampling counters
+func (s *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: s.next.WithGroup(name), config: s.config, state: s.state}
+}
+
+func (s *SamplingHandler) allow(r slog.Record) bool {
+	key := r.Level.String() + "|" + r.Message
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	c, ok := s.state.counts[key]
+	if !ok || now.After(c.reset) {
+		// Drop stale counters occasionally so unique messages don't grow the map forever
+		if len(s.state.counts) > 10000 {
+			s.state.counts = map[string]*sampleCounter{}
+		}
+		c = &sampleCounter{reset: now.Add(s.config.Tick)}
+		s.state.counts[key] = c
+	}
+	c.n++
+	if c.n <= s.config.First {
+		return true
+	}
+	return (c.n-s.config.First)%s.config.Thereafter == 0
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+)
+
+// DBStatser is implemented by *sql.DB (use gormDB.DB() for GORM)
+type DBStatser interface {
+	Stats() sql.DBStats
+}
+
+// DBPoolConfig configures MonitorDBPool
+type DBPoolConfig struct {
+	// Name labels the metrics (db="main")
+	Name string
+	// Interval between samples (default 5s); shorter intervals catch shorter spikes
+	Interval time.Duration
+	// OnSaturated is called after a sample in which every connection was in use and requests
+	// had to wait; the default logs a warning. last holds the stats of the previous sample.
+	OnSaturated func(name string, stats, last sql.DBStats)
+}
+
+// MonitorDBPool samples the connection pool of db on a ticker and publishes db_pool_* metrics in reg
+// Unlike NewDBStatsCollector, which reads the stats at scrape time, it records the peak number
+// of connections in use between scrapes, the pool utilization and the waits of the last
+// interval, and reports saturation as it happens, so pool exhaustion is visible before
+// requests start failing with timeouts. It stops sampling when ctx is done; stop also
+// removes the metrics from reg, so a closed pool doesn't keep reporting stale values.
+// Example:
+//
+//	stop := metrics.MonitorDBPool(ctx, metrics.Default, db, metrics.DBPoolConfig{Name: "main", Interval: time.Second})
+//	defer stop()
+func MonitorDBPool(ctx context.Context, reg *Registry, db DBStatser, cfg DBPoolConfig) (stop func()) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.OnSaturated == nil {
+		cfg.OnSaturated = logSaturated
+	}
+	// Seed with the current stats so waits from before the monitor started don't count
+	// as the first interval and trigger a false saturation report
+	m := &poolMonitor{db: db, cfg: cfg, last: db.Stats()}
+	reg.MustRegister(m)
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.sample()
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			reg.Unregister(m)
+		})
+	}
+}
+
+type poolMonitor struct {
+	db  DBStatser
+	cfg DBPoolConfig
+
+	mu        sync.Mutex
+	last      sql.DBStats
+	peak      int // highest InUse since the previous scrape
+	waits     int64
+	waitTime  time.Duration
+	saturated bool
+}
+
+func (m *poolMonitor) sample() {
+	s := m.db.Stats()
+	m.mu.Lock()
+	prev := m.last
+	m.last = s
+	m.peak = max(m.peak, s.InUse)
+	m.waits = s.WaitCount - prev.WaitCount
+	m.waitTime = s.WaitDuration - prev.WaitDuration
+	m.saturated = s.MaxOpenConnections > 0 && s.InUse >= s.MaxOpenConnections && m.waits > 0
+	saturated := m.saturated
+	m.mu.Unlock()
+
+	if saturated {
+		m.cfg.OnSaturated(m.cfg.Name, s, prev)
+	}
+}
+
+func (m *poolMonitor) Collect() []Family {
+	m.mu.Lock()
+	s, peak, waits, waitTime, saturated := m.last, max(m.peak, m.last.InUse), m.waits, m.waitTime, m.saturated
+	m.peak = 0
+	m.mu.Unlock()
+
+	labels := []Label{{Name: "db", Value: m.cfg.Name}}
+	gauge := func(metric, help string, v float64) Family {
+		return Family{Name: metric, Help: help, Type: "gauge", Samples: []Sample{{Name: metric, Labels: labels, Value: v}}}
+	}
+	counter := func(metric, help string, v float64) Family {
+		return Family{Name: metric, Help: help, Type: "counter", Samples: []Sample{{Name: metric, Labels: labels, Value: v}}}
+	}
+	utilization := 0.0
+	if s.MaxOpenConnections > 0 {
+		utilization = float64(s.InUse) / float64(s.MaxOpenConnections)
+	}
+	sat := 0.0
+	if saturated {
+		sat = 1
+	}
+	return []Family{
+		gauge("db_pool_max_open_connections", "Maximum number of open connections to the database.", float64(s.MaxOpenConnections)),
+		gauge("db_pool_open_connections", "Connections established, in use and idle, at the last sample.", float64(s.OpenConnections)),
+		gauge("db_pool_in_use_connections", "Connections in use at the last sample.", float64(s.InUse)),
+		gauge("db_pool_in_use_connections_peak", "Highest number of connections in use sampled since the previous scrape.", float64(peak)),
+		gauge("db_pool_idle_connections", "Idle connections at the last sample.", float64(s.Idle)),
+		gauge("db_pool_utilization_ratio", "Connections in use divided by the maximum open connections (0 when unlimited).", utilization),
+		gauge("db_pool_saturated", "1 when every connection was in use and requests waited during the last interval.", sat),
+		gauge("db_pool_interval_waits", "Connections waited for during the last sample interval.", float64(waits)),
+		gauge("db_pool_interval_wait_seconds", "Time spent waiting for connections during the last sample interval.", waitTime.Seconds()),
+		counter("db_pool_wait_count_total", "The total number of connections waited for.", float64(s.WaitCount)),
+		counter("db_pool_wait_duration_seconds_total", "The total time blocked waiting for a new connection.", s.WaitDuration.Seconds()),
+		counter("db_pool_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.", float64(s.MaxIdleClosed)),
+		counter("db_pool_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime.", float64(s.MaxIdleTimeClosed)),
+		counter("db_pool_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.", float64(s.MaxLifetimeClosed)),
+	}
+}
+
+func logSaturated(name string, s, last sql.DBStats) {
+	log.Printf("metrics: db pool %q saturated: %d/%d connections in use, %d waits (%s) since the last sample",
+		name, s.InUse, s.MaxOpenConnections, s.WaitCount-last.WaitCount, s.WaitDuration-last.WaitDuration)
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import "database/sql"
+
+// NewDBStatsCollector exposes sql.DB connection pool stats (db_* metrics) labeled by name
+// Works for GORM too via sqlDB, _ := gormDB.DB().
+// Example:
+//
+//	metrics.Default.MustRegister(metrics.NewDBStatsCollector(db, "main"))
+func NewDBStatsCollector(db *sql.DB, name string) Collector {
+	return CollectorFunc(func() []Family {
+		s := db.Stats()
+		labels := []Label{{Name: "db", Value: name}}
+		gauge := func(metric, help string, v float64) Family {
+			return Family{Name: metric, Help: help, Type: "gauge", Samples: []Sample{{Name: metric, Labels: labels, Value: v}}}
+		}
+		counter := func(metric, help string, v float64) Family {
+			return Family{Name: metric, Help: help, Type: "counter", Samples: []Sample{{Name: metric, Labels: labels, Value: v}}}
+		}
+		return []Family{
+			gauge("db_max_open_connections", "Maximum number of open connections to the database.", float64(s.MaxOpenConnections)),
+			gauge("db_open_connections", "The number of established connections both in use and idle.", float64(s.OpenConnections)),
+			gauge("db_in_use_connections", "The number of connections currently in use.", float64(s.InUse)),
+			gauge("db_idle_connections", "The number of idle connections.", float64(s.Idle)),
+			counter("db_wait_count_total", "The total number of connections waited for.", float64(s.WaitCount)),
+			counter("db_wait_duration_seconds_total", "The total time blocked waiting for a new connection.", s.WaitDuration.Seconds()),
+			counter("db_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns.", float64(s.MaxIdleClosed)),
+			counter("db_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime.", float64(s.MaxIdleTimeClosed)),
+			counter("db_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime.", float64(s.MaxLifetimeClosed)),
+		}
+	})
+}
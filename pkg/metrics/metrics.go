@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Sample is a single metric value with its labels
+type Sample struct {
+	Name   string // full sample name, e.g. "http_requests_total" or "latency_seconds_bucket"
+	Labels []Label
+	Value  float64
+}
+
+// Label is a single name/value pair
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Family is a group of samples sharing name, help and type
+type Family struct {
+	Name    string
+	Help    string
+	Type    string // counter | gauge | histogram | untyped
+	Samples []Sample
+}
+
+// Collector produces metric families on each scrape
+// Implement this to expose custom application state.
+type Collector interface {
+	Collect() []Family
+}
+
+// CollectorFunc adapts a function to Collector
+type CollectorFunc func() []Family
+
+// Collect calls f
+func (f CollectorFunc) Collect() []Family { return f() }
+
+// Registry holds collectors and renders them in the Prometheus text format
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default is the shared registry used by package-level helpers and Handler()
+var Default = NewRegistry()
+
+// MustRegister adds collectors to the registry
+func (r *Registry) MustRegister(cs ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, cs...)
+}
+
+// Unregister removes c from the registry and reports whether it was registered
+// Collectors are matched by identity, so keep the value passed to MustRegister;
+// CollectorFunc values can't be compared and are never removed.
+func (r *Registry) Unregister(c Collector) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, x := range r.collectors {
+		if sameCollector(x, c) {
+			r.collectors = append(r.collectors[:i:i], r.collectors[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// sameCollector compares collectors without panicking on uncomparable types such as funcs
+func sameCollector(a, b Collector) bool {
+	ta := reflect.TypeOf(a)
+	return ta == reflect.TypeOf(b) && ta != nil && ta.Comparable() && a == b
+}
+
+// Gather collects all families sorted by name
+// Families of the same name from different collectors (e.g. one pool collector per
+// database) are merged into one, keeping the help and type of the first, since the text
+// format allows a single HELP/TYPE block per metric.
+func (r *Registry) Gather() []Family {
+	r.mu.RLock()
+	cs := append([]Collector(nil), r.collectors...)
+	r.mu.RUnlock()
+
+	var families []Family
+	index := map[string]int{}
+	for _, c := range cs {
+		for _, f := range c.Collect() {
+			if i, ok := index[f.Name]; ok {
+				families[i].Samples = append(families[i].Samples, f.Samples...)
+				continue
+			}
+			index[f.Name] = len(families)
+			f.Samples = append([]Sample(nil), f.Samples...)
+			families = append(families, f)
+		}
+	}
+	sort.SliceStable(families, func(i, j int) bool { return families[i].Name < families[j].Name })
+	return families
+}
+
+// Handler serves the registry in the Prometheus text exposition format
+// Example:
+//
+//	mux.Handle("/metrics", metrics.Default.Handler())
+//	// Echo: e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		for _, f := range r.Gather() {
+			writeFamily(bw, f)
+		}
+		bw.Flush()
+	})
+}
+
+// Handler serves the Default registry
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+func writeFamily(w *bufio.Writer, f Family) {
+	if f.Help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", f.Name, strings.NewReplacer("\\", `\\`, "\n", `\n`).Replace(f.Help))
+	}
+	if f.Type != "" {
+		fmt.Fprintf(w, "# TYPE %s %s\n", f.Name, f.Type)
+	}
+	for _, s := range f.Samples {
+		w.WriteString(s.Name)
+		if len(s.Labels) > 0 {
+			w.WriteByte('{')
+			for i, l := range s.Labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(l.Name + `="` + escapeLabel(l.Value) + `"`)
+			}
+			w.WriteByte('}')
+		}
+		w.WriteByte(' ')
+		w.WriteString(formatFloat(s.Value))
+		w.WriteByte('\n')
+	}
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer("\\", `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
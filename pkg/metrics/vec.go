@@ -0,0 +1,210 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are latency buckets in seconds suitable for HTTP handlers and DB queries
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelKey joins label values into a map key
+func labelKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+func makeLabels(names, values []string) []Label {
+	labels := make([]Label, len(names))
+	for i, n := range names {
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		labels[i] = Label{Name: n, Value: v}
+	}
+	return labels
+}
+
+// valueVec stores one float per label combination (used by Counter and Gauge)
+type valueVec struct {
+	name, help, typ string
+	labelNames      []string
+
+	mu     sync.Mutex
+	values map[string]float64
+	labels map[string][]Label
+}
+
+func newValueVec(name, help, typ string, labelNames []string) *valueVec {
+	return &valueVec{
+		name: name, help: help, typ: typ, labelNames: labelNames,
+		values: map[string]float64{}, labels: map[string][]Label{},
+	}
+}
+
+func (v *valueVec) update(fn func(float64) float64, labelValues []string) {
+	key := labelKey(labelValues)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.labels[key]; !ok {
+		v.labels[key] = makeLabels(v.labelNames, labelValues)
+	}
+	v.values[key] = fn(v.values[key])
+}
+
+func (v *valueVec) Collect() []Family {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	keys := make([]string, 0, len(v.values))
+	for k := range v.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	f := Family{Name: v.name, Help: v.help, Type: v.typ}
+	for _, k := range keys {
+		f.Samples = append(f.Samples, Sample{Name: v.name, Labels: v.labels[k], Value: v.values[k]})
+	}
+	return []Family{f}
+}
+
+// Counter is a monotonically increasing value, optionally partitioned by labels
+type Counter struct{ *valueVec }
+
+// NewCounter creates a counter; register it with Registry.MustRegister
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{newValueVec(name, help, "counter", labelNames)}
+}
+
+// Inc adds 1 for the given label values
+func (c *Counter) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add adds delta (must be >= 0) for the given label values
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.update(func(v float64) float64 { return v + delta }, labelValues)
+}
+
+// Gauge is a value that can go up and down, optionally partitioned by labels
+type Gauge struct{ *valueVec }
+
+// NewGauge creates a gauge; register it with Registry.MustRegister
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{newValueVec(name, help, "gauge", labelNames)}
+}
+
+// Set sets the gauge for the given label values
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.update(func(float64) float64 { return value }, labelValues)
+}
+
+// Add adds delta (can be negative) for the given label values
+func (g *Gauge) Add(delta float64, labelValues ...string) {
+	g.update(func(v float64) float64 { return v + delta }, labelValues)
+}
+
+// Inc adds 1; Dec subtracts 1
+func (g *Gauge) Inc(labelValues ...string) { g.Add(1, labelValues...) }
+func (g *Gauge) Dec(labelValues ...string) { g.Add(-1, labelValues...) }
+
+type histogramData struct {
+	labels []Label
+	counts []uint64 // per bucket (non-cumulative), last is +Inf
+	sum    float64
+	count  uint64
+}
+
+// Histogram tracks value distributions (e.g. latencies) in cumulative buckets
+type Histogram struct {
+	name, help string
+	labelNames []string
+	buckets    []float64
+
+	mu   sync.Mutex
+	data map[string]*histogramData
+}
+
+// NewHistogram creates a histogram; nil buckets uses DefaultBuckets
+func NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	b := append([]float64(nil), buckets...)
+	sort.Float64s(b)
+	return &Histogram{name: name, help: help, labelNames: labelNames, buckets: b, data: map[string]*histogramData{}}
+}
+
+// Observe records value for the given label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := labelKey(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	d, ok := h.data[key]
+	if !ok {
+		d = &histogramData{labels: makeLabels(h.labelNames, labelValues), counts: make([]uint64, len(h.buckets)+1)}
+		h.data[key] = d
+	}
+	i := sort.SearchFloat64s(h.buckets, value)
+	d.counts[i]++
+	d.sum += value
+	d.count++
+}
+
+// Collect renders _bucket, _sum and _count samples
+func (h *Histogram) Collect() []Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.data))
+	for k := range h.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	f := Family{Name: h.name, Help: h.help, Type: "histogram"}
+	for _, k := range keys {
+		d := h.data[k]
+		var cum uint64
+		for i, upper := range append(append([]float64(nil), h.buckets...), math.Inf(1)) {
+			cum += d.counts[i]
+			labels := append(append([]Label(nil), d.labels...), Label{Name: "le", Value: formatFloat(upper)})
+			f.Samples = append(f.Samples, Sample{Name: h.name + "_bucket", Labels: labels, Value: float64(cum)})
+		}
+		f.Samples = append(f.Samples,
+			Sample{Name: h.name + "_sum", Labels: d.labels, Value: d.sum},
+			Sample{Name: h.name + "_count", Labels: d.labels, Value: float64(d.count)},
+		)
+	}
+	return []Family{f}
+}
+
+// RegisterCounter creates a counter in the Default registry
+// Example:
+//
+//	var ordersCreated = metrics.RegisterCounter("orders_created_total", "Orders created", "channel")
+//	ordersCreated.Inc("web")
+func RegisterCounter(name, help string, labelNames ...string) *Counter {
+	c := NewCounter(name, help, labelNames...)
+	Default.MustRegister(c)
+	return c
+}
+
+// RegisterGauge creates a gauge in the Default registry
+func RegisterGauge(name, help string, labelNames ...string) *Gauge {
+	g := NewGauge(name, help, labelNames...)
+	Default.MustRegister(g)
+	return g
+}
+
+// RegisterHistogram creates a histogram in the Default registry
+// Example:
+//
+//	var paymentLatency = metrics.RegisterHistogram("payment_duration_seconds", "Payment gateway latency", nil, "provider")
+//	paymentLatency.Observe(time.Since(start).Seconds(), "stripe")
+func RegisterHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	h := NewHistogram(name, help, buckets, labelNames...)
+	Default.MustRegister(h)
+	return h
+}
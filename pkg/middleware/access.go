@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net"
+	"net/http"
+
+	"github.com/yoockh/go-api-utils/pkg/realip"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// BasicAuth requires HTTP basic auth with the given credentials
+// Use this to protect internal endpoints (debug, metrics, admin tools)
+// Example:
+//
+//	handler := middleware.BasicAuth("ops", os.Getenv("DEBUG_PASSWORD"))(debugMux)
+func BasicAuth(username, password string) func(http.Handler) http.Handler {
+	wantUser := sha256.Sum256([]byte(username))
+	wantPass := sha256.Sum256([]byte(password))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			gotUser := sha256.Sum256([]byte(user))
+			gotPass := sha256.Sum256([]byte(pass))
+			// Compare hashes in constant time so length and content don't leak via timing
+			userOK := subtle.ConstantTimeCompare(gotUser[:], wantUser[:]) == 1
+			passOK := subtle.ConstantTimeCompare(gotPass[:], wantPass[:]) == 1
+			if !ok || password == "" || !userOK || !passOK {
+				w.Header().Set("WWW-Authenticate", `Basic realm="restricted", charset="UTF-8"`)
+				response.Unauthorized(w, "authentication required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IPAllowlist only lets requests from the given IPs or CIDR ranges through
+// The client IP is taken from the connection (RemoteAddr), or from forwarded headers of
+// trusted proxies when realip.Middleware runs first.
+// Example:
+//
+//	allow, err := middleware.IPAllowlist("10.0.0.0/8", "127.0.0.1")
+//	handler := allow(adminMux)
+func IPAllowlist(entries ...string) (func(http.Handler) http.Handler, error) {
+	nets, err := realip.ParseCIDRs(entries)
+	if err != nil {
+		return nil, err
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := net.ParseIP(realip.FromRequest(r))
+			for _, n := range nets {
+				if ip != nil && n.Contains(ip) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			response.Forbidden(w, "access denied")
+		})
+	}, nil
+}
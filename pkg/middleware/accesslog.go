@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/realip"
+)
+
+// FastLogger is the performance mode of Logger for high-throughput gateways
+// It writes one logfmt line per completed request to out (default os.Stderr):
+//
+//	time=2026-01-02T15:04:05.000Z method=GET path=/products status=200 bytes=512 duration_us=840 ip=10.0.0.7 request_id=4f2c...
+//
+// Lines are built with strconv appends into pooled buffers and the response recorder is
+// pooled too, so a request costs no fmt or interface allocations; each line is a single
+// Write, serialized so concurrent requests don't interleave.
+// Example:
+//
+//	handler := middleware.FastLogger(os.Stdout)(mux)
+func FastLogger(out io.Writer) func(http.Handler) http.Handler {
+	if out == nil {
+		out = os.Stderr
+	}
+	var mu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := recorderPool.Get().(*accessRecorder)
+			*rec = accessRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				rec.ResponseWriter = nil
+				recorderPool.Put(rec)
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			bp := linePool.Get().(*[]byte)
+			b := (*bp)[:0]
+			b = append(b, "time="...)
+			b = start.UTC().AppendFormat(b, "2006-01-02T15:04:05.000Z07:00")
+			b = append(b, " method="...)
+			b = appendLogValue(b, r.Method)
+			b = append(b, " path="...)
+			b = appendLogValue(b, r.URL.Path)
+			b = append(b, " status="...)
+			b = strconv.AppendInt(b, int64(rec.status), 10)
+			b = append(b, " bytes="...)
+			b = strconv.AppendInt(b, rec.bytes, 10)
+			b = append(b, " duration_us="...)
+			b = strconv.AppendInt(b, time.Since(start).Microseconds(), 10)
+			b = append(b, " ip="...)
+			b = appendLogValue(b, realip.FromRequest(r))
+			if id := logging.RequestID(r.Context()); id != "" {
+				b = append(b, " request_id="...)
+				b = appendLogValue(b, id)
+			}
+			b = append(b, '\n')
+
+			mu.Lock()
+			out.Write(b)
+			mu.Unlock()
+
+			if cap(b) <= 4<<10 { // don't keep buffers grown by very long paths
+				*bp = b
+				linePool.Put(bp)
+			}
+		})
+	}
+}
+
+var (
+	linePool     = sync.Pool{New: func() any { b := make([]byte, 0, 256); return &b }}
+	recorderPool = sync.Pool{New: func() any { return &accessRecorder{} }}
+)
+
+// appendLogValue appends v, quoted only when it holds spaces, quotes, '=' or control
+// characters, so client-controlled paths can't forge fields or lines
+func appendLogValue(b []byte, v string) []byte {
+	if v == "" {
+		return append(b, `""`...)
+	}
+	for i := 0; i < len(v); i++ {
+		if c := v[i]; c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			return strconv.AppendQuote(b, v)
+		}
+	}
+	return append(b, v...)
+}
+
+// accessRecorder captures the status and response size for FastLogger
+type accessRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (a *accessRecorder) WriteHeader(code int) {
+	if !a.wroteHeader {
+		a.status = code
+		a.wroteHeader = true
+	}
+	a.ResponseWriter.WriteHeader(code)
+}
+
+func (a *accessRecorder) Write(p []byte) (int, error) {
+	a.wroteHeader = true
+	n, err := a.ResponseWriter.Write(p)
+	a.bytes += int64(n)
+	return n, err
+}
+
+func (a *accessRecorder) Unwrap() http.ResponseWriter {
+	return a.ResponseWriter
+}
@@ -0,0 +1,269 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/clock"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// Quota headers set by APIKey when a store is configured
+const (
+	QuotaLimitHeader     = "X-Quota-Limit"
+	QuotaRemainingHeader = "X-Quota-Remaining"
+	QuotaResetHeader     = "X-Quota-Reset" // Unix seconds when the window resets
+)
+
+// ErrQuotaExceeded is returned once an API key used up its daily or monthly quota (429 quota_exceeded)
+var ErrQuotaExceeded = errs.Define("quota_exceeded", http.StatusTooManyRequests, "API key quota exceeded").
+	Describe("The API key used up its daily or monthly request quota; retry after the time in Retry-After.")
+
+// APIKeyInfo describes the owner and quotas of an API key
+type APIKeyInfo struct {
+	ID           string // stable ID used for usage tracking; never the secret key itself
+	Name         string
+	DailyLimit   int64 // requests per UTC day, 0 = unlimited
+	MonthlyLimit int64 // requests per UTC month, 0 = unlimited
+}
+
+// UsageStore counts requests per API key and period
+// Periods are UTC days ("2006-01-02") and months ("2006-01"). Share one store (e.g. Redis
+// INCR with EXPIRE) between instances; MemoryUsageStore only counts a single process.
+type UsageStore interface {
+	// Increment adds one request to the key's count for period and returns the new count
+	Increment(ctx context.Context, keyID, period string) (int64, error)
+	// Usage returns the count of every key for period
+	Usage(ctx context.Context, period string) (map[string]int64, error)
+}
+
+// MemoryUsageStore is an in-process UsageStore
+// It keeps the current and previous month, days included, so UsageReport can still bill the
+// month that just ended; older periods are dropped when a new month starts counting.
+type MemoryUsageStore struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int64 // period -> key ID -> count
+}
+
+// NewMemoryUsageStore creates an empty MemoryUsageStore
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{counts: map[string]map[string]int64{}}
+}
+
+// Increment implements UsageStore
+func (s *MemoryUsageStore) Increment(_ context.Context, keyID, period string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.counts[period] == nil {
+		s.counts[period] = map[string]int64{}
+		if month, err := time.Parse("2006-01", period); err == nil {
+			s.prune(month.AddDate(0, -1, 0).Format("2006-01"))
+		}
+	}
+	s.counts[period][keyID]++
+	return s.counts[period][keyID], nil
+}
+
+// prune drops the days and months before month ("2006-01"); call with mu held
+func (s *MemoryUsageStore) prune(month string) {
+	for period := range s.counts {
+		if period[:min(len(period), len(month))] < month {
+			delete(s.counts, period)
+		}
+	}
+}
+
+// Usage implements UsageStore
+func (s *MemoryUsageStore) Usage(_ context.Context, period string) (map[string]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]int64, len(s.counts[period]))
+	for k, n := range s.counts[period] {
+		out[k] = n
+	}
+	return out, nil
+}
+
+// APIKeyConfig configures APIKey
+type APIKeyConfig struct {
+	Header string // header carrying the key (default "X-API-Key")
+	// Lookup returns the key's info, or nil for unknown or revoked keys (401)
+	Lookup func(ctx context.Context, key string) (*APIKeyInfo, error)
+	// Usage enables usage tracking and the daily/monthly quotas; nil disables both
+	Usage UsageStore
+}
+
+type apiKeyCtxKey struct{}
+
+// APIKey authenticates requests by API key and enforces their daily and monthly quotas
+// Missing or unknown keys get 401. With Config.Usage every request is counted, responses
+// carry X-Quota-Limit, X-Quota-Remaining and X-Quota-Reset for the tighter window, and keys
+// over a quota get 429 with Retry-After until it resets. Rejected requests are counted too,
+// so usage shows demand; bill up to the limit.
+// Example:
+//
+//	usage := middleware.NewMemoryUsageStore()
+//	requireKey := middleware.APIKey(middleware.APIKeyConfig{
+//		Lookup: func(ctx context.Context, key string) (*middleware.APIKeyInfo, error) {
+//			return keys.FindActive(ctx, sha256Hex(key)) // store hashes, not keys
+//		},
+//		Usage: usage,
+//	})
+//	mux.Handle("/v1/", requireKey(api))
+func APIKey(config APIKeyConfig) func(http.Handler) http.Handler {
+	if config.Lookup == nil {
+		panic("API key lookup cannot be nil")
+	}
+	if config.Header == "" {
+		config.Header = "X-API-Key"
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := strings.TrimSpace(r.Header.Get(config.Header))
+			if key == "" {
+				response.WriteError(w, r, errs.Unauthorized("missing API key"))
+				return
+			}
+			info, err := config.Lookup(r.Context(), key)
+			if err != nil {
+				response.WriteError(w, r, fmt.Errorf("failed to look up API key: %w", err))
+				return
+			}
+			if info == nil {
+				response.WriteError(w, r, errs.Unauthorized("invalid API key"))
+				return
+			}
+
+			ctx := logging.With(r.Context(), "api_key_id", info.ID)
+			errs.SetTag(ctx, "api_key", info.ID)
+			if config.Usage != nil {
+				if err := checkQuota(ctx, w, config.Usage, info, clock.Now().UTC()); err != nil {
+					response.WriteError(w, r, err)
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, apiKeyCtxKey{}, info)))
+		})
+	}
+}
+
+// checkQuota counts the request in the daily and monthly windows and sets the quota headers
+// of the window with the fewest requests left
+func checkQuota(ctx context.Context, w http.ResponseWriter, store UsageStore, info *APIKeyInfo, now time.Time) error {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	windows := []struct {
+		period string
+		limit  int64
+		reset  time.Time
+	}{
+		{day.Format("2006-01-02"), info.DailyLimit, day.AddDate(0, 0, 1)},
+		{month.Format("2006-01"), info.MonthlyLimit, month.AddDate(0, 1, 0)},
+	}
+
+	var limit, remaining int64 = 0, -1
+	var reset time.Time
+	var exceeded bool
+	for _, win := range windows {
+		count, err := store.Increment(ctx, info.ID, win.period)
+		if err != nil {
+			return fmt.Errorf("failed to record API key usage: %w", err)
+		}
+		if win.limit <= 0 {
+			continue
+		}
+		left := win.limit - count
+		if left < 0 {
+			left = 0
+			exceeded = true
+		}
+		if remaining < 0 || left < remaining || (left == remaining && win.reset.After(reset)) {
+			limit, remaining, reset = win.limit, left, win.reset
+		}
+	}
+	if remaining < 0 {
+		return nil // unlimited
+	}
+	h := w.Header()
+	h.Set(QuotaLimitHeader, strconv.FormatInt(limit, 10))
+	h.Set(QuotaRemainingHeader, strconv.FormatInt(remaining, 10))
+	h.Set(QuotaResetHeader, strconv.FormatInt(reset.Unix(), 10))
+	if exceeded {
+		h.Set("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+		return ErrQuotaExceeded.New()
+	}
+	return nil
+}
+
+// APIKeyFromContext returns the key info stored by APIKey
+func APIKeyFromContext(ctx context.Context) (*APIKeyInfo, bool) {
+	info, ok := ctx.Value(apiKeyCtxKey{}).(*APIKeyInfo)
+	return info, ok
+}
+
+// KeyUsage is the usage of one API key in a month
+type KeyUsage struct {
+	KeyID    string           `json:"key_id"`
+	Month    string           `json:"month"` // "2006-01"
+	Requests int64            `json:"requests"`
+	Daily    map[string]int64 `json:"daily"` // "2006-01-02" -> requests
+}
+
+// UsageReport aggregates the usage of every key in the UTC month of month, busiest first,
+// for billing dashboards and invoices
+// Example:
+//
+//	mux.Handle("GET /admin/usage", response.Handle(func(w http.ResponseWriter, r *http.Request) error {
+//		report, err := middleware.UsageReport(r.Context(), usage, time.Now())
+//		if err != nil {
+//			return err
+//		}
+//		response.Success(w, "usage retrieved", report)
+//		return nil
+//	}))
+func UsageReport(ctx context.Context, store UsageStore, month time.Time) ([]KeyUsage, error) {
+	month = month.UTC()
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	period := start.Format("2006-01")
+	totals, err := store.Usage(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load usage for %s: %w", period, err)
+	}
+
+	byKey := make(map[string]*KeyUsage, len(totals))
+	for id, n := range totals {
+		byKey[id] = &KeyUsage{KeyID: id, Month: period, Requests: n, Daily: map[string]int64{}}
+	}
+	for d := start; d.Before(start.AddDate(0, 1, 0)); d = d.AddDate(0, 0, 1) {
+		day := d.Format("2006-01-02")
+		counts, err := store.Usage(ctx, day)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load usage for %s: %w", day, err)
+		}
+		for id, n := range counts {
+			if u, ok := byKey[id]; ok {
+				u.Daily[day] = n
+			}
+		}
+	}
+
+	report := make([]KeyUsage, 0, len(byKey))
+	for _, u := range byKey {
+		report = append(report, *u)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Requests != report[j].Requests {
+			return report[i].Requests > report[j].Requests
+		}
+		return report[i].KeyID < report[j].KeyID
+	})
+	return report, nil
+}
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/auth"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/options"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+type ctxKey string
+
+const (
+	tokenDataKey ctxKey = "token_data"
+	claimsKey    ctxKey = "claims"
+	userIDKey    ctxKey = "user_id"
+	emailKey     ctxKey = "email"
+	roleKey      ctxKey = "role"
+)
+
+// JWTConfig configures the JWT middleware
+// Tokens are the ones issued by auth.GenerateToken (UseCustomToken false)
+// or auth.GenerateCustomToken (UseCustomToken true).
+type JWTConfig struct {
+	SecretKey      string
+	UseCustomToken bool
+	SkipperFunc    func(r *http.Request) bool // return true to let the request through without a token
+}
+
+// JWT validates the Bearer token from the Authorization header and stores its claims in the request context
+// Invalid or missing tokens get 401. Read the claims with CurrentUserID, CurrentEmail, CurrentRole,
+// ClaimsFromContext or TokenDataFromContext. opts are passed to auth.ValidateToken (options.WithClock).
+// Example:
+//
+//	protect := middleware.JWT(middleware.JWTConfig{SecretKey: os.Getenv("JWT_SECRET")})
+//	mux.Handle("/profile", protect(http.HandlerFunc(profileHandler)))
+func JWT(config JWTConfig, opts ...options.Option) func(http.Handler) http.Handler {
+	if config.SecretKey == "" {
+		panic("JWT secret key cannot be empty")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if config.SkipperFunc != nil && config.SkipperFunc(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				response.Unauthorized(w, "missing authorization header")
+				return
+			}
+			parts := strings.Fields(authHeader)
+			if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+				response.Unauthorized(w, "invalid authorization header format")
+				return
+			}
+			tokenString := parts[1]
+
+			ctx, err := Authenticate(r.Context(), config, tokenString, opts...)
+			if err != nil {
+				if err == auth.ErrExpiredToken {
+					response.Unauthorized(w, "token expired")
+					return
+				}
+				response.Unauthorized(w, "invalid token")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Authenticate validates tokenString and returns ctx carrying its claims
+// The request logger gets user_id and the error-reporting scope gets the user.
+// Errors are auth.ErrExpiredToken or auth.ErrInvalidToken. JWT and the gRPC interceptors
+// (pkg-grpc) use it, so the accessors below work the same for both. opts are passed to
+// the auth token validation (options.WithClock).
+func Authenticate(ctx context.Context, config JWTConfig, tokenString string, opts ...options.Option) (context.Context, error) {
+	if config.UseCustomToken {
+		data, err := auth.ValidateCustomToken(tokenString, config.SecretKey, opts...)
+		if err != nil {
+			return ctx, err
+		}
+		ctx = context.WithValue(ctx, tokenDataKey, data)
+		// Convenience extractions (if present)
+		if v, ok := data["user_id"]; ok {
+			ctx = context.WithValue(ctx, userIDKey, v)
+		}
+		if v, ok := data["email"]; ok {
+			ctx = context.WithValue(ctx, emailKey, v)
+		}
+		if v, ok := data["role"]; ok {
+			ctx = context.WithValue(ctx, roleKey, v)
+		}
+	} else {
+		claims, err := auth.ValidateToken(tokenString, config.SecretKey, opts...)
+		if err != nil {
+			return ctx, err
+		}
+		ctx = context.WithValue(ctx, claimsKey, claims)
+		ctx = context.WithValue(ctx, userIDKey, claims.UserID)
+		ctx = context.WithValue(ctx, emailKey, claims.Email)
+		if claims.Role != "" {
+			ctx = context.WithValue(ctx, roleKey, claims.Role)
+		}
+	}
+
+	// Enrich the request-scoped logger and the error-reporting scope
+	ctx = errs.WithScope(ctx)
+	uid := ctx.Value(userIDKey)
+	ctx = logging.With(ctx, "user_id", uid)
+	if uid != nil {
+		errs.SetUser(ctx, fmt.Sprint(uid))
+	}
+	return ctx, nil
+}
+
+// AuthenticateRequest validates the Bearer token of r, or the query parameter param when r
+// has no Authorization header, and returns ctx carrying its claims
+// The query fallback is for WebSocket and EventSource clients, which can't set headers in
+// browsers; "" disables it. Errors are 401 *errs.Error values for response.WriteError.
+func AuthenticateRequest(ctx context.Context, r *http.Request, config JWTConfig, param string, opts ...options.Option) (context.Context, error) {
+	var token string
+	if param != "" {
+		token = r.URL.Query().Get(param)
+	}
+	if h := r.Header.Get("Authorization"); h != "" {
+		parts := strings.Fields(h)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+			return ctx, errs.Unauthorized("invalid authorization header format")
+		}
+		token = parts[1]
+	}
+	if token == "" {
+		return ctx, errs.Unauthorized("missing token")
+	}
+	ctx, err := Authenticate(ctx, config, token, opts...)
+	if err == auth.ErrExpiredToken {
+		return ctx, errs.Unauthorized("token expired")
+	}
+	if err != nil {
+		return ctx, errs.Unauthorized("invalid token")
+	}
+	return ctx, nil
+}
+
+// ClaimsFromContext returns the basic token claims stored by JWT
+// ok is false for custom tokens or unauthenticated requests.
+func ClaimsFromContext(ctx context.Context) (*auth.Claims, bool) {
+	c, ok := ctx.Value(claimsKey).(*auth.Claims)
+	return c, ok
+}
+
+// TokenDataFromContext returns the custom token data stored by JWT, or an empty map
+func TokenDataFromContext(ctx context.Context) map[string]interface{} {
+	if m, ok := ctx.Value(tokenDataKey).(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+// UserIDFromContext returns the authenticated user ID, 0 if not found
+// Use this in services that only receive a context.
+func UserIDFromContext(ctx context.Context) uint {
+	switch t := ctx.Value(userIDKey).(type) {
+	case uint:
+		return t
+	case int:
+		if t >= 0 {
+			return uint(t)
+		}
+	case float64:
+		if t >= 0 {
+			return uint(t)
+		}
+	case string:
+		if n, err := strconv.Atoi(t); err == nil && n >= 0 {
+			return uint(n)
+		}
+	}
+	return 0
+}
+
+// EmailFromContext returns the authenticated user's email or empty string
+func EmailFromContext(ctx context.Context) string {
+	s, _ := ctx.Value(emailKey).(string)
+	return s
+}
+
+// RoleFromContext returns the authenticated user's role or empty string
+func RoleFromContext(ctx context.Context) string {
+	s, _ := ctx.Value(roleKey).(string)
+	return s
+}
+
+// CurrentUserID returns the user ID stored by JWT, 0 if not found
+// Example:
+//
+//	uid := middleware.CurrentUserID(r)
+func CurrentUserID(r *http.Request) uint { return UserIDFromContext(r.Context()) }
+
+// CurrentEmail returns the email stored by JWT or empty string
+func CurrentEmail(r *http.Request) string { return EmailFromContext(r.Context()) }
+
+// CurrentRole returns the role stored by JWT or empty string
+func CurrentRole(r *http.Request) string { return RoleFromContext(r.Context()) }
@@ -4,6 +4,8 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/realip"
 )
 
 // CORS adds Cross-Origin Resource Sharing headers
@@ -28,7 +30,7 @@ func CORS(next http.Handler) http.Handler {
 }
 
 // Logger logs HTTP requests with method, path, and duration
-// Use this to monitor API requests
+// Use this to monitor API requests; see FastLogger for high-throughput services
 // Example:
 //
 //	handler := middleware.Logger(mux)
@@ -37,7 +39,7 @@ func Logger(next http.Handler) http.Handler {
 		start := time.Now()
 
 		// Log request
-		log.Printf("==> [%s] %s %s", r.Method, r.URL.Path, r.RemoteAddr)
+		log.Printf("==> [%s] %s %s", r.Method, r.URL.Path, realip.FromRequest(r))
 
 		// Call next handler
 		next.ServeHTTP(w, r)
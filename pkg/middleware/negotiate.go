@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/yoockh/go-api-utils/pkg/request"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// Negotiate picks the response media type from the Accept header (q-values honoured)
+// and stores it for response.Negotiate; requests accepting none of offers get 406.
+// Without offers, every type registered with response.RegisterRenderer is offered.
+// Example:
+//
+//	mux.Handle("/api/", middleware.Negotiate("application/json", "application/xml")(api))
+//	// Echo: e.Use(echo.WrapMiddleware(middleware.Negotiate()))
+func Negotiate(offers ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			available := offers
+			if len(available) == 0 {
+				available = response.MediaTypes()
+			}
+			w.Header().Add("Vary", "Accept")
+			mt := request.PreferredType(r.Header.Get("Accept"), available...)
+			if mt == "" {
+				response.Error(w, http.StatusNotAcceptable, "not acceptable")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(response.WithMediaType(r.Context(), mt)))
+		})
+	}
+}
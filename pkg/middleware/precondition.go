@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/yoockh/go-api-utils/pkg/request"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// RequireIfMatch answers PUT, PATCH and DELETE requests without an If-Match header with 428
+// Handlers still compare the tag with request.CheckIfMatch once the resource is loaded.
+// Example:
+//
+//	mux.Handle("/products/", middleware.RequireIfMatch(products))
+func RequireIfMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if request.RequiresIfMatch(r.Method) && r.Header.Get("If-Match") == "" {
+			response.Error(w, http.StatusPreconditionRequired, "If-Match header is required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
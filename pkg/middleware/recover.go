@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// RecoverConfig configures RecoverWithConfig
+type RecoverConfig struct {
+	// RePanic panics again after reporting and responding, so development servers and
+	// debuggers stop on it; net/http then aborts the connection. Leave it off in production.
+	RePanic bool
+}
+
+// Recover turns panics into 500 responses and sends them to the error reporter
+// Put it outermost so the reported event includes values set by inner middleware (e.g. user ID).
+// Example:
+//
+//	handler := middleware.Recover(middleware.Logger(mux))
+func Recover(next http.Handler) http.Handler {
+	return RecoverWithConfig(RecoverConfig{})(next)
+}
+
+// RecoverWithConfig is Recover with options
+// The reported panic carries the request ID, route and user ID; the 500 envelope includes the
+// request ID ({"error":"internal server error","code":"internal","request_id":"..."}) so users
+// can quote it to support. When the handler already sent the status line the panic is only
+// logged and reported. The ID comes from logging.Middleware when it runs inside Recover,
+// otherwise from X-Request-ID or a new one, and is echoed in the X-Request-ID header.
+// Example:
+//
+//	recover := middleware.RecoverWithConfig(middleware.RecoverConfig{
+//		RePanic: os.Getenv("APP_ENV") == "development",
+//	})
+//	handler := recover(logging.Middleware(logger)(mux))
+func RecoverWithConfig(config RecoverConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(errs.WithScope(r.Context()))
+			cw := &commitWriter{ResponseWriter: w}
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+				id := panicRequestID(w, r)
+				r = r.WithContext(logging.WithRequestID(r.Context(), id))
+				log.Printf("panic (request %s): %v\n%s", id, rec, debug.Stack())
+				errs.ReportPanic(r, rec)
+				if !cw.committed {
+					response.Negotiate(w, r, http.StatusInternalServerError, response.Response{
+						Success:   false,
+						Error:     "internal server error",
+						Code:      "internal",
+						RequestID: id,
+					})
+				}
+				if config.RePanic {
+					panic(rec)
+				}
+			}()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// commitWriter records whether the status line has been sent, after which a panic can only be
+// logged: writing the 500 would append to the partial response
+type commitWriter struct {
+	http.ResponseWriter
+	committed bool
+}
+
+func (c *commitWriter) WriteHeader(code int) {
+	if code >= http.StatusOK || code == http.StatusSwitchingProtocols {
+		c.committed = true
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *commitWriter) Write(p []byte) (int, error) {
+	c.committed = true
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *commitWriter) Flush() {
+	c.committed = true
+	http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+func (c *commitWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// panicRequestID finds the request ID set further down the chain (logging.Middleware sets the
+// response header, which outer middleware share), falling back to the request header or a new ID
+func panicRequestID(w http.ResponseWriter, r *http.Request) string {
+	if id := logging.RequestID(r.Context()); id != "" {
+		return id
+	}
+	if id := w.Header().Get(logging.RequestIDHeader); id != "" {
+		return id
+	}
+	id := r.Header.Get(logging.RequestIDHeader)
+	if !logging.ValidRequestID(id) {
+		id = logging.NewRequestID()
+	}
+	w.Header().Set(logging.RequestIDHeader, id)
+	return id
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/client"
+	"github.com/yoockh/go-api-utils/pkg/clock"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// NonceStore remembers nonces for RejectReplays
+// Use one store shared by all instances (e.g. Redis SET NX PX) when the app runs on several;
+// MemoryNonceStore only protects a single process.
+type NonceStore interface {
+	// Add records nonce until ttl passes; fresh is false when it was already recorded
+	Add(ctx context.Context, nonce string, ttl time.Duration) (fresh bool, err error)
+}
+
+// MemoryNonceStore is an in-process NonceStore
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time // nonce -> expiry
+	sweep  time.Time
+}
+
+// NewMemoryNonceStore creates an empty MemoryNonceStore
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: map[string]time.Time{}}
+}
+
+// Add implements NonceStore; expired nonces are dropped at most once per second
+func (s *MemoryNonceStore) Add(_ context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := clock.Now()
+	if now.Sub(s.sweep) > time.Second {
+		for n, exp := range s.nonces {
+			if now.After(exp) {
+				delete(s.nonces, n)
+			}
+		}
+		s.sweep = now
+	}
+	if exp, ok := s.nonces[nonce]; ok && now.Before(exp) {
+		return false, nil
+	}
+	s.nonces[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+// ReplayConfig configures RejectReplays
+type ReplayConfig struct {
+	Store     NonceStore    // default NewMemoryNonceStore()
+	Tolerance time.Duration // accepted age of X-Signature-Timestamp, either way (default 5m)
+}
+
+// RejectReplays refuses requests whose X-Signature-Nonce was already seen or whose
+// X-Signature-Timestamp is outside the tolerance window, with 401
+// Nonces are kept for twice the tolerance, so a captured request can't be replayed once it
+// is forgotten either. Put it after VerifySignature, which makes both headers trustworthy
+// and keeps unsigned requests from filling the store.
+// Example:
+//
+//	verify := middleware.VerifySignature(middleware.SignatureConfig{Keys: keys})
+//	noReplay := middleware.RejectReplays(middleware.ReplayConfig{})
+//	mux.Handle("POST /callbacks/payments", verify(noReplay(paymentCallback)))
+func RejectReplays(config ReplayConfig) func(http.Handler) http.Handler {
+	if config.Store == nil {
+		config.Store = NewMemoryNonceStore()
+	}
+	if config.Tolerance <= 0 {
+		config.Tolerance = 5 * time.Minute
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce := r.Header.Get(client.SignatureNonceHeader)
+			if nonce == "" || len(nonce) > 128 {
+				response.WriteError(w, r, errs.Unauthorized("missing nonce"))
+				return
+			}
+			unix, err := strconv.ParseInt(r.Header.Get(client.SignatureTimestampHeader), 10, 64)
+			if err != nil || absDuration(clock.Since(time.Unix(unix, 0))) > config.Tolerance {
+				response.WriteError(w, r, errs.Unauthorized("request expired"))
+				return
+			}
+
+			keyID, _ := SignatureKeyID(r.Context())
+			fresh, err := config.Store.Add(r.Context(), keyID+":"+nonce, 2*config.Tolerance)
+			if err != nil {
+				response.WriteError(w, r, fmt.Errorf("failed to record nonce: %w", err))
+				return
+			}
+			if !fresh {
+				response.WriteError(w, r, errs.Unauthorized("request already used"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
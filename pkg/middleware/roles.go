@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// RequireRoles allows only requests whose role (stored by JWT) is in the allowed list
+// Roles are compared case-insensitively. Requests without a matching role get 403.
+// Example:
+//
+//	adminOnly := func(h http.Handler) http.Handler {
+//	    return middleware.JWT(jwtConfig)(middleware.RequireRoles("admin")(h))
+//	}
+//	mux.Handle("/admin/", adminOnly(adminMux))
+func RequireRoles(allowed ...string) func(http.Handler) http.Handler {
+	set := map[string]struct{}{}
+	for _, role := range allowed {
+		set[strings.ToLower(strings.TrimSpace(role))] = struct{}{}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := set[strings.ToLower(CurrentRole(r))]; !ok {
+				response.Forbidden(w, "insufficient role")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermissions allows only requests whose token grants every listed permission
+// Permissions come from the "permissions" field of a custom token, either a list
+// (["products:write", "orders:read"]) or a space/comma separated string.
+// Example:
+//
+//	token, _ := auth.GenerateCustomToken(map[string]any{
+//	    "user_id": 1, "permissions": []string{"products:write"},
+//	}, secret, time.Hour)
+//
+//	mux.Handle("/products/import", middleware.JWT(cfg)(middleware.RequirePermissions("products:write")(importHandler)))
+func RequirePermissions(required ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, p := range required {
+				if !HasPermission(r.Context(), p) {
+					response.Forbidden(w, "missing permission: "+p)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HasPermission reports whether the token stored by JWT grants permission
+func HasPermission(ctx context.Context, permission string) bool {
+	for _, p := range PermissionsFromContext(ctx) {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsFromContext returns the permissions of the custom token stored by JWT
+func PermissionsFromContext(ctx context.Context) []string {
+	var perms []string
+	switch v := TokenDataFromContext(ctx)["permissions"].(type) {
+	case []interface{}: // decoded from JSON
+		for _, p := range v {
+			if s, ok := p.(string); ok {
+				perms = append(perms, s)
+			}
+		}
+	case []string:
+		perms = append(perms, v...)
+	case string:
+		perms = strings.FieldsFunc(v, func(r rune) bool { return r == ' ' || r == ',' })
+	}
+	return perms
+}
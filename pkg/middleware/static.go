@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StaticConfig configures the Static middleware
+type StaticConfig struct {
+	// Root is the file system to serve (e.g. an embed.FS sub-tree). If nil, Dir is used.
+	Root fs.FS
+	// Dir is an on-disk directory to serve when Root is nil (e.g. "./web/dist")
+	Dir string
+	// Index is the file served for "/" and as SPA fallback (default "index.html")
+	Index string
+	// SPA serves Index for unknown paths so client-side routing works
+	SPA bool
+	// SkipPrefixes are passed straight to the next handler (default "/api"); they match whole
+	// path segments, so "/api" skips /api and /api/users but not /apidocs
+	SkipPrefixes []string
+	// MaxAge is the Cache-Control max-age for assets (default 1 hour).
+	// Index is always served with "no-cache" so new deploys are picked up.
+	MaxAge time.Duration
+	// ImmutablePrefixes mark fingerprinted assets (e.g. "/assets/") as immutable for one year
+	ImmutablePrefixes []string
+}
+
+// Static serves a frontend build (embedded or on disk) in front of your API
+// Precompressed siblings (file.br, file.gz) are served when the client accepts them.
+// Requests that don't match a file fall through to next (or get Index in SPA mode).
+// Example:
+//
+//	//go:embed dist
+//	var dist embed.FS
+//
+//	sub, _ := fs.Sub(dist, "dist")
+//	handler := middleware.Static(middleware.StaticConfig{Root: sub, SPA: true})(mux)
+func Static(config StaticConfig) func(http.Handler) http.Handler {
+	root := config.Root
+	if root == nil {
+		dir := config.Dir
+		if dir == "" {
+			dir = "."
+		}
+		root = os.DirFS(dir)
+	}
+	if config.Index == "" {
+		config.Index = "index.html"
+	}
+	if config.SkipPrefixes == nil {
+		config.SkipPrefixes = []string{"/api"}
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = time.Hour
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, p := range config.SkipPrefixes {
+				if hasPathPrefix(r.URL.Path, p) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+			if name == "" {
+				name = config.Index
+			}
+			if isDir(root, name) {
+				name = path.Join(name, config.Index)
+			}
+
+			if serveStatic(w, r, root, name, config) {
+				return
+			}
+			// SPA fallback only for page navigations, not missing assets like /app.js
+			if config.SPA && path.Ext(name) == "" && serveStatic(w, r, root, config.Index, config) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isDir(root fs.FS, name string) bool {
+	st, err := fs.Stat(root, name)
+	return err == nil && st.IsDir()
+}
+
+// serveStatic serves name (or its precompressed variant); returns false if it doesn't exist
+func serveStatic(w http.ResponseWriter, r *http.Request, root fs.FS, name string, config StaticConfig) bool {
+	if !fs.ValidPath(name) {
+		return false
+	}
+	st, err := fs.Stat(root, name)
+	if err != nil || st.IsDir() {
+		return false
+	}
+
+	h := w.Header()
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype == "" {
+		ctype = "application/octet-stream"
+	}
+	h.Set("Content-Type", ctype)
+	h.Add("Vary", "Accept-Encoding")
+
+	switch {
+	case path.Base(name) == config.Index:
+		h.Set("Cache-Control", "no-cache")
+	case hasAnyPrefix("/"+name, config.ImmutablePrefixes):
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	default:
+		h.Set("Cache-Control", "public, max-age="+strconv.Itoa(int(config.MaxAge/time.Second)))
+	}
+
+	served := name
+	encodings := []struct {
+		token, ext string
+		q          float64
+	}{{"br", ".br", 0}, {"gzip", ".gz", 0}}
+	for i := range encodings {
+		encodings[i].q = encodingQuality(r.Header.Values("Accept-Encoding"), encodings[i].token)
+	}
+	// Highest q first; br wins ties as it compresses better
+	sort.SliceStable(encodings, func(i, j int) bool { return encodings[i].q > encodings[j].q })
+	for _, enc := range encodings {
+		if enc.q <= 0 {
+			continue
+		}
+		if cst, err := fs.Stat(root, name+enc.ext); err == nil && !cst.IsDir() {
+			h.Set("Content-Encoding", enc.token)
+			served, st = name+enc.ext, cst
+			break
+		}
+	}
+
+	f, err := root.Open(served)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return false
+		}
+		rs = bytes.NewReader(data)
+	}
+	http.ServeContent(w, r, name, st.ModTime(), rs)
+	return true
+}
+
+// encodingQuality returns the q-value the Accept-Encoding headers give token, 0 when it is
+// not accepted ("gzip;q=0" refuses it, "*" covers encodings not listed)
+func encodingQuality(headers []string, token string) float64 {
+	q, wildcard := -1.0, 0.0
+	for _, header := range headers {
+		for _, part := range strings.Split(header, ",") {
+			name, params, _ := strings.Cut(part, ";")
+			name = strings.TrimSpace(name)
+			v := 1.0
+			for _, param := range strings.Split(params, ";") {
+				if s, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+					if f, err := strconv.ParseFloat(s, 64); err == nil {
+						v = f
+					}
+				}
+			}
+			switch {
+			case strings.EqualFold(name, token):
+				q = v
+			case name == "*":
+				wildcard = v
+			}
+		}
+	}
+	if q < 0 {
+		return wildcard
+	}
+	return q
+}
+
+// hasPathPrefix reports whether p is prefix or below it, on a path segment boundary
+func hasPathPrefix(p, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return p == prefix || strings.HasPrefix(p, prefix+"/") || prefix == ""
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoDown is returned by Down when an applied migration has no .down.sql file
+var ErrNoDown = errors.New("migrations: no down migration")
+
+// Table records the applied migration versions
+const Table = "schema_migrations"
+
+// lockID keys the advisory lock held while migrating, so two instances deploying at once
+// apply each migration only once
+const lockID = 7283110264
+
+// NoTransaction is the marker line of migrations that can't run in a transaction, such as
+// CREATE INDEX CONCURRENTLY. Put it on its own line in the .up.sql or .down.sql file and
+// keep such a file to a single statement: a failure can't be rolled back, and PostgreSQL
+// runs several statements sent at once in one implicit transaction anyway.
+const NoTransaction = "-- migrate:no-transaction"
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and whether it has been applied
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Load reads migrations from fsys
+// Files are named <version>_<name>.up.sql and <version>_<name>.down.sql; a plain
+// <version>_<name>.sql is an up migration (the layout dbtest.Config.Migrations accepts).
+// Versions are integers, e.g. 0001 or 20240105120000, applied in numeric order.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, name := range names {
+		base, down := strings.TrimSuffix(name, ".sql"), false
+		switch {
+		case strings.HasSuffix(base, ".down"):
+			base, down = strings.TrimSuffix(base, ".down"), true
+		case strings.HasSuffix(base, ".up"):
+			base = strings.TrimSuffix(base, ".up")
+		}
+		prefix, label, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrations: %s: file name must start with a numeric version", name)
+		}
+		data, err := fs.ReadFile(fsys, path.Clean(name))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("migrations: version %d is used by %q and %q", version, m.Name, label)
+		}
+		target := &m.Up
+		if down {
+			target = &m.Down
+		}
+		if *target != "" {
+			return nil, fmt.Errorf("migrations: duplicate migration %s", name)
+		}
+		*target = string(data)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migrations: version %d (%s) has no up migration", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to a PostgreSQL database
+// Each migration runs in its own transaction together with its schema_migrations row, so a
+// failing migration leaves the schema at the previous version; files marked with
+// NoTransaction run on their own and are recorded afterwards.
+// Example:
+//
+//	//go:embed migrations/*.sql
+//	var migrationFS embed.FS
+//
+//	m, err := migrations.New(db, migrationFS, "migrations")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	applied, err := m.Up(ctx)
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New loads the migrations in dir of fsys ("." for its root)
+func New(db *sql.DB, fsys fs.FS, dir string) (*Migrator, error) {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := Load(sub)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations returns the loaded migrations in version order
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies the pending migrations in version order and returns the ones it applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := done[mig.Version]; ok {
+				continue
+			}
+			err := run(ctx, conn, mig.Up, "INSERT INTO "+Table+" (version, name) VALUES ($1, $2)", mig.Version, mig.Name)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations, newest first, and returns them
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, nil
+	}
+	byVersion := make(map[int64]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		byVersion[mig.Version] = mig
+	}
+
+	var reverted []Migration
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(done))
+		for v := range done {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, v := range versions[:min(steps, len(versions))] {
+			mig, ok := byVersion[v]
+			if !ok {
+				return fmt.Errorf("migrations: applied version %d has no migration file", v)
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("%w for %d_%s", ErrNoDown, mig.Version, mig.Name)
+			}
+			if err := run(ctx, conn, mig.Down, "DELETE FROM "+Table+" WHERE version = $1", mig.Version); err != nil {
+				return fmt.Errorf("failed to revert migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists the migrations in version order with their applied state
+// Applied versions without a migration file are included with an empty Up. It waits for
+// migrations another instance is applying.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var list []Status
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			at, ok := done[mig.Version]
+			list = append(list, Status{Migration: mig, Applied: ok, AppliedAt: at})
+			delete(done, mig.Version)
+		}
+		for v, at := range done {
+			list = append(list, Status{Migration: Migration{Version: v, Name: "(missing)"}, Applied: true, AppliedAt: at})
+		}
+		return nil
+	})
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, err
+}
+
+// locked calls fn on a dedicated connection holding the migration advisory lock, after
+// creating the schema_migrations table
+// The table is created under the lock: concurrent CREATE TABLE IF NOT EXISTS statements
+// can still fail with a unique violation in the system catalogs.
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect for migrations: %w", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", Table, err)
+	}
+	return fn(conn)
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM "+Table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", Table, err)
+	}
+	defer rows.Close()
+	done := map[int64]time.Time{}
+	for rows.Next() {
+		var v int64
+		var at time.Time
+		if err := rows.Scan(&v, &at); err != nil {
+			return nil, err
+		}
+		done[v] = at
+	}
+	return done, rows.Err()
+}
+
+// run executes script and the bookkeeping statement, in one transaction unless script is
+// marked with NoTransaction
+func run(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	if !noTransaction(script) {
+		return inTx(ctx, conn, script, record, args...)
+	}
+	if _, err := conn.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	_, err := conn.ExecContext(ctx, record, args...)
+	return err
+}
+
+// noTransaction reports whether script has the NoTransaction marker line
+func noTransaction(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		if strings.TrimSpace(line) == NoTransaction {
+			return true
+		}
+	}
+	return false
+}
+
+// inTx runs script and the bookkeeping statement in one transaction
+func inTx(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
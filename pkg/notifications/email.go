@@ -0,0 +1,86 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPConfig holds SMTP server configuration for EmailProvider
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// EmailProvider sends notifications as plain text email over SMTP
+// Example:
+//
+//	email := notifications.NewEmailProvider(notifications.SMTPConfig{
+//	    Host: "smtp.example.com", Port: "587", Username: "apikey", Password: "secret", From: "no-reply@example.com",
+//	})
+type EmailProvider struct {
+	config SMTPConfig
+	send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailProvider creates an SMTP email provider
+func NewEmailProvider(config SMTPConfig) *EmailProvider {
+	if config.Port == "" {
+		config.Port = "587"
+	}
+	return &EmailProvider{config: config, send: smtp.SendMail}
+}
+
+// Name returns "email"
+func (p *EmailProvider) Name() string { return "email" }
+
+// Send emails msg.Body to msg.To with msg.Subject
+// Recipient and sender are parsed as RFC 5322 addresses ("Ann <ann@example.com>" works)
+// and a subject with line breaks is rejected, so user input can't inject headers such
+// as Bcc; non-ASCII subjects are Q-encoded.
+func (p *EmailProvider) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return fmt.Errorf("email recipient cannot be empty")
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid email recipient %q: %w", msg.To, err)
+	}
+	from, err := mail.ParseAddress(p.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid email sender %q: %w", p.config.From, err)
+	}
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("email subject cannot contain line breaks")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if p.config.Username != "" {
+		auth = smtp.PlainAuth("", p.config.Username, p.config.Password, p.config.Host)
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from.String() + "\r\n")
+	b.WriteString("To: " + to.String() + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+
+	addr := net.JoinHostPort(p.config.Host, p.config.Port)
+	if err := p.send(addr, auth, from.Address, []string{to.Address}, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/client"
+	"github.com/yoockh/go-api-utils/pkg/retry"
+)
+
+// HTTPProvider posts notifications as JSON to a generic HTTP endpoint
+// Use this for internal services or third-party webhooks that accept JSON
+// Example:
+//
+//	hook := notifications.NewHTTPProvider("webhook", "https://example.com/hooks/orders", map[string]string{
+//	    "Authorization": "Bearer token",
+//	})
+type HTTPProvider struct {
+	name    string
+	url     string
+	headers map[string]string
+	client  *http.Client
+	// encode builds the request body; defaults to JSON-encoding the Message
+	encode func(msg Message) ([]byte, error)
+}
+
+// NewHTTPProvider creates a provider that POSTs the Message as JSON to url
+func NewHTTPProvider(name, url string, headers map[string]string) *HTTPProvider {
+	return &HTTPProvider{
+		name:    name,
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Transport: client.NewTransport(nil), Timeout: 10 * time.Second},
+		encode: func(msg Message) ([]byte, error) {
+			return json.Marshal(msg)
+		},
+	}
+}
+
+// Name returns the provider name given at construction
+func (p *HTTPProvider) Name() string { return p.name }
+
+// Send posts msg to the configured URL, failing on non-2xx responses
+// Network errors, 429 and 5xx responses are retried with backoff.
+func (p *HTTPProvider) Send(ctx context.Context, msg Message) error {
+	body, err := p.encode(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	return retry.Do(ctx, func(ctx context.Context) error {
+		return p.post(ctx, body)
+	}, retry.Attempts(3), retry.ExponentialBackoff(200*time.Millisecond, 2*time.Second), retry.RetryIf(isTransient))
+}
+
+// statusError is returned when the endpoint responds with a non-2xx status
+type statusError struct{ code int }
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("notification endpoint returned status %d", e.code)
+}
+
+// isTransient reports whether a failed delivery is worth retrying
+// Network errors, 429 and 5xx are retried; other 4xx responses are not.
+func isTransient(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	return true
+}
+
+func (p *HTTPProvider) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("failed to build request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range p.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &statusError{code: resp.StatusCode}
+	}
+	return nil
+}
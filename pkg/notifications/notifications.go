@@ -0,0 +1,146 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Message represents a single notification payload
+// "To" is the recipient address for the channel (email address, Slack channel, etc).
+// Providers that post to a fixed destination (webhooks) may ignore it.
+type Message struct {
+	To      string                 `json:"to,omitempty"`
+	Subject string                 `json:"subject,omitempty"`
+	Body    string                 `json:"body"`
+	Data    map[string]interface{} `json:"data,omitempty"`
+}
+
+// Provider sends a message through a single delivery channel
+// Implement this to plug in a new channel (SMS, push, etc)
+type Provider interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+var (
+	ErrUnknownChannel = errors.New("unknown notification channel")
+	ErrNoChannels     = errors.New("no notification channels configured for user")
+)
+
+// Preference describes one channel a user wants to be notified on
+// Target overrides Message.To for that channel when set (e.g. user's email address).
+type Preference struct {
+	Channel string `json:"channel"`
+	Target  string `json:"target,omitempty"`
+}
+
+// PreferenceStore returns notification preferences for a user
+// Implement this on top of your users table or settings service.
+type PreferenceStore interface {
+	Preferences(ctx context.Context, userID uint) ([]Preference, error)
+}
+
+// MemoryPreferences is an in-memory PreferenceStore, safe for concurrent use
+// Use this for tests or small apps where preferences are loaded at startup
+// Example:
+//
+//	prefs := notifications.NewMemoryPreferences()
+//	prefs.Set(1, notifications.Preference{Channel: "email", Target: "user@example.com"})
+type MemoryPreferences struct {
+	mu    sync.RWMutex
+	prefs map[uint][]Preference
+}
+
+// NewMemoryPreferences creates an empty in-memory preference store
+func NewMemoryPreferences() *MemoryPreferences {
+	return &MemoryPreferences{prefs: map[uint][]Preference{}}
+}
+
+// Set replaces all preferences for a user
+func (m *MemoryPreferences) Set(userID uint, prefs ...Preference) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prefs[userID] = append([]Preference(nil), prefs...)
+}
+
+// Preferences returns preferences for a user (nil if none)
+func (m *MemoryPreferences) Preferences(ctx context.Context, userID uint) ([]Preference, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Preference(nil), m.prefs[userID]...), nil
+}
+
+// Notifier routes messages to registered providers
+// Use this as the single entry point for sending notifications from API events
+// Example:
+//
+//	n := notifications.NewNotifier(prefs, emailProvider, slackProvider)
+//	err := n.Notify(ctx, userID, notifications.Message{Subject: "Welcome", Body: "Thanks for signing up"})
+type Notifier struct {
+	providers map[string]Provider
+	prefs     PreferenceStore
+}
+
+// NewNotifier creates a Notifier with the given preference store and providers
+// prefs may be nil if you only use Send with explicit channels.
+func NewNotifier(prefs PreferenceStore, providers ...Provider) *Notifier {
+	n := &Notifier{providers: map[string]Provider{}, prefs: prefs}
+	for _, p := range providers {
+		n.Register(p)
+	}
+	return n
+}
+
+// Register adds or replaces a provider under its Name()
+func (n *Notifier) Register(p Provider) {
+	n.providers[p.Name()] = p
+}
+
+// Send delivers msg through a single channel by provider name
+// Example:
+//
+//	err := n.Send(ctx, "slack", notifications.Message{Body: "New order received"})
+func (n *Notifier) Send(ctx context.Context, channel string, msg Message) error {
+	p, ok := n.providers[channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+	}
+	if err := p.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%s: %w", channel, err)
+	}
+	return nil
+}
+
+// Notify delivers msg to every channel the user has enabled
+// Delivery continues on failure; all errors are joined and returned.
+// Example:
+//
+//	if err := n.Notify(ctx, order.UserID, msg); err != nil {
+//	    log.Printf("notify failed: %v", err)
+//	}
+func (n *Notifier) Notify(ctx context.Context, userID uint, msg Message) error {
+	if n.prefs == nil {
+		return ErrNoChannels
+	}
+	prefs, err := n.prefs.Preferences(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load preferences: %w", err)
+	}
+	if len(prefs) == 0 {
+		return ErrNoChannels
+	}
+
+	var errs []error
+	for _, pref := range prefs {
+		m := msg
+		if pref.Target != "" {
+			m.To = pref.Target
+		}
+		if err := n.Send(ctx, pref.Channel, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
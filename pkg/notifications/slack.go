@@ -0,0 +1,24 @@
+package notifications
+
+import "encoding/json"
+
+// NewSlackProvider creates a provider that posts to a Slack incoming webhook
+// Subject (if any) is rendered in bold above the body.
+// Example:
+//
+//	slack := notifications.NewSlackProvider(os.Getenv("SLACK_WEBHOOK_URL"))
+func NewSlackProvider(webhookURL string) *HTTPProvider {
+	p := NewHTTPProvider("slack", webhookURL, nil)
+	p.encode = func(msg Message) ([]byte, error) {
+		text := msg.Body
+		if msg.Subject != "" {
+			text = "*" + msg.Subject + "*\n" + msg.Body
+		}
+		payload := map[string]string{"text": text}
+		if msg.To != "" {
+			payload["channel"] = msg.To
+		}
+		return json.Marshal(payload)
+	}
+	return p
+}
@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+)
+
+//go:embed ui/*.html
+var uiFS embed.FS
+
+var uiTemplates = template.Must(template.ParseFS(uiFS, "ui/*.html"))
+
+// Default asset locations (pinned versions), loaded with the Subresource Integrity hashes
+// generated into integrity.go (go generate after changing a version); set Assets to an
+// embedded copy of swagger-ui-dist or redoc, or point AssetsURL at a self-hosted one, for
+// offline or strict-CSP deployments.
+//
+//go:generate go run gensri.go
+const (
+	SwaggerUIAssets = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14"
+	RedocAssets     = "https://cdn.jsdelivr.net/npm/redoc@2.1.5"
+)
+
+// DocsConfig configures the interactive documentation page
+type DocsConfig struct {
+	Title     string // page title (default "API Docs")
+	SpecURL   string // URL of the OpenAPI document (set by Mount)
+	Redoc     bool   // render with Redoc instead of Swagger UI
+	AssetsURL string // base URL of the UI assets (default SwaggerUIAssets / RedocAssets)
+
+	// Assets holds the UI files (swagger-ui-dist's swagger-ui.css and swagger-ui-bundle.js,
+	// or redoc's bundles/redoc.standalone.js); Mount serves them at prefix + "/assets" so
+	// the page loads nothing from a third party
+	Assets fs.FS
+
+	// Integrity maps asset files ("swagger-ui-bundle.js", "swagger-ui.css",
+	// "bundles/redoc.standalone.js") to Subresource Integrity hashes ("sha384-..."), so a
+	// tampered CDN file is refused by the browser instead of reading persisted tokens.
+	// Defaults to the generated hashes when AssetsURL is SwaggerUIAssets or RedocAssets.
+	Integrity map[string]string
+
+	// Username and Password enable HTTP basic auth for the page and the document
+	Username string
+	Password string
+}
+
+// DocsHandler serves the Swagger UI (or Redoc) page for cfg.SpecURL
+func DocsHandler(cfg DocsConfig) http.Handler {
+	if cfg.Title == "" {
+		cfg.Title = "API Docs"
+	}
+	name := "swagger.html"
+	if cfg.AssetsURL == "" {
+		cfg.AssetsURL = SwaggerUIAssets
+	}
+	if cfg.Redoc {
+		name = "redoc.html"
+		if cfg.AssetsURL == SwaggerUIAssets {
+			cfg.AssetsURL = RedocAssets
+		}
+	}
+	cfg.AssetsURL = strings.TrimRight(cfg.AssetsURL, "/")
+	if cfg.Integrity == nil {
+		cfg.Integrity = defaultIntegrity[cfg.AssetsURL]
+	}
+
+	var buf bytes.Buffer
+	if err := uiTemplates.ExecuteTemplate(&buf, name, cfg); err != nil {
+		panic("openapi: failed to render docs page: " + err.Error())
+	}
+	page := buf.Bytes()
+
+	return protect(cfg, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	}))
+}
+
+// Mount serves the docs page at prefix and the document at prefix + "/openapi.json"
+// With cfg.Assets set the UI files are served at prefix + "/assets" as well.
+// Example:
+//
+//	//go:embed swagger-ui
+//	var swaggerUI embed.FS
+//
+//	assets, _ := fs.Sub(swaggerUI, "swagger-ui")
+//	spec.Mount(mux, "/docs", openapi.DocsConfig{
+//		Title:    "Books API",
+//		Assets:   assets,
+//		Username: os.Getenv("DOCS_USER"),
+//		Password: os.Getenv("DOCS_PASSWORD"),
+//	})
+//	// Echo: e.Any("/docs*", echo.WrapHandler(docsMux))
+func (s *Spec) Mount(mux *http.ServeMux, prefix string, cfg DocsConfig) {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if cfg.SpecURL == "" {
+		cfg.SpecURL = prefix + "/openapi.json"
+	}
+	if cfg.Title == "" {
+		s.mu.Lock()
+		cfg.Title = s.doc.Info.Title
+		s.mu.Unlock()
+	}
+	if cfg.Assets != nil {
+		if cfg.AssetsURL == "" {
+			cfg.AssetsURL = prefix + "/assets"
+		}
+		mux.Handle(prefix+"/assets/", protect(cfg, http.StripPrefix(prefix+"/assets", http.FileServerFS(cfg.Assets))))
+	}
+	mux.Handle(prefix, DocsHandler(cfg))
+	mux.Handle(prefix+"/openapi.json", protect(cfg, s.Handler()))
+}
+
+// protect wraps h with basic auth when credentials are configured
+func protect(cfg DocsConfig, h http.Handler) http.Handler {
+	if cfg.Username == "" && cfg.Password == "" {
+		return h
+	}
+	return middleware.BasicAuth(cfg.Username, cfg.Password)(h)
+}
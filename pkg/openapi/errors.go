@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// AddErrorCatalog documents error codes as the ErrorCode schema used by ErrorResponse.code
+// The schema lists the codes as an enum (so generated clients get constants) and its
+// description is a table of code, status, default message and description.
+// Example:
+//
+//	spec.AddErrorCatalog(errs.Catalog())
+func (s *Spec) AddErrorCatalog(defs []errs.Definition) {
+	var b strings.Builder
+	b.WriteString("Error codes returned in `code`.\n\n| Code | Status | Message | Description |\n|---|---|---|---|\n")
+	enum := make([]any, 0, len(defs))
+	for _, d := range defs {
+		enum = append(enum, d.Code)
+		fmt.Fprintf(&b, "| `%s` | %d | %s | %s |\n", d.Code, d.Status, tableCell(d.Message), tableCell(d.Description))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc.Components.Schemas["ErrorCode"] = &Schema{Type: "string", Enum: enum, Description: b.String()}
+	if e := s.doc.Components.Schemas["ErrorResponse"]; e != nil {
+		e.Properties["code"] = &Schema{Ref: "#/components/schemas/ErrorCode"}
+	}
+}
+
+func tableCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
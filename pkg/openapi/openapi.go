@@ -0,0 +1,401 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Document is an OpenAPI 3.0 document (the subset this package generates)
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Tags       []Tag               `json:"tags,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is a base URL the API is served from
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations in the rendered docs
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower-case HTTP methods to operations
+type PathItem map[string]*OperationObject
+
+// OperationObject is a documented operation as it appears in the document
+type OperationObject struct {
+	OperationID string                     `json:"operationId,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []Parameter                `json:"parameters,omitempty"`
+	RequestBody *RequestBody               `json:"requestBody,omitempty"`
+	Responses   map[string]*ResponseObject `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the request payload
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// MediaType holds the schema for one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// ResponseObject describes one response status
+type ResponseObject struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Components holds reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how secured operations authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Operation documents a route when registering it with Spec.Route
+// Request, Response, Query and Path are example values (usually zero values) of the structs
+// the handler uses; their schemas are derived from struct tags.
+type Operation struct {
+	ID          string   // operationId (optional)
+	Summary     string   // one-line summary
+	Description string   // longer description (markdown)
+	Tags        []string // grouping in the rendered docs
+	Deprecated  bool
+
+	Request  any // JSON request body, e.g. CreateBookRequest{}
+	Response any // data returned on success, e.g. Book{} or []Book{}
+	Query    any // struct with `query:"name"` tags describing query parameters
+	Path     any // struct with `param:"name"` tags typing path parameters (default string)
+
+	Status    int   // success status; default 201 for POST, 204 when Response is nil on DELETE, else 200
+	Raw       bool  // Response is sent as-is (response.SuccessData) instead of inside the envelope
+	Paginated bool  // envelope includes pagination meta (response.Paginated)
+	Errors    []int // additional documented error statuses (400/422 are added for bodies, 401/403 for Secured)
+	Secured   bool  // requires a Bearer token
+}
+
+// Spec collects routes and builds the OpenAPI document
+// Example:
+//
+//	spec := openapi.New("Books API", "1.0.0")
+//	spec.HandleFunc(mux, "POST /books", createBook, openapi.Operation{
+//		Summary:  "Create a book",
+//		Tags:     []string{"books"},
+//		Request:  CreateBookRequest{},
+//		Response: Book{},
+//		Secured:  true,
+//	})
+//	mux.Handle("GET /openapi.json", spec.Handler())
+type Spec struct {
+	mu  sync.Mutex
+	doc Document
+	gen *generator
+}
+
+// New creates a spec with the standard envelope schemas and a bearer security scheme
+func New(title, version string) *Spec {
+	s := &Spec{
+		doc: Document{
+			OpenAPI: "3.0.3",
+			Info:    Info{Title: title, Version: version},
+			Paths:   map[string]PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+				SecuritySchemes: map[string]*SecurityScheme{
+					"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			},
+		},
+	}
+	s.gen = newGenerator(s.doc.Components.Schemas)
+	s.doc.Components.Schemas["ErrorResponse"] = errorSchema()
+	s.doc.Components.Schemas["PaginationMeta"] = paginationSchema()
+	return s
+}
+
+// SetDescription sets the API description shown at the top of the docs
+func (s *Spec) SetDescription(description string) {
+	s.mu.Lock()
+	s.doc.Info.Description = description
+	s.mu.Unlock()
+}
+
+// AddServer adds a base URL, e.g. "https://api.example.com/v1"
+func (s *Spec) AddServer(url, description string) {
+	s.mu.Lock()
+	s.doc.Servers = append(s.doc.Servers, Server{URL: url, Description: description})
+	s.mu.Unlock()
+}
+
+// AddTag describes a tag used by operations
+func (s *Spec) AddTag(name, description string) {
+	s.mu.Lock()
+	s.doc.Tags = append(s.doc.Tags, Tag{Name: name, Description: description})
+	s.mu.Unlock()
+}
+
+// Route documents method + path
+// Path parameters may be written as {id} (net/http, chi) or :id (Echo, Gin, Fiber).
+// Registering the same method and path again replaces the previous operation.
+// Example:
+//
+//	// document routes registered on an Echo router
+//	for _, r := range e.Routes() {
+//		spec.Route(r.Method, r.Path, docs[r.Name]) // docs: map[string]openapi.Operation
+//	}
+func (s *Spec) Route(method, path string, op Operation) {
+	path, params := normalizePath(path)
+	method = strings.ToLower(method)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.doc.Paths[path]
+	if item == nil {
+		item = PathItem{}
+		s.doc.Paths[path] = item
+	}
+	item[method] = s.operation(method, params, op)
+}
+
+// HandleFunc registers h on mux with a Go 1.22 pattern ("GET /books/{id}") and documents it
+func (s *Spec) HandleFunc(mux *http.ServeMux, pattern string, h http.HandlerFunc, op Operation) {
+	mux.HandleFunc(pattern, h)
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		// Patterns without a method match every method; document them as GET
+		method, path = http.MethodGet, pattern
+	}
+	s.Route(method, path, op)
+}
+
+// Document returns a copy of the current document
+func (s *Spec) Document() Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var doc Document
+	data, _ := json.Marshal(s.doc)
+	_ = json.Unmarshal(data, &doc)
+	return doc
+}
+
+// JSON returns the document encoded as indented JSON
+func (s *Spec) JSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return json.MarshalIndent(s.doc, "", "  ")
+}
+
+// Handler serves the document as JSON (mount it at /openapi.json)
+func (s *Spec) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := s.JSON()
+		if err != nil {
+			http.Error(w, "failed to encode OpenAPI document", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+}
+
+func (s *Spec) operation(method string, pathParams []string, op Operation) *OperationObject {
+	o := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+		Responses:   map[string]*ResponseObject{},
+	}
+
+	// Path parameters: typed from op.Path when given, otherwise strings
+	typed := map[string]Parameter{}
+	if op.Path != nil {
+		for _, p := range s.gen.params(op.Path, "param", "path") {
+			typed[p.Name] = p
+		}
+	}
+	for _, name := range pathParams {
+		p, ok := typed[name]
+		if !ok {
+			p = Parameter{Name: name, In: "path", Schema: &Schema{Type: "string"}}
+		}
+		p.Required = true
+		o.Parameters = append(o.Parameters, p)
+	}
+	if op.Query != nil {
+		o.Parameters = append(o.Parameters, s.gen.params(op.Query, "query", "query")...)
+	}
+
+	errorStatuses := map[int]bool{}
+	for _, code := range op.Errors {
+		errorStatuses[code] = true
+	}
+	if op.Request != nil {
+		o.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: s.gen.schema(op.Request)}},
+		}
+		errorStatuses[http.StatusBadRequest] = true
+		errorStatuses[http.StatusUnprocessableEntity] = true
+	}
+	if op.Secured {
+		o.Security = []map[string][]string{{"bearerAuth": {}}}
+		errorStatuses[http.StatusUnauthorized] = true
+		errorStatuses[http.StatusForbidden] = true
+	}
+
+	status := op.Status
+	if status == 0 {
+		switch {
+		case method == "post":
+			status = http.StatusCreated
+		case method == "delete" && op.Response == nil:
+			status = http.StatusNoContent
+		default:
+			status = http.StatusOK
+		}
+	}
+	success := &ResponseObject{Description: http.StatusText(status)}
+	if status != http.StatusNoContent {
+		success.Content = map[string]MediaType{"application/json": {Schema: s.successSchema(op)}}
+	}
+	o.Responses[strconv.Itoa(status)] = success
+
+	codes := make([]int, 0, len(errorStatuses))
+	for code := range errorStatuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		o.Responses[strconv.Itoa(code)] = &ResponseObject{
+			Description: http.StatusText(code),
+			Content:     map[string]MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/ErrorResponse"}}},
+		}
+	}
+	return o
+}
+
+// successSchema wraps the response data in the standard envelope unless op.Raw is set
+func (s *Spec) successSchema(op Operation) *Schema {
+	var data *Schema
+	if op.Response != nil {
+		data = s.gen.schema(op.Response)
+	}
+	if op.Raw {
+		if data == nil {
+			return &Schema{}
+		}
+		return data
+	}
+	env := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean", Example: true},
+			"message": {Type: "string"},
+		},
+		Required: []string{"success"},
+	}
+	if data != nil {
+		env.Properties["data"] = data
+	}
+	if op.Paginated {
+		env.Properties["meta"] = &Schema{Ref: "#/components/schemas/PaginationMeta"}
+	}
+	return env
+}
+
+// errorSchema is the envelope sent by the error helpers (response.Error, ValidationError, ...)
+func errorSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success": {Type: "boolean", Example: false},
+			"message": {Type: "string"},
+			"error":   {Type: "string", Example: "validation failed"},
+			"code":    {Type: "string", Description: "Machine-readable error code", Example: "validation_failed"},
+			"errors": {
+				Type:                 "object",
+				Description:          "Validation messages by field name (422 only)",
+				AdditionalProperties: &Schema{Type: "array", Items: &Schema{Type: "string"}},
+			},
+		},
+		Required: []string{"success", "error"},
+	}
+}
+
+// paginationSchema matches the meta object passed to response.Paginated
+func paginationSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"page":        {Type: "integer"},
+			"per_page":    {Type: "integer"},
+			"total":       {Type: "integer"},
+			"total_pages": {Type: "integer"},
+		},
+	}
+}
+
+var (
+	colonParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+	braceParam = regexp.MustCompile(`\{([A-Za-z0-9_]+)(?:\.\.\.|:[^}]*)?\}`)
+)
+
+// normalizePath converts router paths to OpenAPI paths and returns the parameter names
+// "/books/:id", "/books/{id}", "/files/{path...}" and chi's "/books/{id:[0-9]+}" are supported.
+func normalizePath(path string) (string, []string) {
+	path = strings.TrimSuffix(path, "{$}")
+	path = colonParam.ReplaceAllString(path, "{$1}")
+	path = braceParam.ReplaceAllString(path, "{$1}")
+	if path == "" {
+		path = "/"
+	}
+	var params []string
+	for _, m := range braceParam.FindAllStringSubmatch(path, -1) {
+		params = append(params, m[1])
+	}
+	return path, params
+}
@@ -0,0 +1,306 @@
+package openapi
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaError is a place where a value doesn't match its schema
+type SchemaError struct {
+	Path    string // dotted path inside the value, e.g. "data.items.0.price"
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// FindOperation returns the documented path and operation matching a request
+// Literal segments win over parameters, so /books/new matches before /books/{id}.
+// Example:
+//
+//	path, op, params := doc.FindOperation("GET", "/books/42") // "/books/{id}", op, {"id": "42"}
+func (d *Document) FindOperation(method, requestPath string) (string, *OperationObject, map[string]string) {
+	method = strings.ToLower(method)
+	segments := strings.Split(strings.Trim(requestPath, "/"), "/")
+
+	var (
+		bestPath   string
+		bestOp     *OperationObject
+		bestParams map[string]string
+		bestScore  = -1
+	)
+	for path, item := range d.Paths {
+		op := item[method]
+		if op == nil {
+			continue
+		}
+		tmpl := strings.Split(strings.Trim(path, "/"), "/")
+		if len(tmpl) != len(segments) {
+			continue
+		}
+		params := map[string]string{}
+		score := 0
+		for i, seg := range tmpl {
+			if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+				if segments[i] == "" {
+					score = -1
+					break
+				}
+				value, err := url.PathUnescape(segments[i])
+				if err != nil {
+					value = segments[i]
+				}
+				params[seg[1:len(seg)-1]] = value
+				continue
+			}
+			if seg != segments[i] {
+				score = -1
+				break
+			}
+			score++
+		}
+		if score > bestScore || (score == bestScore && path < bestPath) {
+			bestPath, bestOp, bestParams, bestScore = path, op, params, score
+		}
+	}
+	if bestOp == nil {
+		return "", nil, nil
+	}
+	return bestPath, bestOp, bestParams
+}
+
+// Response returns the documented response for a status code, falling back to "2XX" and "default"
+func (o *OperationObject) Response(status int) *ResponseObject {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		if r, ok := o.Responses[key]; ok {
+			return r
+		}
+	}
+	return nil
+}
+
+// Validate reports where v, a value decoded from JSON into interface{}, doesn't match schema
+// $refs are resolved against the document's components. With strict set, object properties
+// missing from the schema are reported too, unless it allows additionalProperties.
+// Example:
+//
+//	var body any
+//	json.Unmarshal(rec.Body.Bytes(), &body)
+//	for _, err := range doc.Validate(op.Response(200).Content["application/json"].Schema, body, true) {
+//		t.Error(err)
+//	}
+func (d *Document) Validate(schema *Schema, v any, strict bool) []error {
+	var errs []error
+	d.validate(schema, v, "", strict, &errs, 0)
+	return errs
+}
+
+// ValidateParam checks a raw path, query or header value against a parameter schema
+func (d *Document) ValidateParam(schema *Schema, raw string) error {
+	schema = d.resolve(schema)
+	if schema == nil {
+		return nil
+	}
+	var v any = raw
+	switch schema.Type {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return SchemaError{Message: fmt.Sprintf("%q is not an integer", raw)}
+		}
+		v = float64(n)
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return SchemaError{Message: fmt.Sprintf("%q is not a number", raw)}
+		}
+		v = n
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return SchemaError{Message: fmt.Sprintf("%q is not a boolean", raw)}
+		}
+		v = b
+	case "array", "object":
+		return nil // exploded forms aren't checked
+	}
+	if errs := d.Validate(schema, v, false); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func (d *Document) resolve(s *Schema) *Schema {
+	for i := 0; s != nil && s.Ref != "" && i < 32; i++ {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+func (d *Document) validate(s *Schema, v any, path string, strict bool, errs *[]error, depth int) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if s != nil && s.Ref != "" {
+		ref := s.Ref
+		if s = d.resolve(s); s == nil {
+			fail("unknown schema %s", ref)
+			return
+		}
+	}
+	if s == nil || depth > 64 {
+		return
+	}
+	if v == nil {
+		if s.Type != "" && !s.Nullable {
+			fail("null is not allowed, want %s", s.Type)
+		}
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("got %s, want object", jsonType(v))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := join(path, k)
+			if ps, ok := s.Properties[k]; ok {
+				d.validate(ps, obj[k], child, strict, errs, depth+1)
+			} else if s.AdditionalProperties != nil {
+				d.validate(s.AdditionalProperties, obj[k], child, strict, errs, depth+1)
+			} else if strict && len(s.Properties) > 0 {
+				*errs = append(*errs, SchemaError{Path: child, Message: "property is not documented"})
+			}
+		}
+		d.checkCount(s, len(obj), fail)
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("got %s, want array", jsonType(v))
+			return
+		}
+		for i, item := range arr {
+			d.validate(s.Items, item, join(path, strconv.Itoa(i)), strict, errs, depth+1)
+		}
+		d.checkCount(s, len(arr), fail)
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("got %s, want string", jsonType(v))
+			return
+		}
+		n := len([]rune(str))
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("length %d is below minLength %d", n, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("length %d exceeds maxLength %d", n, *s.MaxLength)
+		}
+		switch s.Format {
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				fail("%q is not an RFC 3339 date-time", str)
+			}
+		case "date":
+			if _, err := time.Parse(time.DateOnly, str); err != nil {
+				fail("%q is not a date (YYYY-MM-DD)", str)
+			}
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok {
+			fail("got %s, want %s", jsonType(v), s.Type)
+			return
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			fail("%v is not an integer", n)
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			fail("%v is below minimum %v", n, *s.Minimum)
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail("%v exceeds maximum %v", n, *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("got %s, want boolean", jsonType(v))
+			return
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(normalizeEnum(e), v) {
+				return
+			}
+		}
+		fail("%v is not one of %v", v, s.Enum)
+	}
+}
+
+func (d *Document) checkCount(s *Schema, n int, fail func(string, ...any)) {
+	if s.MinItems != nil && n < *s.MinItems {
+		fail("has %d elements, want at least %d", n, *s.MinItems)
+	}
+	if s.MaxItems != nil && n > *s.MaxItems {
+		fail("has %d elements, want at most %d", n, *s.MaxItems)
+	}
+}
+
+// normalizeEnum converts enum values built in Go (int64 from tags) to their decoded JSON types
+func normalizeEnum(v any) any {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	}
+	return v
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,99 @@
+package options
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/clock"
+	"github.com/yoockh/go-api-utils/pkg/codec"
+)
+
+// Option configures a constructor of this module
+// The same With* functions are accepted by database.ConnectPostgres, orm.ConnectGORM,
+// the auth token functions, the JWT middlewares, graceful.NewServer and client.New, so a
+// new setting can be added without changing their signatures. Each constructor documents
+// which options it honours and ignores the others.
+type Option func(*Options)
+
+// Options holds the applied settings; zero fields mean "use the default"
+type Options struct {
+	Logger  *slog.Logger
+	Clock   clock.Clock
+	Encoder codec.Codec
+	Pool    Pool
+	Timeout time.Duration
+}
+
+// Pool configures a connection pool; zero fields keep the constructor's defaults
+type Pool struct {
+	MaxOpen     int
+	MaxIdle     int
+	MaxLifetime time.Duration
+	MaxIdleTime time.Duration
+}
+
+// WithLogger sets the logger used for connection and lifecycle messages (default: the log package)
+func WithLogger(l *slog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = l
+	}
+}
+
+// WithClock sets the time source (see pkg/clock), e.g. a clock.Fake in tests
+func WithClock(c clock.Clock) Option {
+	return func(o *Options) {
+		o.Clock = c
+	}
+}
+
+// WithEncoder sets the JSON implementation (see pkg/codec)
+func WithEncoder(c codec.Codec) Option {
+	return func(o *Options) {
+		o.Encoder = c
+	}
+}
+
+// WithPool sets connection pool limits
+// Example:
+//
+//	db, err := database.ConnectPostgresURL(url, options.WithPool(options.Pool{MaxOpen: 50, MaxIdle: 10}))
+func WithPool(p Pool) Option {
+	return func(o *Options) {
+		o.Pool = p
+	}
+}
+
+// WithTimeout sets the constructor's main timeout: the statement timeout for database
+// connections, the read/write timeout for servers
+func WithTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = max(d, 0)
+	}
+}
+
+// Apply applies opts in order; later options override earlier ones
+func Apply(opts ...Option) Options {
+	var o Options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// Now returns the time of the configured clock, clock.Now() if none
+func (o Options) Now() time.Time {
+	if o.Clock != nil {
+		return o.Clock.Now()
+	}
+	return clock.Now()
+}
+
+// JSON returns the configured encoder, codec.JSON() if none
+func (o Options) JSON() codec.Codec {
+	if o.Encoder != nil {
+		return o.Encoder
+	}
+	return codec.JSON()
+}
@@ -0,0 +1,160 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultPerPage is used when per_page is missing or invalid
+	DefaultPerPage = 10
+	// MaxPerPage caps per_page; larger values fall back to DefaultPerPage
+	MaxPerPage = 1000
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for cursors that were not produced by EncodeCursor
+var ErrInvalidCursor = errors.New("pagination: invalid cursor")
+
+// Params is the requested page
+// Offset pagination uses Page and PerPage; cursor pagination uses Cursor and PerPage.
+type Params struct {
+	Page    int    `json:"page" query:"page"`
+	PerPage int    `json:"per_page" query:"per_page"`
+	Cursor  string `json:"cursor,omitempty" query:"cursor"`
+}
+
+// New returns normalized params: page < 1 becomes 1, and per_page outside 1..MaxPerPage becomes DefaultPerPage
+// Example:
+//
+//	p := pagination.New(0, 5000) // {Page: 1, PerPage: 10}
+func New(page, perPage int) Params {
+	return Params{Page: page, PerPage: perPage}.Normalize()
+}
+
+// FromRequest reads page, per_page and cursor from the query string
+// Missing or malformed values use the defaults, so handlers never fail on paging input.
+// Example:
+//
+//	p := pagination.FromRequest(r) // /products?page=2&per_page=20
+//	items, total, err := repo.List(ctx, repository.Query{Page: p.Page, PerPage: p.PerPage})
+//	response.Paginated(w, "products retrieved", items, p.Meta(total))
+func FromRequest(r *http.Request) Params {
+	return FromQuery(r.URL.Query())
+}
+
+// FromQuery reads page, per_page and cursor from query values (e.g. echo's c.QueryParams())
+func FromQuery(q url.Values) Params {
+	page, _ := strconv.Atoi(q.Get("page"))
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	return Params{Page: page, PerPage: perPage, Cursor: q.Get("cursor")}.Normalize()
+}
+
+// Normalize applies the defaults and bounds used everywhere in this module
+func (p Params) Normalize() Params {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PerPage <= 0 || p.PerPage > MaxPerPage {
+		p.PerPage = DefaultPerPage
+	}
+	return p
+}
+
+// Limit returns the LIMIT for the page
+func (p Params) Limit() int {
+	return p.Normalize().PerPage
+}
+
+// Offset returns the OFFSET for the page
+func (p Params) Offset() int {
+	p = p.Normalize()
+	return (p.Page - 1) * p.PerPage
+}
+
+// Meta describes an offset-paginated collection in the response envelope
+type Meta struct {
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+	// Estimated is set when Total is a planner estimate rather than an exact count
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// Meta builds the response meta for a page of a collection with total items
+func (p Params) Meta(total int64) Meta {
+	p = p.Normalize()
+	return Meta{Page: p.Page, PerPage: p.PerPage, Total: total, TotalPages: TotalPages(total, p.PerPage)}
+}
+
+// HasNext reports whether pages follow this one
+func (m Meta) HasNext() bool {
+	return m.Page < m.TotalPages
+}
+
+// HasPrev reports whether pages precede this one
+func (m Meta) HasPrev() bool {
+	return m.Page > 1
+}
+
+// TotalPages returns the number of pages needed for total items (0 for an empty collection)
+func TotalPages(total int64, perPage int) int {
+	if perPage < 1 || total <= 0 {
+		return 0
+	}
+	return int((total + int64(perPage) - 1) / int64(perPage))
+}
+
+// CursorMeta describes a cursor-paginated collection in the response envelope
+type CursorMeta struct {
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// CursorMeta builds the response meta for a cursor page; next is "" on the last page
+// Example:
+//
+//	p := pagination.FromRequest(r)
+//	var after struct{ ID int64 }
+//	if p.Cursor != "" {
+//		if err := pagination.DecodeCursor(p.Cursor, &after); err != nil {
+//			response.BadRequest(w, err.Error())
+//			return
+//		}
+//	}
+//	rows := fetch(after.ID, p.Limit()+1) // one extra row tells whether more follow
+//	next := ""
+//	if len(rows) > p.Limit() {
+//		rows = rows[:p.Limit()]
+//		next, _ = pagination.EncodeCursor(struct{ ID int64 }{rows[len(rows)-1].ID})
+//	}
+//	response.Paginated(w, "events retrieved", rows, p.CursorMeta(next))
+func (p Params) CursorMeta(next string) CursorMeta {
+	return CursorMeta{PerPage: p.Limit(), NextCursor: next, HasMore: next != ""}
+}
+
+// EncodeCursor encodes the position of the last returned row (e.g. its sort key and id) as an opaque cursor
+func EncodeCursor(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor produced by EncodeCursor into v
+func DecodeCursor(cursor string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package realip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// PrivateRanges are the loopback and private networks load balancers and ingress
+// controllers usually connect from; trust them when only your own proxies can reach the app
+var PrivateRanges = []string{"127.0.0.0/8", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "::1", "fc00::/7"}
+
+// Config configures which proxies are trusted to report the client address
+type Config struct {
+	// TrustedProxies lists the CIDRs or IPs of your load balancers and proxies; forwarded
+	// headers from any other peer are ignored, so clients can't spoof their address
+	TrustedProxies []string
+	// Header carries the client address set by the proxies (default "X-Forwarded-For")
+	// X-Forwarded-For is read right to left, skipping trusted hops; single-value headers
+	// like "X-Real-IP" or "CF-Connecting-IP" are used as they are.
+	Header string
+}
+
+// Resolver determines the client IP of requests behind trusted proxies
+type Resolver struct {
+	trusted []*net.IPNet
+	header  string
+}
+
+// New creates a Resolver; it fails on malformed TrustedProxies entries
+func New(cfg Config) (*Resolver, error) {
+	trusted, err := ParseCIDRs(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Header == "" {
+		cfg.Header = "X-Forwarded-For"
+	}
+	return &Resolver{trusted: trusted, header: http.CanonicalHeaderKey(cfg.Header)}, nil
+}
+
+// ClientIP returns the address of the client that sent r
+// Without a trusted peer this is the connection address (RemoteAddr). The method value
+// fits echo.Echo.IPExtractor, so Echo's c.RealIP() agrees with the net/http packages.
+func (res *Resolver) ClientIP(r *http.Request) string {
+	peer := remoteIP(r)
+	if !res.isTrusted(peer) {
+		return peer
+	}
+	values := r.Header.Values(res.header)
+	if res.header != "X-Forwarded-For" {
+		if len(values) > 0 {
+			if ip := parseIP(values[len(values)-1]); ip != "" {
+				return ip
+			}
+		}
+		return peer
+	}
+
+	// Each proxy appends the address it received the request from, so the rightmost
+	// untrusted hop is the client; anything left of it was written by the client.
+	var hops []string
+	for _, v := range values {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := parseIP(hops[i])
+		if ip == "" {
+			break
+		}
+		client = ip
+		if !res.isTrusted(ip) {
+			break
+		}
+	}
+	return client
+}
+
+func (res *Resolver) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range res.trusted {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+type ctxKey struct{}
+
+// Middleware stores the client IP of each request for FromRequest, so logging, audit logs,
+// IP allowlists and rate limiters see the client instead of the load balancer
+// Put it outermost. It fails on malformed TrustedProxies entries.
+// Example:
+//
+//	realIP, err := realip.Middleware(realip.Config{TrustedProxies: []string{"10.0.0.0/8"}})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	handler := realIP(logging.Middleware(logger)(mux))
+func Middleware(cfg Config) (func(http.Handler) http.Handler, error) {
+	res, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), ctxKey{}, res.ClientIP(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}, nil
+}
+
+// FromRequest returns the client IP stored by Middleware, or the connection address
+// without it; forwarded headers are never trusted here on their own
+func FromRequest(r *http.Request) string {
+	if ip := FromContext(r.Context()); ip != "" {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+// FromContext returns the client IP stored by Middleware, "" without it
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxKey{}).(string)
+	return ip
+}
+
+// remoteIP returns the connection address without port
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// parseIP returns the normalized IP in s, "" if s isn't one
+func parseIP(s string) string {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host // some proxies append the port
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// ParseCIDRs accepts CIDRs ("10.0.0.0/8") and single IPs ("127.0.0.1", "::1")
+func ParseCIDRs(entries []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if !strings.Contains(e, "/") {
+			ip := net.ParseIP(e)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address: %s", e)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR: %s", e)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// maxParams is the PostgreSQL limit of bind parameters per statement
+const maxParams = 65535
+
+// Batch is implemented by repositories that write many rows per statement (SQL and Memory)
+// Type-assert a Repository to use it and fall back to one call per row otherwise.
+type Batch[T any, ID comparable] interface {
+	// CreateMany inserts every value or none and sets their ids
+	CreateMany(ctx context.Context, vs []T) error
+	// UpdateMany writes the values whose id exists and returns the ids that don't
+	UpdateMany(ctx context.Context, vs []T) (missing []ID, err error)
+	// DeleteMany removes the rows with the given ids and returns the ids that didn't exist
+	DeleteMany(ctx context.Context, ids []ID) (missing []ID, err error)
+}
+
+// BuildBulkInsertQuery generates a multi-row INSERT for rows rows of columns
+// Example:
+//
+//	query := BuildBulkInsertQuery("products", []string{"name", "price"}, 2)
+//	// Returns: INSERT INTO products (name, price) VALUES ($1, $2), ($3, $4) RETURNING id
+//
+// Without columns the row is inserted with DEFAULT VALUES, which covers a single row only.
+func BuildBulkInsertQuery(table string, columns []string, rows int) string {
+	if len(columns) == 0 {
+		return BuildInsertQuery(table, nil)
+	}
+	return fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES %s RETURNING id",
+		table,
+		strings.Join(columns, ", "),
+		valuesList(len(columns), rows),
+	)
+}
+
+// BuildBulkUpdateQuery generates one UPDATE for rows rows of (id, columns...) values
+// The values are typed by the table's own columns through a UNION with an empty SELECT,
+// so no casts are needed. RETURNING lists the ids that were found.
+// Example:
+//
+//	query := BuildBulkUpdateQuery("products", []string{"name", "price"}, 2)
+//	// Returns: UPDATE products AS t SET name = v.name, price = v.price
+//	//   FROM (SELECT id, name, price FROM products WHERE false UNION ALL VALUES ($1, $2, $3), ($4, $5, $6)) AS v
+//	//   WHERE t.id = v.id RETURNING t.id
+func BuildBulkUpdateQuery(table string, columns []string, rows int) string {
+	setClauses := make([]string, len(columns))
+	for i, col := range columns {
+		setClauses[i] = fmt.Sprintf("%s = v.%s", col, col)
+	}
+	return fmt.Sprintf(
+		"UPDATE %s AS t SET %s FROM (SELECT %s FROM %s WHERE false UNION ALL VALUES %s) AS v WHERE t.id = v.id RETURNING t.id",
+		table,
+		strings.Join(setClauses, ", "),
+		strings.Join(append([]string{"id"}, columns...), ", "),
+		table,
+		valuesList(len(columns)+1, rows),
+	)
+}
+
+// BuildBulkDeleteQuery generates a DELETE for ids ids returning the deleted ids
+// Example:
+//
+//	query := BuildBulkDeleteQuery("products", 3)
+//	// Returns: DELETE FROM products WHERE id IN ($1, $2, $3) RETURNING id
+func BuildBulkDeleteQuery(table string, ids int) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE id IN %s RETURNING id", table, valuesList(ids, 1))
+}
+
+// valuesList renders rows groups of n placeholders: ($1, $2), ($3, $4)
+func valuesList(n, rows int) string {
+	var b strings.Builder
+	for row := 0; row < rows; row++ {
+		if row > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for col := 0; col < n; col++ {
+			if col > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", row*n+col+1)
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// CreateMany inserts vs with multi-row INSERTs and sets their ids from RETURNING id
+// Rows with a zero id get the database default, like Create. When more than one statement
+// is needed (mixed zero and set ids, or more than 65535 parameters) they run in one
+// transaction if the repository is on a *sql.DB or *sql.Conn, so either every row is
+// inserted or none.
+func (r *SQL[T, ID]) CreateMany(ctx context.Context, vs []T) error {
+	var generated, explicit []int
+	for i := range vs {
+		if reflect.ValueOf(&vs[i]).Elem().FieldByIndex(r.model.id).IsZero() {
+			generated = append(generated, i)
+		} else {
+			explicit = append(explicit, i)
+		}
+	}
+	type batch struct {
+		rows    []int
+		columns []string
+	}
+	var batches []batch
+	for _, group := range []batch{{generated, without(r.model.columns, "id")}, {explicit, r.model.columns}} {
+		size := 1 // a model with only an id: one DEFAULT VALUES insert per row
+		if len(group.columns) > 0 {
+			size = maxParams / len(group.columns)
+		}
+		for _, chunk := range chunks(group.rows, size) {
+			batches = append(batches, batch{chunk, group.columns})
+		}
+	}
+	err := r.atomic(ctx, len(batches) > 1, func(db DBTX) error {
+		for _, b := range batches {
+			args := make([]interface{}, 0, len(b.rows)*len(b.columns))
+			for _, i := range b.rows {
+				args = append(args, r.values(&vs[i], b.columns)...)
+			}
+			rows, err := db.QueryContext(ctx, BuildBulkInsertQuery(r.table, b.columns, len(b.rows)), args...)
+			if err != nil {
+				return err
+			}
+			for n := 0; n < len(b.rows) && rows.Next(); n++ {
+				if err := rows.Scan(r.fields(&vs[b.rows[n]], []string{"id"})...); err != nil {
+					rows.Close()
+					return err
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", r.table, err)
+	}
+	return nil
+}
+
+// UpdateMany writes every column of vs with bulk UPDATEs and returns the ids that don't exist
+func (r *SQL[T, ID]) UpdateMany(ctx context.Context, vs []T) ([]ID, error) {
+	columns := without(r.model.columns, "id")
+	all := append([]string{"id"}, columns...)
+	indexes := make([]int, len(vs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	found := map[ID]bool{}
+	err := r.atomic(ctx, len(vs)*len(all) > maxParams, func(db DBTX) error {
+		for _, chunk := range chunks(indexes, maxParams/len(all)) {
+			args := make([]interface{}, 0, len(chunk)*len(all))
+			for _, i := range chunk {
+				args = append(args, r.values(&vs[i], all)...)
+			}
+			if err := r.collectIDs(ctx, db, BuildBulkUpdateQuery(r.table, columns, len(chunk)), args, found); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", r.table, err)
+	}
+	var missing []ID
+	for i := range vs {
+		if id, _ := IDOf[T, ID](vs[i]); !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// DeleteMany removes the rows with the given ids and returns the ids that didn't exist
+func (r *SQL[T, ID]) DeleteMany(ctx context.Context, ids []ID) ([]ID, error) {
+	found := map[ID]bool{}
+	err := r.atomic(ctx, len(ids) > maxParams, func(db DBTX) error {
+		for start := 0; start < len(ids); start += maxParams {
+			chunk := ids[start:min(start+maxParams, len(ids))]
+			args := make([]interface{}, len(chunk))
+			for i, id := range chunk {
+				args[i] = id
+			}
+			if err := r.collectIDs(ctx, db, BuildBulkDeleteQuery(r.table, len(chunk)), args, found); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete %s: %w", r.table, err)
+	}
+	var missing []ID
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// collectIDs runs a query returning ids and marks them in found
+func (r *SQL[T, ID]) collectIDs(ctx context.Context, db DBTX, query string, args []interface{}, found map[ID]bool) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id ID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		found[id] = true
+	}
+	return rows.Err()
+}
+
+// atomic runs fn in a transaction when several statements are needed and the repository
+// can begin one; a repository already on a *sql.Tx runs fn directly
+func (r *SQL[T, ID]) atomic(ctx context.Context, several bool, fn func(db DBTX) error) error {
+	b, ok := r.db.(Beginner)
+	if !several || !ok {
+		return fn(r.db)
+	}
+	tx, err := b.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// chunks splits indexes into slices of at most size
+func chunks(indexes []int, size int) [][]int {
+	var out [][]int
+	for start := 0; start < len(indexes); start += size {
+		out = append(out, indexes[start:min(start+size, len(indexes))])
+	}
+	return out
+}
+
+// CreateMany stores copies of vs, assigning zero ids; nothing is stored when one fails
+func (r *Memory[T, ID]) CreateMany(ctx context.Context, vs []T) error {
+	restore := r.snapshot()
+	for i := range vs {
+		if err := r.Create(ctx, &vs[i]); err != nil {
+			restore()
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateMany replaces the rows with the ids of vs and returns the ids that don't exist
+func (r *Memory[T, ID]) UpdateMany(ctx context.Context, vs []T) ([]ID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var missing []ID
+	for _, v := range vs {
+		id := r.idOf(v)
+		if _, ok := r.rows[id]; !ok {
+			missing = append(missing, id)
+			continue
+		}
+		r.rows[id] = v
+	}
+	return missing, nil
+}
+
+// DeleteMany removes the rows with the given ids and returns the ids that didn't exist
+func (r *Memory[T, ID]) DeleteMany(ctx context.Context, ids []ID) ([]ID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var missing []ID
+	for _, id := range ids {
+		if _, ok := r.rows[id]; !ok {
+			missing = append(missing, id)
+			continue
+		}
+		delete(r.rows, id)
+	}
+	return missing, nil
+}
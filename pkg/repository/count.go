@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// EstimateRows returns the PostgreSQL planner's row estimate for query without running it
+// The estimate comes from EXPLAIN, i.e. table statistics (pg_class.reltuples and column
+// histograms kept up to date by ANALYZE / autovacuum), so it costs a planning step instead
+// of a scan. It can be far off for tables that were never analyzed.
+func EstimateRows(ctx context.Context, db DBTX, query string, args ...interface{}) (int64, error) {
+	var raw []byte
+	if err := db.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+query, args...).Scan(&raw); err != nil {
+		return 0, fmt.Errorf("failed to explain count query: %w", err)
+	}
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil || len(plans) == 0 {
+		return 0, fmt.Errorf("failed to parse query plan: %s", raw)
+	}
+	return int64(plans[0].Plan.Rows), nil
+}
+
+// CountRows counts the rows returned by query (a SELECT without LIMIT/OFFSET)
+// With threshold > 0 the planner estimate is used when it exceeds threshold, and estimated
+// is true; smaller results, and estimates that fail, are counted exactly with COUNT(*).
+// Exact counts on tables with tens of millions of rows dominate list latency, while
+// "about 52,000,000" is all a pager needs.
+// Example:
+//
+//	total, estimated, err := repository.CountRows(ctx, db, "SELECT 1 FROM events WHERE kind = $1", 100000, kind)
+func CountRows(ctx context.Context, db DBTX, query string, threshold int64, args ...interface{}) (total int64, estimated bool, err error) {
+	if threshold > 0 {
+		if est, err := EstimateRows(ctx, db, query, args...); err == nil && est > threshold {
+			return est, true, nil
+		}
+	}
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+query+") AS counted", args...).Scan(&total); err != nil {
+		return 0, false, fmt.Errorf("failed to count rows: %w", err)
+	}
+	return total, false, nil
+}
@@ -0,0 +1,688 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// ErrInvalidFilter is returned for filters that don't parse or use fields that are not filterable (400 invalid_filter)
+var ErrInvalidFilter = errs.Define("invalid_filter", http.StatusBadRequest, "invalid filter parameter").
+	Describe("The filter parameter has a syntax error, an unknown operator or a field that is not filterable.")
+
+const (
+	maxFilterTerms = 32 // comparisons per filter
+	maxFilterDepth = 8  // nested parentheses / NOT
+)
+
+// Op is a comparison operator of a filter expression
+type Op string
+
+// Comparison operators; the bracket form (filter[price][gte]=100) uses these names
+const (
+	OpEq      Op = "eq"      // =
+	OpNe      Op = "ne"      // != or <>
+	OpGt      Op = "gt"      // >
+	OpGte     Op = "gte"     // >=
+	OpLt      Op = "lt"      // <
+	OpLte     Op = "lte"     // <=
+	OpLike    Op = "like"    // ~, case-insensitive "contains"
+	OpIn      Op = "in"      // IN (a, b)
+	OpNull    Op = "null"    // IS NULL, = null
+	OpNotNull Op = "notnull" // IS NOT NULL, != null
+)
+
+var opSymbols = map[Op]string{
+	OpEq: "=", OpNe: "!=", OpGt: ">", OpGte: ">=", OpLt: "<", OpLte: "<=", OpLike: "~",
+}
+
+// Expr is a node of a parsed filter: *Logical, *Not or *Compare
+type Expr interface {
+	// String renders the expression back in filter syntax
+	String() string
+	isExpr()
+}
+
+// Logical joins expressions with "AND" or "OR"
+type Logical struct {
+	Op    string
+	Exprs []Expr
+}
+
+// Not negates an expression
+type Not struct {
+	Expr Expr
+}
+
+// Compare tests a field against literal values
+// Values holds one value, several for OpIn and none for OpNull / OpNotNull. Values are kept
+// as text and sent as parameters, so the database converts them to the column type.
+type Compare struct {
+	Field  string
+	Op     Op
+	Values []string
+}
+
+func (*Logical) isExpr() {}
+func (*Not) isExpr()     {}
+func (*Compare) isExpr() {}
+
+func (e *Logical) String() string {
+	parts := make([]string, len(e.Exprs))
+	for i, x := range e.Exprs {
+		parts[i] = x.String()
+		if _, ok := x.(*Logical); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+e.Op+" ")
+}
+
+func (e *Not) String() string {
+	return "NOT (" + e.Expr.String() + ")"
+}
+
+func (e *Compare) String() string {
+	switch e.Op {
+	case OpNull:
+		return e.Field + " IS NULL"
+	case OpNotNull:
+		return e.Field + " IS NOT NULL"
+	case OpIn:
+		vals := make([]string, len(e.Values))
+		for i, v := range e.Values {
+			vals[i] = quoteFilterValue(v)
+		}
+		return e.Field + " IN (" + strings.Join(vals, ", ") + ")"
+	}
+	return e.Field + opSymbols[e.Op] + quoteFilterValue(e.Values[0])
+}
+
+// ParseFilter parses a filter expression such as
+//
+//	price>100 AND (status=active OR status='on hold') AND name~pen AND tag IN (a, b) AND deleted_at IS NULL
+//
+// Operators are = != <> > >= < <= ~ (contains, case-insensitive), IN and IS [NOT] NULL;
+// AND binds tighter than OR and NOT negates. Values containing spaces or operator characters
+// are quoted with ' or ". Field names are not checked here: use FilterSpec to whitelist them.
+func ParseFilter(s string) (Expr, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	toks, err := lexFilter(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks}
+	e, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, ErrInvalidFilter.Newf("unexpected %q at position %d", t.text, t.pos)
+	}
+	return e, nil
+}
+
+// ParseFilterQuery reads filters from query parameters and joins them with AND
+// It accepts the expression form (?filter=price>100 AND status=active) and the bracket form
+// (?filter[price][gte]=100&filter[status]=active, where the operator defaults to eq, "in"
+// takes a comma-separated list and "null" takes true or false). It returns nil without filters.
+func ParseFilterQuery(values url.Values) (Expr, error) {
+	var exprs []Expr
+	for _, s := range values["filter"] {
+		e, err := ParseFilter(s)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			exprs = append(exprs, e)
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if strings.HasPrefix(k, "filter[") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field, op, err := parseFilterKey(k)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values[k] {
+			c, err := bracketCompare(field, op, v)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, c)
+		}
+	}
+
+	switch len(exprs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return exprs[0], nil
+	}
+	if n := countTerms(exprs...); n > maxFilterTerms {
+		return nil, ErrInvalidFilter.Newf("too many filter conditions (%d, at most %d)", n, maxFilterTerms)
+	}
+	return &Logical{Op: "AND", Exprs: exprs}, nil
+}
+
+// parseFilterKey splits "filter[price][gte]" into ("price", "gte")
+func parseFilterKey(key string) (field string, op Op, err error) {
+	rest := strings.TrimPrefix(key, "filter[")
+	field, rest, ok := strings.Cut(rest, "]")
+	if !ok || field == "" {
+		return "", "", ErrInvalidFilter.Newf("malformed filter parameter %q", key)
+	}
+	switch {
+	case rest == "":
+		return field, OpEq, nil
+	case strings.HasPrefix(rest, "[") && strings.HasSuffix(rest, "]") && len(rest) > 2:
+		op = Op(strings.ToLower(rest[1 : len(rest)-1]))
+		if _, ok := opSymbols[op]; ok || op == OpIn || op == OpNull {
+			return field, op, nil
+		}
+		return "", "", ErrInvalidFilter.Newf("unknown filter operator %q", rest[1:len(rest)-1])
+	}
+	return "", "", ErrInvalidFilter.Newf("malformed filter parameter %q", key)
+}
+
+func bracketCompare(field string, op Op, value string) (*Compare, error) {
+	switch op {
+	case OpIn:
+		vals := strings.Split(value, ",")
+		for i := range vals {
+			vals[i] = strings.TrimSpace(vals[i])
+		}
+		return &Compare{Field: field, Op: OpIn, Values: vals}, nil
+	case OpNull:
+		switch strings.ToLower(value) {
+		case "true", "1", "":
+			return &Compare{Field: field, Op: OpNull}, nil
+		case "false", "0":
+			return &Compare{Field: field, Op: OpNotNull}, nil
+		}
+		return nil, ErrInvalidFilter.Newf("filter[%s][null] must be true or false", field)
+	}
+	return &Compare{Field: field, Op: op, Values: []string{value}}, nil
+}
+
+// FilterSpec maps the public filter fields of a resource to the columns they compare
+// Only listed fields can be filtered on, so user input never reaches the WHERE clause
+// except as parameters. Columns are written by the developer and may be qualified or
+// expressions; OpLike uses ILIKE and needs a text column or expression.
+// Example:
+//
+//	var productFilter = repository.FilterSpec{
+//		"price":    "p.price",
+//		"status":   "p.status",
+//		"name":     "p.name",
+//		"category": "c.slug",
+//	}
+//
+//	cond, err := productFilter.FromQuery(r.URL.Query()) // ?filter=price>100 AND status=active
+//	if err != nil {
+//		return err // 400 invalid_filter
+//	}
+//	query := "SELECT ... FROM products p JOIN categories c ON c.id = p.category_id"
+//	if !cond.Empty() {
+//		query += " WHERE " + cond.Numbered(0)
+//	}
+//	rows, err := db.QueryContext(ctx, query, cond.Args...)
+type FilterSpec map[string]string
+
+// Resolve checks every field of e against the spec and returns a copy using the columns
+func (s FilterSpec) Resolve(e Expr) (Expr, error) {
+	if e == nil {
+		return nil, nil
+	}
+	return mapFilter(e, func(field string) (string, error) {
+		col, ok := s[field]
+		if !ok {
+			return "", ErrInvalidFilter.Newf("cannot filter by %q; allowed: %s", field, strings.Join(s.Fields(), ", "))
+		}
+		return col, nil
+	})
+}
+
+// Compile resolves e and renders it as a parameterized condition; a nil e gives an empty condition
+func (s FilterSpec) Compile(e Expr) (Condition, error) {
+	resolved, err := s.Resolve(e)
+	if err != nil || resolved == nil {
+		return Condition{}, err
+	}
+	return compileFilter(resolved), nil
+}
+
+// FromQuery parses the filter query parameters (see ParseFilterQuery) and compiles them
+func (s FilterSpec) FromQuery(values url.Values) (Condition, error) {
+	e, err := ParseFilterQuery(values)
+	if err != nil {
+		return Condition{}, err
+	}
+	return s.Compile(e)
+}
+
+// Fields returns the filterable fields in alphabetical order
+func (s FilterSpec) Fields() []string {
+	return SortSpec(s).Keys()
+}
+
+// Condition is a self-contained SQL boolean expression with "?" placeholders and its arguments
+// Pass it to GORM as db.Where(c.Query, c.Args...), or use Numbered for database/sql with Postgres.
+type Condition struct {
+	Query string
+	Args  []interface{}
+}
+
+// Empty reports whether the condition has no query
+func (c Condition) Empty() bool {
+	return c.Query == ""
+}
+
+// And joins two conditions; an empty side is dropped
+func (c Condition) And(other Condition) Condition {
+	switch {
+	case c.Empty():
+		return other
+	case other.Empty():
+		return c
+	}
+	return Condition{
+		Query: "(" + c.Query + ") AND (" + other.Query + ")",
+		Args:  append(append([]interface{}(nil), c.Args...), other.Args...),
+	}
+}
+
+// Numbered returns the query with "?" replaced by $n placeholders, starting after offset
+// offset is the number of arguments already used by the surrounding query.
+// Question marks inside single-quoted literals are left alone.
+func (c Condition) Numbered(offset int) string {
+	var b strings.Builder
+	quoted := false
+	for _, r := range c.Query {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == '?' && !quoted:
+			offset++
+			fmt.Fprintf(&b, "$%d", offset)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func compileFilter(e Expr) Condition {
+	var c Condition
+	var render func(e Expr) string
+	render = func(e Expr) string {
+		switch e := e.(type) {
+		case *Logical:
+			parts := make([]string, len(e.Exprs))
+			for i, x := range e.Exprs {
+				parts[i] = render(x)
+				if _, ok := x.(*Logical); ok {
+					parts[i] = "(" + parts[i] + ")"
+				}
+			}
+			return strings.Join(parts, " "+e.Op+" ")
+		case *Not:
+			return "NOT (" + render(e.Expr) + ")"
+		case *Compare:
+			switch e.Op {
+			case OpNull:
+				return e.Field + " IS NULL"
+			case OpNotNull:
+				return e.Field + " IS NOT NULL"
+			case OpIn:
+				marks := make([]string, len(e.Values))
+				for i, v := range e.Values {
+					marks[i] = "?"
+					c.Args = append(c.Args, v)
+				}
+				return e.Field + " IN (" + strings.Join(marks, ", ") + ")"
+			case OpLike:
+				c.Args = append(c.Args, "%"+escapeLike(e.Values[0])+"%")
+				return e.Field + " ILIKE ?"
+			case OpNe:
+				c.Args = append(c.Args, e.Values[0])
+				return e.Field + " <> ?"
+			}
+			c.Args = append(c.Args, e.Values[0])
+			return e.Field + " " + opSymbols[e.Op] + " ?"
+		}
+		return ""
+	}
+	c.Query = render(e)
+	if l, ok := e.(*Logical); ok && l.Op == "OR" {
+		c.Query = "(" + c.Query + ")" // keep the condition safe to join with AND
+	}
+	return c
+}
+
+// mapFilter copies e with every field passed through fn
+func mapFilter(e Expr, fn func(field string) (string, error)) (Expr, error) {
+	switch e := e.(type) {
+	case *Logical:
+		out := &Logical{Op: e.Op, Exprs: make([]Expr, len(e.Exprs))}
+		for i, x := range e.Exprs {
+			m, err := mapFilter(x, fn)
+			if err != nil {
+				return nil, err
+			}
+			out.Exprs[i] = m
+		}
+		return out, nil
+	case *Not:
+		m, err := mapFilter(e.Expr, fn)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: m}, nil
+	case *Compare:
+		field, err := fn(e.Field)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case e.Op == OpIn && len(e.Values) == 0:
+			return nil, ErrInvalidFilter.Newf("%s IN needs at least one value", e.Field)
+		case e.Op != OpIn && e.Op != OpNull && e.Op != OpNotNull && len(e.Values) != 1:
+			return nil, ErrInvalidFilter.Newf("%s %s needs exactly one value", e.Field, e.Op)
+		case e.Op != OpIn && e.Op != OpNull && e.Op != OpNotNull && opSymbols[e.Op] == "":
+			return nil, ErrInvalidFilter.Newf("unknown filter operator %q", e.Op)
+		}
+		return &Compare{Field: field, Op: e.Op, Values: append([]string(nil), e.Values...)}, nil
+	}
+	return nil, ErrInvalidFilter.Newf("unsupported filter node %T", e)
+}
+
+func countTerms(exprs ...Expr) int {
+	n := 0
+	for _, e := range exprs {
+		switch e := e.(type) {
+		case *Logical:
+			n += countTerms(e.Exprs...)
+		case *Not:
+			n += countTerms(e.Expr)
+		case *Compare:
+			n++
+		}
+	}
+	return n
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func quoteFilterValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n'\"=!<>~(),") && !isFilterKeyword(v) {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+func isFilterKeyword(s string) bool {
+	switch strings.ToUpper(s) {
+	case "AND", "OR", "NOT", "IN", "IS", "NULL":
+		return true
+	}
+	return false
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type filterToken struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func lexFilter(s string) ([]filterToken, error) {
+	var toks []filterToken
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, filterToken{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, filterToken{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, filterToken{tokComma, ",", i})
+			i++
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			start := i
+			i++
+			for {
+				if i >= len(s) {
+					return nil, ErrInvalidFilter.Newf("unterminated string at position %d", start)
+				}
+				if s[i] == c {
+					if i+1 < len(s) && s[i+1] == c { // doubled quote
+						b.WriteByte(c)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			toks = append(toks, filterToken{tokString, b.String(), start})
+		case strings.IndexByte("=!<>~", c) >= 0:
+			start := i
+			op := s[i : i+1]
+			if i+1 < len(s) && (s[i:i+2] == ">=" || s[i:i+2] == "<=" || s[i:i+2] == "!=" || s[i:i+2] == "<>") {
+				op = s[i : i+2]
+			}
+			if op == "!" {
+				return nil, ErrInvalidFilter.Newf("unexpected \"!\" at position %d", i)
+			}
+			i += len(op)
+			toks = append(toks, filterToken{tokOp, op, start})
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\n\r()',\"=!<>~", rune(s[i])) {
+				i++
+			}
+			toks = append(toks, filterToken{tokWord, s[start:i], start})
+		}
+	}
+	return append(toks, filterToken{tokEOF, "end of filter", len(s)}), nil
+}
+
+type filterParser struct {
+	toks  []filterToken
+	pos   int
+	terms int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.toks[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the given keyword (case-insensitive)
+func (p *filterParser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokWord && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) or(depth int) (Expr, error) {
+	return p.logical("OR", depth, p.and)
+}
+
+func (p *filterParser) and(depth int) (Expr, error) {
+	return p.logical("AND", depth, p.not)
+}
+
+func (p *filterParser) logical(op string, depth int, operand func(int) (Expr, error)) (Expr, error) {
+	first, err := operand(depth)
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expr{first}
+	for p.keyword(op) {
+		e, err := operand(depth)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return &Logical{Op: op, Exprs: exprs}, nil
+}
+
+func (p *filterParser) not(depth int) (Expr, error) {
+	if depth > maxFilterDepth {
+		return nil, ErrInvalidFilter.Newf("filter is nested too deeply (at most %d levels)", maxFilterDepth)
+	}
+	if p.keyword("NOT") {
+		e, err := p.not(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: e}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		e, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, ErrInvalidFilter.Newf("expected \")\" at position %d", t.pos)
+		}
+		return e, nil
+	}
+	return p.comparison()
+}
+
+func (p *filterParser) comparison() (Expr, error) {
+	field := p.next()
+	if field.kind != tokWord || isFilterKeyword(field.text) {
+		return nil, ErrInvalidFilter.Newf("expected a field name at position %d, got %q", field.pos, field.text)
+	}
+	p.terms++
+	if p.terms > maxFilterTerms {
+		return nil, ErrInvalidFilter.Newf("too many filter conditions (at most %d)", maxFilterTerms)
+	}
+
+	switch {
+	case p.keyword("IS"):
+		op := OpNull
+		if p.keyword("NOT") {
+			op = OpNotNull
+		}
+		if !p.keyword("NULL") {
+			return nil, ErrInvalidFilter.Newf("expected NULL at position %d", p.peek().pos)
+		}
+		return &Compare{Field: field.text, Op: op}, nil
+	case p.keyword("IN"):
+		return p.in(field.text)
+	case p.keyword("NOT"):
+		if !p.keyword("IN") {
+			return nil, ErrInvalidFilter.Newf("expected IN at position %d", p.peek().pos)
+		}
+		c, err := p.in(field.text)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: c}, nil
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, ErrInvalidFilter.Newf("expected an operator after %q at position %d", field.text, opTok.pos)
+	}
+	val := p.next()
+	if val.kind != tokWord && val.kind != tokString {
+		return nil, ErrInvalidFilter.Newf("expected a value at position %d", val.pos)
+	}
+	op := symbolOp(opTok.text)
+	if val.kind == tokWord && strings.EqualFold(val.text, "NULL") {
+		switch op {
+		case OpEq:
+			return &Compare{Field: field.text, Op: OpNull}, nil
+		case OpNe:
+			return &Compare{Field: field.text, Op: OpNotNull}, nil
+		}
+	}
+	return &Compare{Field: field.text, Op: op, Values: []string{val.text}}, nil
+}
+
+func (p *filterParser) in(field string) (Expr, error) {
+	if t := p.next(); t.kind != tokLParen {
+		return nil, ErrInvalidFilter.Newf("expected \"(\" after IN at position %d", t.pos)
+	}
+	var vals []string
+	for {
+		t := p.next()
+		if t.kind != tokWord && t.kind != tokString {
+			return nil, ErrInvalidFilter.Newf("expected a value at position %d", t.pos)
+		}
+		vals = append(vals, t.text)
+		t = p.next()
+		if t.kind == tokRParen {
+			return &Compare{Field: field, Op: OpIn, Values: vals}, nil
+		}
+		if t.kind != tokComma {
+			return nil, ErrInvalidFilter.Newf("expected \",\" or \")\" at position %d", t.pos)
+		}
+	}
+}
+
+func symbolOp(sym string) Op {
+	switch sym {
+	case "<>", "!=":
+		return OpNe
+	}
+	for op, s := range opSymbols {
+		if s == sym {
+			return op
+		}
+	}
+	return OpEq
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/yoockh/go-api-utils/pkg/pagination"
+)
+
+// Repository is the generic CRUD interface implemented by SQL and Memory
+// Services depend on it so tests can swap the database for NewMemory.
+type Repository[T any, ID comparable] interface {
+	Find(ctx context.Context, id ID) (T, error)
+	List(ctx context.Context, q Query) ([]T, int64, error)
+	Create(ctx context.Context, v *T) error
+	Update(ctx context.Context, v *T) error
+	Delete(ctx context.Context, id ID) error
+}
+
+// Query selects, orders and pages the rows returned by List
+type Query struct {
+	// Filters are column = value conditions joined with AND; a nil value matches NULL
+	Filters map[string]interface{}
+	// Filter is a parsed filter expression over model columns (see ParseFilterQuery and
+	// FilterSpec.Resolve), joined to Filters with AND
+	Filter Expr
+	// Search is free text (usually ?q=) matched against the columns given to Searchable
+	Search string
+	// Sort lists columns to order by, "-" prefixed for descending (default: id ascending)
+	Sort []string
+	// Page starts at 1; PerPage is normalized by pagination.New (default 10, at most 1000)
+	Page    int
+	PerPage int
+}
+
+// DBTX is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ErrUnknownColumn is returned for filters or sort keys that are not columns of the model
+var ErrUnknownColumn = errors.New("repository: unknown column")
+
+// filterColumns resolves the fields of q.Filter against the model's columns
+func (q Query) filterColumns(m *model) (Expr, error) {
+	if q.Filter == nil {
+		return nil, nil
+	}
+	return mapFilter(q.Filter, func(field string) (string, error) {
+		if _, ok := m.index[field]; !ok {
+			return "", fmt.Errorf("%w %q", ErrUnknownColumn, field)
+		}
+		return field, nil
+	})
+}
+
+// searchColumns checks that the columns of a Search exist on the model
+func searchColumns(m *model, s Search) error {
+	if len(s.Columns) == 0 {
+		return errors.New("repository: Searchable needs at least one column")
+	}
+	for _, col := range s.Columns {
+		if _, ok := m.index[col]; !ok {
+			return fmt.Errorf("%w %q", ErrUnknownColumn, col)
+		}
+	}
+	return nil
+}
+
+// limitOffset normalizes paging the same way for every implementation
+func (q Query) limitOffset() (limit, offset int) {
+	p := pagination.New(q.Page, q.PerPage)
+	return p.Limit(), p.Offset()
+}
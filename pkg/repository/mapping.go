@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// model describes how a struct maps to table columns
+type model struct {
+	columns []string         // all mapped columns, in field order
+	index   map[string][]int // column -> field index path
+	id      []int            // index path of the "id" column
+}
+
+var models sync.Map // reflect.Type -> *model
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// modelOf maps the exported fields of struct type t to columns using the same names as
+// BuildPartialUpdateQuery: `db` tag, gorm `column:`, json name, then the snake_case field name.
+// Struct and slice fields that are not scalar database values (relations) are skipped.
+func modelOf(t reflect.Type) (*model, error) {
+	if m, ok := models.Load(t); ok {
+		return m.(*model), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("repository: %s is not a struct", t)
+	}
+	m := &model{index: map[string][]int{}}
+	collectColumns(m, t, nil)
+	if m.id == nil {
+		return nil, fmt.Errorf("repository: %s has no id column", t)
+	}
+	models.Store(t, m)
+	return m, nil
+}
+
+// IDOf returns the value of the id column of v, converted to ID
+func IDOf[T any, ID comparable](v T) (ID, error) {
+	var id ID
+	m, err := modelOf(reflect.TypeOf(v))
+	if err != nil {
+		return id, err
+	}
+	fv, target := reflect.ValueOf(v).FieldByIndex(m.id), reflect.ValueOf(&id).Elem()
+	if !fv.CanConvert(target.Type()) {
+		return id, fmt.Errorf("repository: id of %T is %s, not %s", v, fv.Type(), target.Type())
+	}
+	target.Set(fv.Convert(target.Type()))
+	return id, nil
+}
+
+// SetID sets the id column of v, e.g. to the id taken from the URL of a PUT request
+func SetID[T any, ID comparable](v *T, id ID) error {
+	m, err := modelOf(reflect.TypeOf(v).Elem())
+	if err != nil {
+		return err
+	}
+	fv, idv := reflect.ValueOf(v).Elem().FieldByIndex(m.id), reflect.ValueOf(id)
+	if !idv.CanConvert(fv.Type()) {
+		return fmt.Errorf("repository: id of %T is %s, not %s", *v, fv.Type(), idv.Type())
+	}
+	fv.Set(idv.Convert(fv.Type()))
+	return nil
+}
+
+func collectColumns(m *model, t reflect.Type, parent []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		index := append(append([]int(nil), parent...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("db") == "" {
+			collectColumns(m, f.Type, index) // embedded structs (e.g. gorm.Model) are flattened
+			continue
+		}
+		if !f.IsExported() || f.Tag.Get("db") == "-" || strings.Contains(f.Tag.Get("gorm"), "-:all") || !isColumnType(f.Type) {
+			continue
+		}
+		col := fieldColumn(f)
+		if _, dup := m.index[col]; dup {
+			continue
+		}
+		m.columns = append(m.columns, col)
+		m.index[col] = index
+		if col == "id" {
+			m.id = index
+		}
+	}
+}
+
+func fieldColumn(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		name = snakeCase(f.Name)
+	}
+	return columnName(f, name)
+}
+
+// isColumnType reports whether values of t can be stored in a single column
+func isColumnType(t reflect.Type) bool {
+	if t.Implements(valuerType) || reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return t == timeType
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8 // []byte
+	case reflect.Map, reflect.Func, reflect.Chan, reflect.Interface, reflect.Array:
+		return false
+	}
+	return true
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// ID -> id, UserID -> user_id, HTTPCode -> http_code
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
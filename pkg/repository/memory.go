@@ -0,0 +1,410 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// Memory is a map-backed Repository for unit tests
+// Filtering, sorting, paging and errors follow SQL: unknown columns return ErrUnknownColumn,
+// missing ids errs.ErrNotFound and duplicate ids errs.ErrConflict. Stored values are copies,
+// so callers mutating their structs don't change the store. Nested pointers, slices and
+// maps are shared, like rows scanned twice would not be.
+type Memory[T any, ID comparable] struct {
+	mu     sync.RWMutex
+	rows   map[ID]T
+	model  *model
+	nextID int64
+	search *Search
+}
+
+// NewMemory creates an empty in-memory Repository; it panics if T has no id column
+// Zero integer ids are assigned sequentially on Create and zero string ids get a random UUID.
+// Example:
+//
+//	products := repository.NewMemory[Product, int64]()
+//	svc := NewProductService(products) // accepts repository.Repository[Product, int64]
+func NewMemory[T any, ID comparable](seed ...T) *Memory[T, ID] {
+	m, err := modelOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		panic(err)
+	}
+	r := &Memory[T, ID]{rows: map[ID]T{}, model: m}
+	for _, v := range seed {
+		if err := r.Create(context.Background(), &v); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Searchable sets the columns matched by Query.Search and returns r; it panics on unknown columns
+// Both search modes match rows containing every word of the search in one of the columns.
+func (r *Memory[T, ID]) Searchable(s Search) *Memory[T, ID] {
+	if err := searchColumns(r.model, s); err != nil {
+		panic(err)
+	}
+	r.search = &s
+	return r
+}
+
+// Find returns the row with the given id
+func (r *Memory[T, ID]) Find(ctx context.Context, id ID) (T, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.rows[id]
+	if !ok {
+		return v, fmt.Errorf("%T %v: %w", v, id, errs.ErrNotFound)
+	}
+	return v, nil
+}
+
+// List returns one page of rows matching q and the total number of matches
+func (r *Memory[T, ID]) List(ctx context.Context, q Query) ([]T, int64, error) {
+	for col := range q.Filters {
+		if _, ok := r.model.index[col]; !ok {
+			return nil, 0, fmt.Errorf("%w %q", ErrUnknownColumn, col)
+		}
+	}
+	filter, err := q.filterColumns(r.model)
+	if err != nil {
+		return nil, 0, err
+	}
+	if q.Search != "" && r.search == nil {
+		var zero T
+		return nil, 0, fmt.Errorf("repository: %T is not searchable", zero)
+	}
+	sortKeys := q.Sort
+	if len(sortKeys) == 0 {
+		sortKeys = []string{"id"}
+	}
+	for _, key := range sortKeys {
+		if _, ok := r.model.index[strings.TrimPrefix(key, "-")]; !ok {
+			return nil, 0, fmt.Errorf("%w %q", ErrUnknownColumn, strings.TrimPrefix(key, "-"))
+		}
+	}
+
+	r.mu.RLock()
+	matches := make([]T, 0, len(r.rows))
+	for _, v := range r.rows {
+		if r.matches(v, q.Filters) && (filter == nil || r.eval(reflect.ValueOf(v), filter) == sqlTrue) && r.searchMatches(v, q.Search) {
+			matches = append(matches, v)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortStableFunc(matches, func(a, b T) int {
+		av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+		for _, key := range sortKeys {
+			col, desc := strings.CutPrefix(key, "-")
+			idx := r.model.index[col]
+			c := compareValues(av.FieldByIndex(idx), bv.FieldByIndex(idx))
+			if desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	total := int64(len(matches))
+	limit, offset := q.limitOffset()
+	if offset >= len(matches) {
+		return []T{}, total, nil
+	}
+	return matches[offset:min(offset+limit, len(matches))], total, nil
+}
+
+// Create stores a copy of v, assigning an id when it is zero
+func (r *Memory[T, ID]) Create(ctx context.Context, v *T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	idField := reflect.ValueOf(v).Elem().FieldByIndex(r.model.id)
+	if idField.IsZero() {
+		switch idField.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			r.nextID++
+			idField.SetInt(r.nextID)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			r.nextID++
+			idField.SetUint(uint64(r.nextID))
+		case reflect.String:
+			idField.SetString(newUUID())
+		default:
+			return fmt.Errorf("failed to create %T: id is required", *v)
+		}
+	} else if idField.CanInt() && idField.Int() > r.nextID {
+		r.nextID = idField.Int() // keep generated ids after explicit ones, like a sequence
+	}
+
+	id := r.idOf(*v)
+	if _, exists := r.rows[id]; exists {
+		return fmt.Errorf("%T %v: %w", *v, id, errs.ErrConflict)
+	}
+	r.rows[id] = *v
+	return nil
+}
+
+// Update replaces the row with v's id
+func (r *Memory[T, ID]) Update(ctx context.Context, v *T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := r.idOf(*v)
+	if _, ok := r.rows[id]; !ok {
+		return fmt.Errorf("%T %v: %w", *v, id, errs.ErrNotFound)
+	}
+	r.rows[id] = *v
+	return nil
+}
+
+// Delete removes the row with the given id
+func (r *Memory[T, ID]) Delete(ctx context.Context, id ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rows[id]; !ok {
+		var zero T
+		return fmt.Errorf("%T %v: %w", zero, id, errs.ErrNotFound)
+	}
+	delete(r.rows, id)
+	return nil
+}
+
+// Len returns the number of stored rows
+func (r *Memory[T, ID]) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.rows)
+}
+
+func (r *Memory[T, ID]) idOf(v T) ID {
+	var id ID
+	fv := reflect.ValueOf(v).FieldByIndex(r.model.id)
+	reflect.ValueOf(&id).Elem().Set(fv.Convert(reflect.TypeOf(id)))
+	return id
+}
+
+func (r *Memory[T, ID]) matches(v T, filters map[string]interface{}) bool {
+	rv := reflect.ValueOf(v)
+	for col, want := range filters {
+		fv := rv.FieldByIndex(r.model.index[col])
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		isNull := fv.Kind() == reflect.Pointer && fv.IsNil()
+		if want == nil || isNull {
+			if want != nil || !isNull {
+				return false
+			}
+			continue
+		}
+		// Compare textual forms, like Postgres casting a text parameter to the column type
+		if fmt.Sprint(fv.Interface()) != fmt.Sprint(reflect.Indirect(reflect.ValueOf(want)).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Memory[T, ID]) searchMatches(v T, q string) bool {
+	if strings.TrimSpace(q) == "" {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	values := make([]string, 0, len(r.search.Columns))
+	for _, col := range r.search.Columns {
+		fv := rv.FieldByIndex(r.model.index[col])
+		for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.Pointer {
+			values = append(values, fmt.Sprint(fv.Interface()))
+		}
+	}
+	return r.search.matches(q, values)
+}
+
+// sqlBool is a three-valued SQL truth value: comparisons with NULL are unknown
+type sqlBool int8
+
+const (
+	sqlFalse sqlBool = iota
+	sqlTrue
+	sqlUnknown
+)
+
+// eval evaluates a resolved filter against a row with Postgres semantics
+func (r *Memory[T, ID]) eval(rv reflect.Value, e Expr) sqlBool {
+	switch e := e.(type) {
+	case *Logical:
+		result := sqlTrue
+		if e.Op == "OR" {
+			result = sqlFalse
+		}
+		for _, x := range e.Exprs {
+			switch v := r.eval(rv, x); {
+			case e.Op == "OR" && v == sqlTrue, e.Op == "AND" && v == sqlFalse:
+				return v
+			case v == sqlUnknown:
+				result = sqlUnknown
+			}
+		}
+		return result
+	case *Not:
+		switch r.eval(rv, e.Expr) {
+		case sqlTrue:
+			return sqlFalse
+		case sqlFalse:
+			return sqlTrue
+		}
+		return sqlUnknown
+	case *Compare:
+		fv := rv.FieldByIndex(r.model.index[e.Field])
+		for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		isNull := fv.Kind() == reflect.Pointer
+		switch {
+		case e.Op == OpNull:
+			return toSQLBool(isNull)
+		case e.Op == OpNotNull:
+			return toSQLBool(!isNull)
+		case isNull:
+			return sqlUnknown
+		}
+		switch e.Op {
+		case OpIn:
+			for _, v := range e.Values {
+				if compareLiteral(fv, v) == 0 {
+					return sqlTrue
+				}
+			}
+			return sqlFalse
+		case OpLike:
+			return toSQLBool(strings.Contains(strings.ToLower(fmt.Sprint(fv.Interface())), strings.ToLower(e.Values[0])))
+		}
+		c := compareLiteral(fv, e.Values[0])
+		switch e.Op {
+		case OpEq:
+			return toSQLBool(c == 0)
+		case OpNe:
+			return toSQLBool(c != 0)
+		case OpGt:
+			return toSQLBool(c > 0)
+		case OpGte:
+			return toSQLBool(c >= 0)
+		case OpLt:
+			return toSQLBool(c < 0)
+		case OpLte:
+			return toSQLBool(c <= 0)
+		}
+	}
+	return sqlFalse
+}
+
+func toSQLBool(b bool) sqlBool {
+	if b {
+		return sqlTrue
+	}
+	return sqlFalse
+}
+
+// compareLiteral orders a field value against a filter literal converted to the field's type,
+// like Postgres casting a text parameter; literals that don't convert compare as text
+func compareLiteral(fv reflect.Value, lit string) int {
+	switch {
+	case fv.Type() == timeType:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, lit); err == nil {
+				return fv.Interface().(time.Time).Compare(t)
+			}
+		}
+	case fv.CanInt():
+		if n, err := strconv.ParseInt(lit, 10, 64); err == nil {
+			return cmp.Compare(fv.Int(), n)
+		}
+		if f, err := strconv.ParseFloat(lit, 64); err == nil {
+			return cmp.Compare(float64(fv.Int()), f)
+		}
+	case fv.CanUint():
+		if n, err := strconv.ParseUint(lit, 10, 64); err == nil {
+			return cmp.Compare(fv.Uint(), n)
+		}
+	case fv.CanFloat():
+		if f, err := strconv.ParseFloat(lit, 64); err == nil {
+			return cmp.Compare(fv.Float(), f)
+		}
+	case fv.Kind() == reflect.Bool:
+		if b, err := strconv.ParseBool(lit); err == nil {
+			return cmp.Compare(boolInt(fv.Bool()), boolInt(b))
+		}
+	case fv.Kind() == reflect.String:
+		return cmp.Compare(fv.String(), lit)
+	}
+	return cmp.Compare(fmt.Sprint(fv.Interface()), lit)
+}
+
+// compareValues orders two field values; NULLs sort last, as in Postgres ascending order
+func compareValues(a, b reflect.Value) int {
+	for a.Kind() == reflect.Pointer || b.Kind() == reflect.Pointer {
+		switch {
+		case a.IsNil() && b.IsNil():
+			return 0
+		case a.IsNil():
+			return 1
+		case b.IsNil():
+			return -1
+		}
+		a, b = a.Elem(), b.Elem()
+	}
+	if a.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time))
+	}
+	switch {
+	case a.CanInt():
+		return cmp.Compare(a.Int(), b.Int())
+	case a.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint())
+	case a.CanFloat():
+		return cmp.Compare(a.Float(), b.Float())
+	case a.Kind() == reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	case a.Kind() == reflect.Bool:
+		return cmp.Compare(boolInt(a.Bool()), boolInt(b.Bool()))
+	}
+	return cmp.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+var (
+	_ Repository[struct{ ID int }, int] = (*Memory[struct{ ID int }, int])(nil)
+	_ Repository[struct{ ID int }, int] = (*SQL[struct{ ID int }, int])(nil)
+)
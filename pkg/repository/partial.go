@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var ErrNoChanges = errors.New("repository: no columns to update")
+
+// BuildPartialUpdateQuery generates an UPDATE for the changed JSON fields of v
+// fields are JSON names, as returned by request.ParsePatch. Columns come from the `db` tag,
+// then gorm's `column:` tag, then the JSON name; "id" is never updated. Append the id from
+// the request path to args, not the patched struct's.
+// Example:
+//
+//	fields, err := request.ParsePatch(r, &product, "price", "stock") // ["price", "stock"]
+//	query, args, err := repository.BuildPartialUpdateQuery("products", &product, fields)
+//	// query: UPDATE products SET price = $1, stock = $2 WHERE id = $3
+//	result, err := db.ExecContext(ctx, query, append(args, id)...)
+func BuildPartialUpdateQuery(table string, v interface{}, fields []string) (string, []interface{}, error) {
+	columns, args, err := PartialColumns(v, fields)
+	if err != nil {
+		return "", nil, err
+	}
+	return BuildUpdateQuery(table, columns), args, nil
+}
+
+// PartialColumns maps changed JSON fields of v to column names and their current values
+// Use the columns with GORM to update only what the patch touched:
+//
+//	cols, _, err := repository.PartialColumns(&product, fields)
+//	db.Model(&product).Select(cols).Updates(&product)
+func PartialColumns(v interface{}, fields []string) ([]string, []interface{}, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("repository: %T is not a struct", v)
+	}
+
+	var columns []string
+	var args []interface{}
+	for _, name := range fields {
+		fv, f, ok := jsonField(rv, name)
+		if !ok {
+			return nil, nil, fmt.Errorf("repository: unknown field %q", name)
+		}
+		col := columnName(f, name)
+		if col == "id" {
+			continue
+		}
+		columns = append(columns, col)
+		args = append(args, fv.Interface())
+	}
+	if len(columns) == 0 {
+		return nil, nil, ErrNoChanges
+	}
+	return columns, args, nil
+}
+
+func columnName(f reflect.StructField, jsonName string) string {
+	if col, _, _ := strings.Cut(f.Tag.Get("db"), ","); col != "" && col != "-" {
+		return col
+	}
+	for _, part := range strings.Split(f.Tag.Get("gorm"), ";") {
+		if col, ok := strings.CutPrefix(strings.TrimSpace(part), "column:"); ok {
+			return col
+		}
+	}
+	return jsonName
+}
+
+// jsonField finds the field encoded under name, following embedded structs
+func jsonField(v reflect.Value, name string) (reflect.Value, reflect.StructField, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			if ev := reflect.Indirect(v.Field(i)); ev.Kind() == reflect.Struct {
+				if fv, sf, ok := jsonField(ev, name); ok {
+					return fv, sf, true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if tag == name {
+			return v.Field(i), f, true
+		}
+	}
+	return reflect.Value{}, reflect.StructField{}, false
+}
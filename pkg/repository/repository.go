@@ -12,7 +12,12 @@ import (
 //
 //	query := BuildInsertQuery("products", []string{"name", "price", "stock"})
 //	// Returns: INSERT INTO products (name, price, stock) VALUES ($1, $2, $3) RETURNING id
+//	BuildInsertQuery("tickets", nil)
+//	// Returns: INSERT INTO tickets DEFAULT VALUES RETURNING id
 func BuildInsertQuery(table string, columns []string) string {
+	if len(columns) == 0 {
+		return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING id", table)
+	}
 	placeholders := make([]string, len(columns))
 	for i := range columns {
 		placeholders[i] = fmt.Sprintf("$%d", i+1)
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"net/url"
+	"strings"
+)
+
+// maxSearchTerms caps the words of a search; extra words are ignored
+const maxSearchTerms = 8
+
+// Search turns a free-text ?q= parameter into a condition over the searchable columns of a resource
+// By default every word must appear (case-insensitive) in at least one column, using ILIKE.
+// With FullText the columns are matched with to_tsvector @@ websearch_to_tsquery, which
+// understands "quoted phrases", OR and -excluded words; create an expression index on
+// to_tsvector('<language>', concat_ws(' ', columns...)) for large tables.
+// Example:
+//
+//	var productSearch = repository.Search{Columns: []string{"p.name", "p.description", "c.name"}}
+//
+//	cond := productSearch.FromQuery(r.URL.Query()) // ?q=red pen
+//	// (p.name ILIKE $1 OR p.description ILIKE $2 OR c.name ILIKE $3) AND (p.name ILIKE $4 OR ...)
+//	query := "SELECT ... FROM products p JOIN categories c ON c.id = p.category_id"
+//	if !cond.Empty() {
+//		query += " WHERE " + cond.Numbered(0)
+//	}
+type Search struct {
+	Columns  []string
+	FullText bool
+	// Language is the text search configuration for FullText (default "simple")
+	Language string
+}
+
+// Condition returns the condition matching q; a blank q gives an empty condition
+func (s Search) Condition(q string) Condition {
+	q = strings.TrimSpace(q)
+	if q == "" || len(s.Columns) == 0 {
+		return Condition{}
+	}
+	if s.FullText {
+		lang := s.Language
+		if lang == "" {
+			lang = "simple"
+		}
+		return Condition{
+			Query: "to_tsvector(?::regconfig, concat_ws(' ', " + strings.Join(s.Columns, ", ") + ")) @@ websearch_to_tsquery(?::regconfig, ?)",
+			Args:  []interface{}{lang, lang, q},
+		}
+	}
+
+	var c Condition
+	var groups []string
+	for _, word := range searchTerms(q) {
+		ors := make([]string, len(s.Columns))
+		for i, col := range s.Columns {
+			ors[i] = col + " ILIKE ?"
+			c.Args = append(c.Args, "%"+escapeLike(word)+"%")
+		}
+		groups = append(groups, "("+strings.Join(ors, " OR ")+")")
+	}
+	c.Query = strings.Join(groups, " AND ")
+	return c
+}
+
+// FromQuery returns the condition for the "q" query parameter
+func (s Search) FromQuery(values url.Values) Condition {
+	return s.Condition(values.Get("q"))
+}
+
+// matches reports whether every search word appears in one of the values; the in-memory
+// equivalent of both modes
+func (s Search) matches(q string, values []string) bool {
+	for _, word := range searchTerms(q) {
+		word = strings.ToLower(word)
+		found := false
+		for _, v := range values {
+			if strings.Contains(strings.ToLower(v), word) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func searchTerms(q string) []string {
+	words := strings.Fields(q)
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	return words
+}
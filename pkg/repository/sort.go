@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// ErrInvalidSort is returned by SortSpec.Parse for unknown keys or directions (400 invalid_sort)
+var ErrInvalidSort = errs.Define("invalid_sort", http.StatusBadRequest, "invalid sort parameter").
+	Describe("The sort parameter references a key that is not sortable or an unknown direction.")
+
+// SortSpec maps the public sort keys of a resource to the columns they order by
+// Only keys present in the map are accepted, so user input never reaches the ORDER BY clause;
+// columns are written by the developer and may be qualified or expressions.
+// Example:
+//
+//	var productSort = repository.SortSpec{
+//		"created": "p.created_at",
+//		"price":   "p.price",
+//		"name":    "lower(p.name)",
+//	}
+//
+//	order, err := productSort.Parse(r.URL.Query().Get("sort"), "-created") // "-price,name" or "price:desc,name"
+//	if err != nil {
+//		return err // 400 invalid_sort
+//	}
+//	query := "SELECT ... FROM products p ORDER BY " + order.SQL()
+type SortSpec map[string]string
+
+// SortField is one validated ORDER BY term
+type SortField struct {
+	Key    string
+	Column string
+	Desc   bool
+}
+
+// Order is a validated list of sort terms produced by SortSpec.Parse
+type Order []SortField
+
+// Parse validates a comma-separated sort parameter and returns the order it describes
+// Each term is a key optionally prefixed with "-" (descending) or "+", or suffixed with
+// ":asc" / ":desc". An empty param falls back to def, which uses the same syntax.
+// Repeated keys keep their first occurrence.
+func (s SortSpec) Parse(param, def string) (Order, error) {
+	param = strings.TrimSpace(param)
+	if param == "" {
+		param = def
+	}
+	var order Order
+	seen := make(map[string]bool)
+	for _, term := range strings.Split(param, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		key, desc, err := parseSortTerm(term)
+		if err != nil {
+			return nil, err
+		}
+		col, ok := s[key]
+		if !ok {
+			return nil, ErrInvalidSort.Newf("cannot sort by %q; allowed: %s", key, strings.Join(s.Keys(), ", "))
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		order = append(order, SortField{Key: key, Column: col, Desc: desc})
+	}
+	return order, nil
+}
+
+// Keys returns the allowed sort keys in alphabetical order
+func (s SortSpec) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SQL renders the order as an ORDER BY expression list, e.g. "p.created_at DESC, p.name"
+// It returns "" for an empty order.
+func (o Order) SQL() string {
+	parts := make([]string, len(o))
+	for i, f := range o {
+		parts[i] = f.Column
+		if f.Desc {
+			parts[i] += " DESC"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Sort returns the order in Query.Sort form ("-" prefixed columns) for the generic repository
+// The columns must then be plain columns of the model.
+// Example:
+//
+//	order, err := repository.SortSpec{"created": "created_at", "price": "price"}.Parse(sortParam, "-created")
+//	list, total, err := products.List(ctx, repository.Query{Sort: order.Sort(), Page: p.Page, PerPage: p.PerPage})
+func (o Order) Sort() []string {
+	out := make([]string, len(o))
+	for i, f := range o {
+		out[i] = f.Column
+		if f.Desc {
+			out[i] = "-" + f.Column
+		}
+	}
+	return out
+}
+
+// String renders the order back in public "-key" form, e.g. for pagination links
+func (o Order) String() string {
+	parts := make([]string, len(o))
+	for i, f := range o {
+		parts[i] = f.Key
+		if f.Desc {
+			parts[i] = "-" + f.Key
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseSortTerm(term string) (key string, desc bool, err error) {
+	switch {
+	case strings.HasPrefix(term, "-"):
+		return strings.TrimSpace(term[1:]), true, nil
+	case strings.HasPrefix(term, "+"):
+		return strings.TrimSpace(term[1:]), false, nil
+	}
+	key, dir, ok := strings.Cut(term, ":")
+	if !ok {
+		return term, false, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(dir)) {
+	case "asc":
+		return strings.TrimSpace(key), false, nil
+	case "desc":
+		return strings.TrimSpace(key), true, nil
+	}
+	return "", false, ErrInvalidSort.Newf("unknown sort direction %q", dir)
+}
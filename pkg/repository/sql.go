@@ -0,0 +1,277 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// SQL is a Repository backed by a PostgreSQL table through database/sql
+type SQL[T any, ID comparable] struct {
+	db     DBTX
+	table  string
+	model  *model
+	search *Search
+	// estimateAbove > 0 makes List return planner estimates for larger totals
+	estimateAbove int64
+}
+
+// NewSQL creates a Repository for table; columns are derived from T (see BuildPartialUpdateQuery
+// for naming) and the column named "id" is the primary key. db may be a *sql.DB or *sql.Tx.
+// Missing rows return errs.ErrNotFound (404) and unique violations keep their 409 mapping.
+// It panics if T is not a struct with an id column.
+// Example:
+//
+//	type Product struct {
+//		ID        int64     `json:"id"`
+//		Name      string    `json:"name"`
+//		Price     float64   `json:"price"`
+//		CreatedAt time.Time `json:"created_at"`
+//	}
+//	products := repository.NewSQL[Product, int64](db, "products")
+//	list, total, err := products.List(ctx, repository.Query{Sort: []string{"-created_at"}, Page: 1, PerPage: 20})
+func NewSQL[T any, ID comparable](db DBTX, table string) *SQL[T, ID] {
+	m, err := modelOf(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		panic(err)
+	}
+	return &SQL[T, ID]{db: db, table: table, model: m}
+}
+
+// WithTx returns a copy of the repository running its queries on tx
+func (r *SQL[T, ID]) WithTx(tx DBTX) *SQL[T, ID] {
+	cp := *r
+	cp.db = tx
+	return &cp
+}
+
+// Searchable sets the columns matched by Query.Search and returns r; call it when
+// constructing the repository. It panics if a column is not a column of T.
+// Example:
+//
+//	products := repository.NewSQL[Product, int64](db, "products").
+//		Searchable(repository.Search{Columns: []string{"name", "description"}})
+//	list, total, err := products.List(ctx, repository.Query{Search: r.URL.Query().Get("q")})
+func (r *SQL[T, ID]) Searchable(s Search) *SQL[T, ID] {
+	if err := searchColumns(r.model, s); err != nil {
+		panic(err)
+	}
+	r.search = &s
+	return r
+}
+
+// EstimateCounts makes List report the planner's estimate instead of an exact COUNT(*) when
+// it expects more than threshold matches (see CountRows) and returns r
+// Example:
+//
+//	events := repository.NewSQL[Event, int64](db, "events").EstimateCounts(100000)
+func (r *SQL[T, ID]) EstimateCounts(threshold int64) *SQL[T, ID] {
+	r.estimateAbove = threshold
+	return r
+}
+
+// Find returns the row with the given id
+func (r *SQL[T, ID]) Find(ctx context.Context, id ID) (T, error) {
+	var v T
+	query := BuildSelectQuery(r.table, r.model.columns, "id = $1")
+	err := r.db.QueryRowContext(ctx, query, id).Scan(r.fields(&v, r.model.columns)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return v, fmt.Errorf("%s %v: %w", r.table, id, errs.ErrNotFound)
+	}
+	if err != nil {
+		return v, fmt.Errorf("failed to find %s %v: %w", r.table, id, err)
+	}
+	return v, nil
+}
+
+// List returns one page of rows matching q and the total number of matches
+func (r *SQL[T, ID]) List(ctx context.Context, q Query) ([]T, int64, error) {
+	where, args, err := r.where(q.Filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter, err := q.filterColumns(r.model)
+	if err != nil {
+		return nil, 0, err
+	}
+	var extra Condition
+	if filter != nil {
+		extra = compileFilter(filter)
+	}
+	if q.Search != "" {
+		if r.search == nil {
+			return nil, 0, fmt.Errorf("repository: %s is not searchable", r.table)
+		}
+		extra = extra.And(r.search.Condition(q.Search))
+	}
+	if !extra.Empty() {
+		if where != "" {
+			where += " AND "
+		}
+		where += extra.Numbered(len(args))
+		args = append(args, extra.Args...)
+	}
+	order, err := r.orderBy(q.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.count(ctx, where, args)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count %s: %w", r.table, err)
+	}
+
+	limit, offset := q.limitOffset()
+	query := BuildSelectQuery(r.table, r.model.columns, where) +
+		fmt.Sprintf(" ORDER BY %s LIMIT %d OFFSET %d", order, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list %s: %w", r.table, err)
+	}
+	defer rows.Close()
+	items, err := ScanRows(rows, func(rows *sql.Rows) (T, error) {
+		var v T
+		err := rows.Scan(r.fields(&v, r.model.columns)...)
+		return v, err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan %s: %w", r.table, err)
+	}
+	if items == nil {
+		items = []T{}
+	}
+	return items, total, nil
+}
+
+// Create inserts v and sets its id from RETURNING id
+// A zero id is left to the database default (serial, identity or gen_random_uuid()).
+func (r *SQL[T, ID]) Create(ctx context.Context, v *T) error {
+	columns := r.model.columns
+	if reflect.ValueOf(v).Elem().FieldByIndex(r.model.id).IsZero() {
+		columns = without(columns, "id")
+	}
+	query := BuildInsertQuery(r.table, columns)
+	if err := r.db.QueryRowContext(ctx, query, r.values(v, columns)...).Scan(r.fields(v, []string{"id"})...); err != nil {
+		return fmt.Errorf("failed to create %s: %w", r.table, err)
+	}
+	return nil
+}
+
+// Update writes every column of v to the row with v's id
+func (r *SQL[T, ID]) Update(ctx context.Context, v *T) error {
+	columns := without(r.model.columns, "id")
+	args := append(r.values(v, columns), r.values(v, []string{"id"})...)
+	result, err := r.db.ExecContext(ctx, BuildUpdateQuery(r.table, columns), args...)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", r.table, err)
+	}
+	if err := CheckRowsAffected(result); err != nil {
+		return r.notFound(err, r.values(v, []string{"id"})[0])
+	}
+	return nil
+}
+
+// Delete removes the row with the given id
+func (r *SQL[T, ID]) Delete(ctx context.Context, id ID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM "+r.table+" WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s %v: %w", r.table, id, err)
+	}
+	if err := CheckRowsAffected(result); err != nil {
+		return r.notFound(err, id)
+	}
+	return nil
+}
+
+func (r *SQL[T, ID]) notFound(err error, id interface{}) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s %v: %w", r.table, id, errs.ErrNotFound)
+	}
+	return err
+}
+
+func (r *SQL[T, ID]) count(ctx context.Context, where string, args []interface{}) (int64, error) {
+	if r.estimateAbove > 0 {
+		total, _, err := CountRows(ctx, r.db, BuildSelectQuery(r.table, []string{"1"}, where), r.estimateAbove, args...)
+		return total, err
+	}
+	var total int64
+	countQuery := BuildSelectQuery(r.table, []string{"COUNT(*)"}, where)
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	return total, err
+}
+
+// where builds "col = $1 AND col2 IS NULL" from filters, in column order
+func (r *SQL[T, ID]) where(filters map[string]interface{}) (string, []interface{}, error) {
+	for col := range filters {
+		if _, ok := r.model.index[col]; !ok {
+			return "", nil, fmt.Errorf("%w %q", ErrUnknownColumn, col)
+		}
+	}
+	var conds []string
+	var args []interface{}
+	for _, col := range r.model.columns {
+		v, ok := filters[col]
+		switch {
+		case !ok:
+		case v == nil:
+			conds = append(conds, col+" IS NULL")
+		default:
+			args = append(args, v)
+			conds = append(conds, fmt.Sprintf("%s = $%d", col, len(args)))
+		}
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// orderBy validates sort keys against the model's columns, so user input never reaches SQL
+func (r *SQL[T, ID]) orderBy(sort []string) (string, error) {
+	if len(sort) == 0 {
+		return "id", nil
+	}
+	parts := make([]string, 0, len(sort))
+	for _, key := range sort {
+		col, desc := strings.CutPrefix(key, "-")
+		if _, ok := r.model.index[col]; !ok {
+			return "", fmt.Errorf("%w %q", ErrUnknownColumn, col)
+		}
+		if desc {
+			col += " DESC"
+		}
+		parts = append(parts, col)
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+func (r *SQL[T, ID]) fields(v *T, columns []string) []interface{} {
+	rv := reflect.ValueOf(v).Elem()
+	out := make([]interface{}, len(columns))
+	for i, col := range columns {
+		out[i] = rv.FieldByIndex(r.model.index[col]).Addr().Interface()
+	}
+	return out
+}
+
+func (r *SQL[T, ID]) values(v *T, columns []string) []interface{} {
+	rv := reflect.ValueOf(v).Elem()
+	out := make([]interface{}, len(columns))
+	for i, col := range columns {
+		out[i] = rv.FieldByIndex(r.model.index[col]).Interface()
+	}
+	return out
+}
+
+func without(columns []string, drop string) []string {
+	out := make([]string, 0, len(columns))
+	for _, c := range columns {
+		if c != drop {
+			out = append(out, c)
+		}
+	}
+	return out
+}
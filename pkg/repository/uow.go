@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/yoockh/go-api-utils/pkg/database"
+)
+
+// Beginner starts transactions; *sql.DB and *sql.Conn implement it
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// UnitOfWork groups repositories over one transaction so cross-repository changes commit or
+// roll back together. Applications wrap it in a type with one accessor per repository,
+// built with Use, and services depend on that type instead of *sql.Tx.
+// Example:
+//
+//	type Repos struct { // NewSQL in production, NewMemory in tests
+//		Products repository.Repository[Product, int64]
+//		Orders   repository.Repository[Order, int64]
+//	}
+//
+//	type Unit struct {
+//		*repository.UnitOfWork
+//		repos Repos
+//	}
+//
+//	func (u Unit) Products() repository.Repository[Product, int64] { return repository.Use(u.UnitOfWork, u.repos.Products) }
+//	func (u Unit) Orders() repository.Repository[Order, int64]     { return repository.Use(u.UnitOfWork, u.repos.Orders) }
+//
+//	uow, err := repository.Begin(ctx, db, nil)
+//	if err != nil {
+//		return err
+//	}
+//	defer uow.Rollback() // no-op after Commit
+//	u := Unit{uow, repos}
+//	if err := u.Orders().Create(ctx, &order); err != nil {
+//		return err
+//	}
+//	if err := u.Products().Update(ctx, &product); err != nil {
+//		return err
+//	}
+//	return u.Commit()
+type UnitOfWork struct {
+	tx *sql.Tx
+
+	mu        sync.Mutex
+	done      bool
+	snapshots map[any]bool // Memory repositories already snapshotted
+	restores  []func()
+}
+
+// Begin starts a unit of work on db
+// A nil db gives a unit without a database for unit tests: Use returns Memory repositories
+// unchanged and Rollback restores the rows they had when first used in the unit.
+func Begin(ctx context.Context, db Beginner, opts *sql.TxOptions) (*UnitOfWork, error) {
+	u := &UnitOfWork{snapshots: map[any]bool{}}
+	if db == nil {
+		return u, nil
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin unit of work: %w", err)
+	}
+	u.tx = tx
+	return u, nil
+}
+
+// InUnitOfWork runs fn in a unit of work, committing when it returns nil and rolling back
+// when it returns an error or panics. Serialization failures and deadlocks run fn again in
+// a new unit (see database.TxConfig.Retry); opts also set the isolation level.
+// Example:
+//
+//	err := repository.InUnitOfWork(ctx, db, func(uow *repository.UnitOfWork) error {
+//		u := Unit{uow, repos}
+//		if err := u.Orders().Create(ctx, &order); err != nil {
+//			return err
+//		}
+//		return u.Products().Update(ctx, &product)
+//	})
+func InUnitOfWork(ctx context.Context, db Beginner, fn func(u *UnitOfWork) error, opts ...database.TxOption) error {
+	cfg := database.NewTxConfig(opts...)
+	return cfg.Retry(ctx, func(ctx context.Context) error {
+		return runUnitOfWork(ctx, db, cfg.SQLOptions(), fn)
+	})
+}
+
+func runUnitOfWork(ctx context.Context, db Beginner, opts *sql.TxOptions, fn func(u *UnitOfWork) error) error {
+	u, err := Begin(ctx, db, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			u.Rollback()
+			panic(p)
+		}
+	}()
+	if err := fn(u); err != nil {
+		if rbErr := u.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return u.Commit()
+}
+
+// Tx returns the transaction for queries outside the repositories; nil without a database
+func (u *UnitOfWork) Tx() *sql.Tx {
+	return u.tx
+}
+
+// Commit commits the transaction; the unit can't be used afterwards
+func (u *UnitOfWork) Commit() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.done {
+		return sql.ErrTxDone
+	}
+	u.done = true
+	u.restores = nil
+	if u.tx == nil {
+		return nil
+	}
+	if err := u.tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit unit of work: %w", err)
+	}
+	return nil
+}
+
+// Rollback discards the changes made in the unit; it is a no-op after Commit, so it can be deferred
+func (u *UnitOfWork) Rollback() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.done {
+		return nil
+	}
+	u.done = true
+	for i := len(u.restores) - 1; i >= 0; i-- {
+		u.restores[i]()
+	}
+	u.restores = nil
+	if u.tx == nil {
+		return nil
+	}
+	if err := u.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("failed to roll back unit of work: %w", err)
+	}
+	return nil
+}
+
+// Use returns r bound to the unit: *SQL repositories run on its transaction and Memory
+// repositories are snapshotted so Rollback restores them. Other implementations are
+// returned unchanged.
+func Use[T any, ID comparable](u *UnitOfWork, r Repository[T, ID]) Repository[T, ID] {
+	switch r := r.(type) {
+	case *SQL[T, ID]:
+		if u.tx == nil {
+			return r
+		}
+		return r.WithTx(u.tx)
+	case *Memory[T, ID]:
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		if !u.done && !u.snapshots[r] {
+			u.snapshots[r] = true
+			u.restores = append(u.restores, r.snapshot())
+		}
+	}
+	return r
+}
+
+// snapshot copies the stored rows and returns a func restoring them
+func (r *Memory[T, ID]) snapshot() func() {
+	r.mu.RLock()
+	rows, nextID := maps.Clone(r.rows), r.nextID
+	r.mu.RUnlock()
+	return func() {
+		r.mu.Lock()
+		r.rows, r.nextID = rows, nextID
+		r.mu.Unlock()
+	}
+}
@@ -0,0 +1,119 @@
+package request
+
+import (
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MediaRange is one entry of an Accept header
+type MediaRange struct {
+	Type    string  // e.g. "application/json", "text/*" or "*/*"
+	Quality float64 // q-value in [0, 1]
+}
+
+// ParseAccept parses an Accept header into media ranges, most preferred first
+// Ranges are ordered by q-value, then by specificity (type/sub before type/* before */*).
+// Malformed entries are skipped.
+// Example:
+//
+//	request.ParseAccept("text/html;q=0.8, application/json")
+//	// [{application/json 1} {text/html 0.8}]
+func ParseAccept(header string) []MediaRange {
+	var ranges []MediaRange
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil || !strings.Contains(mt, "/") {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil || f < 0 || f > 1 {
+				continue
+			}
+			q = f
+		}
+		ranges = append(ranges, MediaRange{Type: mt, Quality: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].Quality != ranges[j].Quality {
+			return ranges[i].Quality > ranges[j].Quality
+		}
+		return specificity(ranges[i].Type) > specificity(ranges[j].Type)
+	})
+	return ranges
+}
+
+// PreferredType returns the offer the Accept header prefers, or "" if none is acceptable
+// Each offer takes the q-value of the most specific range matching it, so
+// "application/*;q=0.5, application/xml;q=0" excludes XML but allows JSON.
+// An empty header accepts anything and returns the first offer; ties keep offer order.
+// Example:
+//
+//	mt := request.PreferredType(r.Header.Get("Accept"), "application/json", "application/xml")
+//	if mt == "" {
+//		response.Error(w, http.StatusNotAcceptable, "not acceptable")
+//		return
+//	}
+func PreferredType(header string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+	ranges := ParseAccept(header)
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, spec := 0.0, -1
+		for _, mr := range ranges {
+			if s := specificity(mr.Type); s > spec && matchMediaType(mr.Type, offer) {
+				q, spec = mr.Quality, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// Accepts reports whether the request's Accept header allows mediaType
+// Example:
+//
+//	if request.Accepts(r, "text/csv") {
+//		export.CSV(w, rows)
+//	}
+func Accepts(r *http.Request, mediaType string) bool {
+	return PreferredType(r.Header.Get("Accept"), mediaType) != ""
+}
+
+// matchMediaType reports whether the range pattern (which may use wildcards) covers mediaType
+func matchMediaType(pattern, mediaType string) bool {
+	pattern, mediaType = strings.ToLower(pattern), strings.ToLower(mediaType)
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	typ, sub, _ := strings.Cut(pattern, "/")
+	mtyp, _, _ := strings.Cut(mediaType, "/")
+	return sub == "*" && typ == mtyp
+}
+
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
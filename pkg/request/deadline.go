@@ -0,0 +1,79 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is used by ContextWithTimeout for names without a configured timeout
+var DefaultTimeout = 5 * time.Second
+
+var (
+	timeoutsMu sync.RWMutex
+	timeouts   = map[string]time.Duration{}
+)
+
+// SetTimeouts registers named operation timeouts, usually config.Config.Timeouts
+// Names are case-insensitive. Calling it again replaces the previous set.
+// Example:
+//
+//	cfg := config.LoadEnv() // TIMEOUT_DB=2s, TIMEOUT_PAYMENTS=10s
+//	request.SetTimeouts(cfg.Timeouts)
+func SetTimeouts(t map[string]time.Duration) {
+	m := make(map[string]time.Duration, len(t))
+	for name, d := range t {
+		m[strings.ToLower(name)] = d
+	}
+	timeoutsMu.Lock()
+	timeouts = m
+	timeoutsMu.Unlock()
+}
+
+// TimeoutFor returns the timeout registered for name, or DefaultTimeout
+func TimeoutFor(name string) time.Duration {
+	timeoutsMu.RLock()
+	d, ok := timeouts[strings.ToLower(name)]
+	timeoutsMu.RUnlock()
+	if !ok || d <= 0 {
+		return DefaultTimeout
+	}
+	return d
+}
+
+// ContextWithTimeout derives a context from the request that expires after the named timeout
+// It is also cancelled when the client disconnects, and never outlives an earlier deadline
+// already set on the request. Always call cancel.
+// Example:
+//
+//	ctx, cancel := request.ContextWithTimeout(r, "db")
+//	defer cancel()
+//	rows, err := db.QueryContext(ctx, query, args...)
+//	if request.IsTimeout(err) {
+//	    response.Error(w, http.StatusGatewayTimeout, "database timeout")
+//	    return
+//	}
+func ContextWithTimeout(r *http.Request, name string) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), TimeoutFor(name))
+}
+
+// ClientGone reports whether the client has disconnected (the request context was cancelled)
+// Use it to skip expensive work or the response for requests nobody is waiting for.
+func ClientGone(r *http.Request) bool {
+	return errors.Is(r.Context().Err(), context.Canceled)
+}
+
+// IsCanceled reports whether err was caused by a cancelled context (usually a client disconnect)
+// There is no one to answer; log at debug level and return without writing a response.
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// IsTimeout reports whether err was caused by an exceeded deadline
+// Answer these with 503 or 504 instead of a generic 500.
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,540 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	"github.com/yoockh/go-api-utils/pkg/sanitize"
+)
+
+// Patch media types
+const (
+	MergePatchType = "application/merge-patch+json" // RFC 7386
+	JSONPatchType  = "application/json-patch+json"  // RFC 6902
+)
+
+// Patch errors; they carry their HTTP status for response.WriteError (errs.StatusCoder)
+var (
+	ErrUnsupportedPatch error = &patchError{"unsupported patch content type", http.StatusUnsupportedMediaType}
+	ErrInvalidPatch     error = &patchError{"invalid patch", http.StatusBadRequest}
+	ErrPatchTestFailed  error = &patchError{"patch test operation failed", http.StatusConflict}
+	ErrPatchTooLarge    error = &patchError{"patch body too large", http.StatusRequestEntityTooLarge}
+)
+
+type patchError struct {
+	msg    string
+	status int
+}
+
+func (e *patchError) Error() string   { return e.msg }
+func (e *patchError) StatusCode() int { return e.status }
+
+// maxPatchBody caps the patch body ParsePatch reads
+const maxPatchBody = 1 << 20
+
+// primaryKeyField is the JSON field patches may never change; handlers address the row
+// by the id from the path
+const primaryKeyField = "id"
+
+// ParsePatch applies the request body to target as a JSON Patch or JSON Merge Patch,
+// chosen by Content-Type (plain application/json is treated as a merge patch),
+// then sanitizes and validates target. It returns the top-level JSON fields that changed.
+// allowed lists the JSON fields clients may change; without it every field except "id"
+// may be patched. Owner and tenant columns belong in neither: pass an allowlist when the
+// struct has them. Bodies are limited to 1 MiB.
+// Errors: ErrUnsupportedPatch (415), ErrInvalidPatch (400, also for fields that may not be
+// patched), ErrPatchTestFailed (409), ErrPatchTooLarge (413), validator.ValidationErrors
+// (422); all work with response.WriteError.
+// Example:
+//
+//	id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+//	product, err := repo.Find(ctx, id)
+//	...
+//	fields, err := request.ParsePatch(r, &product, "name", "price", "stock")
+//	if err != nil {
+//	    response.WriteError(w, r, err)
+//	    return
+//	}
+//	query, args, err := repository.BuildPartialUpdateQuery("products", &product, fields)
+//	_, err = db.ExecContext(ctx, query, append(args, id)...) // the path id, never the body's
+func ParsePatch(r *http.Request, target interface{}, allowed ...string) ([]string, error) {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		mt = ""
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxPatchBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, fmt.Errorf("%w: limit is %d bytes", ErrPatchTooLarge, tooLarge.Limit)
+		}
+		return nil, fmt.Errorf("failed to read patch: %w", err)
+	}
+
+	var fields []string
+	switch mt {
+	case MergePatchType, "application/json":
+		fields, err = ApplyMergePatch(target, body, allowed...)
+	case JSONPatchType:
+		fields, err = ApplyJSONPatch(target, body, allowed...)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedPatch, mt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sanitize.Struct(target)
+	if err := validator.StructAll(target); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// ApplyMergePatch applies an RFC 7386 merge patch to the struct pointed to by target
+// null removes a field (resets it to its zero value); objects are merged recursively.
+// allowed restricts the fields that may change as in ParsePatch; "id" never may.
+// Example:
+//
+//	fields, err := request.ApplyMergePatch(&product, []byte(`{"price": 12.5, "description": null}`))
+//	// fields: [description price]
+func ApplyMergePatch(target interface{}, patch []byte, allowed ...string) ([]string, error) {
+	return applyPatch(target, patch, MergePatch, allowed)
+}
+
+// ApplyJSONPatch applies an RFC 6902 JSON Patch (add, remove, replace, move, copy, test)
+// to the struct pointed to by target; allowed works as in ApplyMergePatch
+// Example:
+//
+//	fields, err := request.ApplyJSONPatch(&product, []byte(`[
+//		{"op": "test", "path": "/stock", "value": 3},
+//		{"op": "replace", "path": "/stock", "value": 2}
+//	]`))
+func ApplyJSONPatch(target interface{}, patch []byte, allowed ...string) ([]string, error) {
+	return applyPatch(target, patch, JSONPatch, allowed)
+}
+
+// MergePatch applies an RFC 7386 merge patch to a JSON document
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	d, err := decodeJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: document: %v", ErrInvalidPatch, err)
+	}
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(mergeValue(d, p))
+}
+
+func mergeValue(doc, patch interface{}) interface{} {
+	pm, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	dm, ok := doc.(map[string]interface{})
+	if !ok {
+		dm = map[string]interface{}{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(dm, k)
+			continue
+		}
+		dm[k] = mergeValue(dm[k], v)
+	}
+	return dm
+}
+
+type patchOp struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+// JSONPatch applies an RFC 6902 JSON Patch to a JSON document
+// Operations are applied in order; any failure leaves the document unchanged.
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	d, err := decodeJSON(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: document: %v", ErrInvalidPatch, err)
+	}
+	var ops []patchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	for i, op := range ops {
+		if d, err = applyOp(d, op); err != nil {
+			return nil, fmt.Errorf("operation %d (%s): %w", i, op.Op, err)
+		}
+	}
+	return json.Marshal(d)
+}
+
+func applyOp(doc interface{}, op patchOp) (interface{}, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrInvalidPatch)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+	value := func() (interface{}, error) {
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		return decodeJSON(*op.Value)
+	}
+	from := func() ([]string, error) {
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrInvalidPatch)
+		}
+		return parsePointer(*op.From)
+	}
+
+	switch op.Op {
+	case "add":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, v)
+	case "remove":
+		doc, _, err := removeValue(doc, path)
+		return doc, err
+	case "replace":
+		v, err := value()
+		if err != nil {
+			return nil, err
+		}
+		if doc, _, err = removeValue(doc, path); err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, v)
+	case "move":
+		src, err := from()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > len(src) && isPrefix(src, path) {
+			return nil, fmt.Errorf("%w: cannot move %q into itself", ErrInvalidPatch, *op.From)
+		}
+		doc, v, err := removeValue(doc, src)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, path, v)
+	case "copy":
+		src, err := from()
+		if err != nil {
+			return nil, err
+		}
+		v, err := getValue(doc, src)
+		if err != nil {
+			return nil, err
+		}
+		raw, _ := json.Marshal(v)
+		v, _ = decodeJSON(raw) // deep copy
+		return addValue(doc, path, v)
+	case "test":
+		want, err := value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := getValue(doc, path)
+		if err != nil || !jsonEqual(got, want) {
+			return nil, fmt.Errorf("%w: %s", ErrPatchTestFailed, *op.Path)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+}
+
+// parsePointer splits an RFC 6901 JSON pointer into unescaped reference tokens
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("%w: pointer %q must start with /", ErrInvalidPatch, p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func getValue(doc interface{}, path []string) (interface{}, error) {
+	for _, tok := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			v, ok := node[tok]
+			if !ok {
+				return nil, fmt.Errorf("%w: path %q not found", ErrInvalidPatch, tok)
+			}
+			doc = v
+		case []interface{}:
+			i, err := arrayIndex(tok, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("%w: path %q not found", ErrInvalidPatch, tok)
+		}
+	}
+	return doc, nil
+}
+
+// update walks to the parent of path and replaces it with fn(parent, lastToken)
+func update(doc interface{}, path []string, fn func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		child, ok := node[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("%w: path %q not found", ErrInvalidPatch, path[0])
+		}
+		child, err := update(child, path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[path[0]] = child
+		return node, nil
+	case []interface{}:
+		i, err := arrayIndex(path[0], len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		child, err := update(node[i], path[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = child
+		return node, nil
+	}
+	return nil, fmt.Errorf("%w: path %q not found", ErrInvalidPatch, path[0])
+}
+
+func addValue(doc interface{}, path []string, v interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	return update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[key] = v
+			return node, nil
+		case []interface{}:
+			i := len(node)
+			if key != "-" {
+				var err error
+				if i, err = arrayIndex(key, len(node)); err != nil {
+					return nil, err
+				}
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = v
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: cannot add %q to a scalar", ErrInvalidPatch, key)
+	})
+}
+
+func removeValue(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, doc, nil
+	}
+	var removed interface{}
+	doc, err := update(doc, path, func(parent interface{}, key string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			v, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: path %q not found", ErrInvalidPatch, key)
+			}
+			removed = v
+			delete(node, key)
+			return node, nil
+		case []interface{}:
+			i, err := arrayIndex(key, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: path %q not found", ErrInvalidPatch, key)
+	})
+	return doc, removed, err
+}
+
+// arrayIndex parses an array reference token in [0, max]
+func arrayIndex(tok string, max int) (int, error) {
+	i, err := strconv.Atoi(tok)
+	if err != nil || i < 0 || i > max || (len(tok) > 1 && tok[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, tok)
+	}
+	return i, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // keep large integer IDs exact
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+// jsonEqual compares decoded JSON values, treating 1 and 1.0 as equal
+func jsonEqual(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		if aerr == nil && berr == nil {
+			return af == bf
+		}
+	}
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return bytes.Equal(ab, bb)
+}
+
+// applyPatch patches the JSON form of target and decodes the changed top-level fields back
+// Only changed fields are touched, so fields hidden from JSON (`json:"-"`) keep their values.
+// Changes to "id" or to fields outside a non-empty allowed list fail before anything is set.
+func applyPatch(target interface{}, patch []byte, apply func(doc, patch []byte) ([]byte, error), allowed []string) ([]string, error) {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: target must be a pointer to a struct", ErrInvalidPatch)
+	}
+	orig, err := json.Marshal(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode patch target: %w", err)
+	}
+	out, err := apply(orig, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(orig, &before); err != nil {
+		return nil, fmt.Errorf("failed to encode patch target: %w", err)
+	}
+	if err := json.Unmarshal(out, &after); err != nil || after == nil {
+		return nil, fmt.Errorf("%w: result is not an object", ErrInvalidPatch)
+	}
+
+	var changed []string
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	for name, raw := range after {
+		if old, ok := before[name]; !ok || !rawEqual(old, raw) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	for _, name := range changed {
+		if name == primaryKeyField || (len(allowed) > 0 && !slices.Contains(allowed, name)) {
+			return nil, fmt.Errorf("%w: field %q cannot be patched", ErrInvalidPatch, name)
+		}
+	}
+
+	// Decode every field before assigning any, so a bad patch leaves target untouched
+	fields := make([]reflect.Value, len(changed))
+	values := make([]reflect.Value, len(changed))
+	for i, name := range changed {
+		fv, ok := fieldByJSONName(rv.Elem(), name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidPatch, name)
+		}
+		nv := reflect.New(fv.Type())
+		if raw, ok := after[name]; ok {
+			if err := json.Unmarshal(raw, nv.Interface()); err != nil {
+				return nil, fmt.Errorf("%w: field %q: %v", ErrInvalidPatch, name, err)
+			}
+		}
+		fields[i], values[i] = fv, nv.Elem()
+	}
+	for i := range fields {
+		fields[i].Set(values[i])
+	}
+	return changed, nil
+}
+
+func rawEqual(a, b json.RawMessage) bool {
+	av, aerr := decodeJSON(a)
+	bv, berr := decodeJSON(b)
+	return aerr == nil && berr == nil && jsonEqual(av, bv)
+}
+
+// fieldByJSONName finds the settable field encoded under name, following embedded structs
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			ev := v.Field(i)
+			if ev.Kind() == reflect.Pointer {
+				if ev.IsNil() {
+					continue
+				}
+				ev = ev.Elem()
+			}
+			if ev.Kind() == reflect.Struct {
+				if fv, ok := fieldByJSONName(ev, name); ok {
+					return fv, true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,123 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+)
+
+// ErrPathMismatch is returned by MatchPath when the path does not have the pattern's shape
+var ErrPathMismatch = errors.New("path does not match pattern")
+
+// PathParamError reports a path segment that sits where the pattern expects a parameter
+// but does not hold a value of the parameter's type, e.g. "abc" for {id:int}
+type PathParamError struct {
+	Name  string // parameter name from the pattern
+	Type  string // int, uuid or string
+	Value string // the raw segment
+}
+
+func (e *PathParamError) Error() string {
+	return fmt.Sprintf("invalid %s %q for path parameter %s", e.Type, e.Value, e.Name)
+}
+
+// PathParams holds the parameter values of a path matched by MatchPath
+type PathParams map[string]string
+
+// MatchPath matches the request path against pattern and returns its parameters
+// Pattern segments are literals or {name}, {name:int}, {name:uuid}; a trailing slash
+// on either side is ignored. Errors: ErrPathMismatch when literals or the segment count
+// differ (try the next route / 404), *PathParamError when a typed segment is invalid (400).
+// Example:
+//
+//	p, err := request.MatchPath(r, "/products/{id:int}/reviews/{reviewID:uuid}")
+//	var pe *request.PathParamError
+//	switch {
+//	case errors.Is(err, request.ErrPathMismatch):
+//	    response.NotFound(w, "not found")
+//	    return
+//	case errors.As(err, &pe):
+//	    response.BadRequest(w, pe.Error())
+//	    return
+//	}
+//	productID, _ := p.Int64("id")
+//	reviewID := p.String("reviewID")
+func MatchPath(r *http.Request, pattern string) (PathParams, error) {
+	return matchPath(r.URL.Path, pattern)
+}
+
+func matchPath(path, pattern string) (PathParams, error) {
+	segs, pats := pathSegments(path), pathSegments(pattern)
+	if len(segs) != len(pats) {
+		return nil, ErrPathMismatch
+	}
+	params := PathParams{}
+	var invalid *PathParamError
+	for i, pat := range pats {
+		if !strings.HasPrefix(pat, "{") || !strings.HasSuffix(pat, "}") {
+			if pat != segs[i] {
+				return nil, ErrPathMismatch
+			}
+			continue
+		}
+		name, typ, _ := strings.Cut(pat[1:len(pat)-1], ":")
+		if segs[i] == "" {
+			return nil, ErrPathMismatch
+		}
+		// keep scanning literals: a later mismatch means this route doesn't apply at all
+		if invalid == nil && !validPathParam(typ, segs[i]) {
+			invalid = &PathParamError{Name: name, Type: typ, Value: segs[i]}
+		}
+		params[name] = segs[i]
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+	return params, nil
+}
+
+// pathSegments splits a path on "/" without the leading and trailing slash
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func validPathParam(typ, v string) bool {
+	switch typ {
+	case "int":
+		_, err := strconv.ParseInt(v, 10, 64)
+		return err == nil
+	case "uuid":
+		return validator.IsValidUUID(v)
+	}
+	return true
+}
+
+// String returns the parameter name, or "" if the pattern has no such parameter
+func (p PathParams) String(name string) string {
+	return p[name]
+}
+
+// Int64 returns the parameter name as int64; *PathParamError if it is missing or not an integer
+func (p PathParams) Int64(name string) (int64, error) {
+	n, err := strconv.ParseInt(p[name], 10, 64)
+	if err != nil {
+		return 0, &PathParamError{Name: name, Type: "int", Value: p[name]}
+	}
+	return n, nil
+}
+
+// UUID returns the parameter name if it is a canonical UUID; *PathParamError otherwise
+func (p PathParams) UUID(name string) (string, error) {
+	if !validator.IsValidUUID(p[name]) {
+		return "", &PathParamError{Name: name, Type: "uuid", Value: p[name]}
+	}
+	return p[name], nil
+}
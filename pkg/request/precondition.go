@@ -0,0 +1,68 @@
+package request
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+)
+
+// ETag computes a strong entity tag from values identifying a resource version,
+// such as the ID and updated_at, or a version column
+// Example:
+//
+//	etag := request.ETag(product.ID, product.UpdatedAt) // "\"3f2a9c...\""
+//	w.Header().Set("ETag", etag)
+func ETag(parts ...interface{}) string {
+	h := sha256.New()
+	for _, p := range parts {
+		if t, ok := p.(time.Time); ok {
+			p = t.UTC().Format(time.RFC3339Nano) // same instant, same tag, whatever the location
+		}
+		fmt.Fprintf(h, "%v\x00", p)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// RequiresIfMatch reports whether method modifies a resource (PUT, PATCH, DELETE)
+func RequiresIfMatch(method string) bool {
+	return method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+}
+
+// CheckIfMatch compares the If-Match header with the resource's current ETag
+// PUT, PATCH and DELETE without If-Match get errs.ErrPreconditionRequired (428); a header
+// matching none of its tags gets errs.ErrPreconditionFailed (412). "*" matches any existing
+// resource; weak tags (W/"...") never match, as RFC 9110 requires strong comparison.
+// Example:
+//
+//	product, err := repo.Find(ctx, id)
+//	...
+//	etag := request.ETag(product.ID, product.UpdatedAt)
+//	if err := request.CheckIfMatch(r, etag); err != nil {
+//	    w.Header().Set("ETag", etag)
+//	    response.Error(w, errs.HTTPStatus(err), err.Error())
+//	    return
+//	}
+func CheckIfMatch(r *http.Request, current string) error {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		if RequiresIfMatch(r.Method) {
+			return fmt.Errorf("If-Match header is required: %w", errs.ErrPreconditionRequired)
+		}
+		return nil
+	}
+	if header == "*" {
+		return nil
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, "W/") && tag == current {
+			return nil
+		}
+	}
+	return fmt.Errorf("resource was modified: %w", errs.ErrPreconditionFailed)
+}
@@ -1,14 +1,16 @@
 package request
 
 import (
-	"encoding/json"
 	"net/http"
 	"strconv"
-	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/codec"
+	"github.com/yoockh/go-api-utils/pkg/sanitize"
 )
 
 // ParseJSON decodes JSON request body into provided struct
 // Use this to parse POST/PUT request body
+// Fields tagged with `sanitize:"..."` are normalized after decoding (see pkg/sanitize)
 // Example:
 //
 //	var product Product
@@ -17,19 +19,23 @@ import (
 //	    return
 //	}
 func ParseJSON(r *http.Request, v interface{}) error {
-	decoder := json.NewDecoder(r.Body)
+	decoder := codec.JSON().NewDecoder(r.Body)
 	decoder.DisallowUnknownFields() // Reject unknown fields
-	return decoder.Decode(v)
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	sanitize.Struct(v)
+	return nil
 }
 
 // GetIDFromURL extracts ID from URL path
-// Assumes URL format: /resource/123
-// Use this to get resource ID from URL
+// Assumes URL format: /resource/123 (a trailing slash is ignored)
+// Use this to get resource ID from URL; for nested routes use MatchPath
 // Example:
 //
 //	id, err := request.GetIDFromURL(r)  // from /products/123 -> returns 123
 func GetIDFromURL(r *http.Request) (int, error) {
-	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	parts := pathSegments(r.URL.Path)
 	if len(parts) == 0 {
 		return 0, strconv.ErrSyntax
 	}
@@ -67,12 +73,12 @@ func GetQueryParamInt(r *http.Request, key string, defaultValue int) int {
 }
 
 // GetPathSegment extracts specific segment from URL path
-// Use this to extract path parameters
+// Use this to extract path parameters; see MatchPath for named, typed parameters
 // Example:
 //
 //	category := request.GetPathSegment(r, 1)  // from /products/electronics/123
 func GetPathSegment(r *http.Request, index int) string {
-	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	parts := pathSegments(r.URL.Path)
 	if index >= 0 && index < len(parts) {
 		return parts[index]
 	}
@@ -0,0 +1,279 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/i18n"
+	"github.com/yoockh/go-api-utils/pkg/repository"
+	"github.com/yoockh/go-api-utils/pkg/response"
+	"github.com/yoockh/go-api-utils/pkg/sanitize"
+)
+
+// Result is the outcome of one item of a bulk request, in request order
+type Result struct {
+	Index  int                 `json:"index"`
+	ID     interface{}         `json:"id,omitempty"`
+	Status int                 `json:"status"`
+	Data   interface{}         `json:"data,omitempty"`
+	Error  string              `json:"error,omitempty"`
+	Code   string              `json:"code,omitempty"`
+	Errors map[string][]string `json:"errors,omitempty"`
+}
+
+// BatchMeta summarizes a bulk request
+type BatchMeta struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// batch tracks the results of a bulk request
+// Items are decoded and validated one by one, the valid ones are written with the
+// repository's Batch methods when it has them, and when that bulk write fails they are
+// written one by one so every item gets its own status.
+type batch[T any, ID comparable] struct {
+	r       *http.Request
+	results []Result
+	items   []T
+	pending []int // indexes of valid items not written yet
+}
+
+func (h *handlers[T, ID]) createMany(w http.ResponseWriter, r *http.Request) error {
+	b, err := h.decodeBatch(r, false)
+	if err != nil {
+		return err
+	}
+	if bulk, ok := h.repo.(repository.Batch[T, ID]); ok && len(b.pending) > 0 {
+		valid := b.valid()
+		if err := bulk.CreateMany(r.Context(), valid); err == nil {
+			for n, i := range b.pending {
+				b.succeed(i, http.StatusCreated, &valid[n])
+			}
+			b.pending = nil
+		}
+	}
+	for _, i := range b.pending {
+		v := b.items[i]
+		if err := h.repo.Create(r.Context(), &v); err != nil {
+			b.fail(i, err)
+			continue
+		}
+		b.succeed(i, http.StatusCreated, &v)
+	}
+	b.write(w, h.opts.Name+"s created")
+	return nil
+}
+
+func (h *handlers[T, ID]) updateMany(w http.ResponseWriter, r *http.Request) error {
+	b, err := h.decodeBatch(r, true)
+	if err != nil {
+		return err
+	}
+	if bulk, ok := h.repo.(repository.Batch[T, ID]); ok && len(b.pending) > 0 {
+		valid := b.valid()
+		if missing, err := bulk.UpdateMany(r.Context(), valid); err == nil {
+			notFound := idSet(missing)
+			for n, i := range b.pending {
+				if id, _ := repository.IDOf[T, ID](valid[n]); notFound[id] {
+					b.fail(i, errs.NotFound(h.opts.Name))
+					continue
+				}
+				b.succeed(i, http.StatusOK, &valid[n])
+			}
+			b.pending = nil
+		}
+	}
+	for _, i := range b.pending {
+		v := b.items[i]
+		if err := h.repo.Update(r.Context(), &v); err != nil {
+			b.fail(i, h.notFound(err))
+			continue
+		}
+		b.succeed(i, http.StatusOK, &v)
+	}
+	b.write(w, h.opts.Name+"s updated")
+	return nil
+}
+
+func (h *handlers[T, ID]) deleteMany(w http.ResponseWriter, r *http.Request) error {
+	ids, err := h.batchIDs(r)
+	if err != nil {
+		return err
+	}
+	b := &batch[T, ID]{r: r, results: make([]Result, len(ids))}
+	for i, id := range ids {
+		b.results[i] = Result{Index: i, ID: id}
+	}
+	if bulk, ok := h.repo.(repository.Batch[T, ID]); ok {
+		if missing, err := bulk.DeleteMany(r.Context(), ids); err == nil {
+			notFound := idSet(missing)
+			for i, id := range ids {
+				if notFound[id] {
+					b.fail(i, errs.NotFound(h.opts.Name))
+					continue
+				}
+				b.succeed(i, http.StatusNoContent, nil)
+			}
+			b.write(w, h.opts.Name+"s deleted")
+			return nil
+		}
+	}
+	for i, id := range ids {
+		if err := h.repo.Delete(r.Context(), id); err != nil {
+			b.fail(i, h.notFound(err))
+			continue
+		}
+		b.succeed(i, http.StatusNoContent, nil)
+	}
+	b.write(w, h.opts.Name+"s deleted")
+	return nil
+}
+
+// decodeBatch reads a JSON array of items, decoding and validating each on its own;
+// withID requires every item to carry an id
+func (h *handlers[T, ID]) decodeBatch(r *http.Request, withID bool) (*batch[T, ID], error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		return nil, errs.BadRequest("invalid request body: expected a JSON array of " + h.opts.Name + "s")
+	}
+	if err := h.checkSize(len(raw)); err != nil {
+		return nil, err
+	}
+
+	b := &batch[T, ID]{r: r, results: make([]Result, len(raw)), items: make([]T, len(raw))}
+	for i, msg := range raw {
+		b.results[i].Index = i
+		if err := decodeItem(msg, &b.items[i]); err != nil {
+			b.fail(i, errs.BadRequest("invalid "+h.opts.Name+": "+err.Error()))
+			continue
+		}
+		if withID {
+			id, err := repository.IDOf[T, ID](b.items[i])
+			var zero ID
+			if err != nil || id == zero {
+				b.fail(i, errs.Validation(map[string][]string{"id": {"id is required"}}))
+				continue
+			}
+			b.results[i].ID = id
+		}
+		if err := h.validate(r.Context(), &b.items[i]); err != nil {
+			b.fail(i, err)
+			continue
+		}
+		b.pending = append(b.pending, i)
+	}
+	return b, nil
+}
+
+// batchIDs reads the ids to delete from {"ids": [...]} or ?ids=1,2,3
+func (h *handlers[T, ID]) batchIDs(r *http.Request) ([]ID, error) {
+	var ids []ID
+	if list := r.URL.Query().Get("ids"); list != "" {
+		for _, s := range strings.Split(list, ",") {
+			id, err := h.opts.ParseID(strings.TrimSpace(s))
+			if err != nil {
+				return nil, errs.BadRequest(fmt.Sprintf("invalid %s id %q", h.opts.Name, s))
+			}
+			ids = append(ids, id)
+		}
+	} else {
+		var body struct {
+			IDs []ID `json:"ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			return nil, errs.BadRequest(`invalid request body: expected {"ids": [...]}`)
+		}
+		ids = body.IDs
+	}
+	if err := h.checkSize(len(ids)); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (h *handlers[T, ID]) checkSize(n int) error {
+	if n == 0 {
+		return errs.BadRequest("no " + h.opts.Name + "s given")
+	}
+	if n > h.opts.MaxBatch {
+		return errs.New(http.StatusRequestEntityTooLarge, "", fmt.Sprintf("at most %d %ss per request", h.opts.MaxBatch, h.opts.Name))
+	}
+	return nil
+}
+
+// decodeItem decodes one item like request.ParseJSON: unknown fields are rejected and
+// `sanitize` tags applied
+func decodeItem(msg json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(msg))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	sanitize.Struct(v)
+	return nil
+}
+
+// valid returns copies of the pending items
+func (b *batch[T, ID]) valid() []T {
+	valid := make([]T, len(b.pending))
+	for n, i := range b.pending {
+		valid[n] = b.items[i]
+	}
+	return valid
+}
+
+// succeed records a written item; v is nil for deletes
+func (b *batch[T, ID]) succeed(i, status int, v *T) {
+	res := &b.results[i]
+	res.Status = status
+	if v != nil {
+		res.Data = *v
+		if id, err := repository.IDOf[T, ID](*v); err == nil {
+			res.ID = id
+		}
+	}
+}
+
+// fail records err like response.WriteError renders it; 5xx errors are reported
+func (b *batch[T, ID]) fail(i int, err error) {
+	e := i18n.LocalizeError(b.r.Context(), errs.From(err))
+	errs.CheckCode(e.Code)
+	if e.Status >= http.StatusInternalServerError {
+		errs.ReportRequest(b.r, err)
+	}
+	res := &b.results[i]
+	res.Status, res.Error, res.Code, res.Errors = e.Status, e.Message, e.Code, e.Fields
+}
+
+// write answers 200 when every item succeeded and 207 Multi-Status otherwise
+func (b *batch[T, ID]) write(w http.ResponseWriter, message string) {
+	meta := BatchMeta{Total: len(b.results)}
+	for _, res := range b.results {
+		if res.Status < http.StatusBadRequest {
+			meta.Succeeded++
+		}
+	}
+	meta.Failed = meta.Total - meta.Succeeded
+	status := http.StatusOK
+	if meta.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	response.Negotiate(w, b.r, status, response.LinkedResponse{
+		Response: response.Response{Success: meta.Failed == 0, Message: message, Data: b.results},
+		Meta:     meta,
+	})
+}
+
+func idSet[ID comparable](ids []ID) map[ID]bool {
+	m := make(map[ID]bool, len(ids))
+	for _, id := range ids {
+		m[id] = true
+	}
+	return m
+}
@@ -0,0 +1,335 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg-echo/validator"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/pagination"
+	"github.com/yoockh/go-api-utils/pkg/repository"
+	"github.com/yoockh/go-api-utils/pkg/request"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// Router registers handlers for method patterns ("GET /products/{id}"); *http.ServeMux implements it
+type Router interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// Options configures the routes mounted by Mount
+type Options[T any, ID comparable] struct {
+	// Name is the singular resource name used in messages (default: the last path segment
+	// without a trailing "s", e.g. "product" for "/products")
+	Name string
+	// Sort lists the public sort keys of ?sort=; without it sort parameters are rejected
+	Sort repository.SortSpec
+	// DefaultSort is used when ?sort= is missing, e.g. "-created_at"
+	DefaultSort string
+	// Filter lists the fields accepted by ?filter= and filter[field][op]=; without it filter
+	// parameters are rejected
+	Filter repository.FilterSpec
+	// Search enables ?q= (the repository must be Searchable)
+	Search bool
+	// ParseID converts the {id} path value; the default handles string and integer ids
+	ParseID func(s string) (ID, error)
+	// Validate runs after the `validate` struct tags on created, replaced and patched values
+	Validate func(ctx context.Context, v *T) error
+	// Write wraps the POST, PUT, PATCH and DELETE handlers, e.g. with JWT and role middleware
+	Write func(http.Handler) http.Handler
+	// ReadOnly mounts only the GET routes
+	ReadOnly bool
+	// Batch mounts the bulk routes: POST and PUT /products:batch and DELETE /products (see Result)
+	Batch bool
+	// MaxBatch caps the items of one bulk request (default 100)
+	MaxBatch int
+}
+
+// Mount registers the REST routes of a resource backed by repo on router
+//
+//	GET    /products       list; ?page=&per_page=, ?sort=, ?filter= / filter[field][op]=, ?q=
+//	GET    /products/{id}  one item
+//	POST   /products       create (201 with Location)
+//	PUT    /products/{id}  replace
+//	PATCH  /products/{id}  JSON Merge Patch or JSON Patch (see request.ParsePatch)
+//	DELETE /products/{id}  delete (204)
+//
+// With Options.Batch:
+//
+//	POST   /products:batch  create a JSON array of items
+//	PUT    /products:batch  replace a JSON array of items carrying their ids
+//	DELETE /products        delete {"ids": [...]} or ?ids=1,2,3
+//
+// Bodies are decoded strictly, sanitized and checked against the `validate` tags; errors are
+// rendered by response.WriteError (400 bad input, 404 missing id, 409 conflict, 422 validation).
+// Lists answer with response.PaginatedWithHeaders. The path id always wins over the body id.
+// Example:
+//
+//	products := repository.NewSQL[Product, int64](db, "products").
+//		Searchable(repository.Search{Columns: []string{"name", "description"}})
+//
+//	mux := http.NewServeMux()
+//	resource.Mount(mux, "/products", products, resource.Options[Product, int64]{
+//		Sort:        repository.SortSpec{"name": "name", "price": "price"},
+//		DefaultSort: "name",
+//		Filter:      repository.FilterSpec{"price": "price", "stock": "stock"},
+//		Search:      true,
+//		Write:       adminOnly,
+//	})
+func Mount[T any, ID comparable](router Router, prefix string, repo repository.Repository[T, ID], opts Options[T, ID]) {
+	prefix = "/" + strings.Trim(prefix, "/")
+	if opts.Name == "" {
+		opts.Name = strings.TrimSuffix(path.Base(prefix), "s")
+	}
+	if opts.ParseID == nil {
+		opts.ParseID = parseID[ID]
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = 100
+	}
+	if opts.Write == nil {
+		opts.Write = func(h http.Handler) http.Handler { return h }
+	}
+	h := &handlers[T, ID]{repo: repo, prefix: prefix, opts: opts}
+
+	item := prefix + "/{id}"
+	router.Handle("GET "+prefix, response.Handle(h.list))
+	router.Handle("GET "+item, response.Handle(h.get))
+	if opts.ReadOnly {
+		return
+	}
+	router.Handle("POST "+prefix, opts.Write(response.Handle(h.create)))
+	router.Handle("PUT "+item, opts.Write(response.Handle(h.replace)))
+	router.Handle("PATCH "+item, opts.Write(response.Handle(h.patch)))
+	router.Handle("DELETE "+item, opts.Write(response.Handle(h.delete)))
+	if opts.Batch {
+		router.Handle("POST "+prefix+":batch", opts.Write(response.Handle(h.createMany)))
+		router.Handle("PUT "+prefix+":batch", opts.Write(response.Handle(h.updateMany)))
+		router.Handle("DELETE "+prefix, opts.Write(response.Handle(h.deleteMany)))
+	}
+}
+
+type handlers[T any, ID comparable] struct {
+	repo   repository.Repository[T, ID]
+	prefix string
+	opts   Options[T, ID]
+}
+
+func (h *handlers[T, ID]) list(w http.ResponseWriter, r *http.Request) error {
+	values := r.URL.Query()
+	p := pagination.FromQuery(values)
+	q := repository.Query{Page: p.Page, PerPage: p.PerPage}
+
+	switch {
+	case h.opts.Sort != nil:
+		order, err := h.opts.Sort.Parse(values.Get("sort"), h.opts.DefaultSort)
+		if err != nil {
+			return err
+		}
+		q.Sort = order.Sort()
+	case values.Has("sort"):
+		return repository.ErrInvalidSort.Newf("%s can't be sorted", h.opts.Name)
+	}
+
+	e, err := repository.ParseFilterQuery(values)
+	if err != nil {
+		return err
+	}
+	if e != nil {
+		if h.opts.Filter == nil {
+			return repository.ErrInvalidFilter.Newf("%s can't be filtered", h.opts.Name)
+		}
+		if q.Filter, err = h.opts.Filter.Resolve(e); err != nil {
+			return err
+		}
+	}
+
+	if h.opts.Search {
+		q.Search = values.Get("q")
+	}
+
+	items, total, err := h.repo.List(r.Context(), q)
+	if err != nil {
+		return err
+	}
+	if items == nil {
+		items = []T{}
+	}
+	response.PaginatedWithHeaders(w, r, h.opts.Name+"s retrieved", items, p.Meta(total))
+	return nil
+}
+
+func (h *handlers[T, ID]) get(w http.ResponseWriter, r *http.Request) error {
+	id, err := h.id(r)
+	if err != nil {
+		return err
+	}
+	v, err := h.repo.Find(r.Context(), id)
+	if err != nil {
+		return h.notFound(err)
+	}
+	response.Success(w, h.opts.Name+" retrieved", v)
+	return nil
+}
+
+func (h *handlers[T, ID]) create(w http.ResponseWriter, r *http.Request) error {
+	var v T
+	if err := request.ParseJSON(r, &v); err != nil {
+		return errs.BadRequest("invalid request body: " + err.Error())
+	}
+	if err := h.validate(r.Context(), &v); err != nil {
+		return err
+	}
+	if err := h.repo.Create(r.Context(), &v); err != nil {
+		return err
+	}
+	if id, err := repository.IDOf[T, ID](v); err == nil {
+		w.Header().Set("Location", fmt.Sprintf("%s/%v", h.prefix, id))
+	}
+	response.Created(w, h.opts.Name+" created", v)
+	return nil
+}
+
+func (h *handlers[T, ID]) replace(w http.ResponseWriter, r *http.Request) error {
+	id, err := h.id(r)
+	if err != nil {
+		return err
+	}
+	var v T
+	if err := request.ParseJSON(r, &v); err != nil {
+		return errs.BadRequest("invalid request body: " + err.Error())
+	}
+	if err := repository.SetID(&v, id); err != nil {
+		return err
+	}
+	if err := h.validate(r.Context(), &v); err != nil {
+		return err
+	}
+	if err := h.repo.Update(r.Context(), &v); err != nil {
+		return h.notFound(err)
+	}
+	response.Success(w, h.opts.Name+" updated", v)
+	return nil
+}
+
+func (h *handlers[T, ID]) patch(w http.ResponseWriter, r *http.Request) error {
+	id, err := h.id(r)
+	if err != nil {
+		return err
+	}
+	v, err := h.repo.Find(r.Context(), id)
+	if err != nil {
+		return h.notFound(err)
+	}
+	if _, err := request.ParsePatch(r, &v); err != nil {
+		return patchError(err)
+	}
+	if err := repository.SetID(&v, id); err != nil {
+		return err
+	}
+	if err := h.validate(r.Context(), &v); err != nil {
+		return err
+	}
+	if err := h.repo.Update(r.Context(), &v); err != nil {
+		return h.notFound(err)
+	}
+	response.Success(w, h.opts.Name+" updated", v)
+	return nil
+}
+
+func (h *handlers[T, ID]) delete(w http.ResponseWriter, r *http.Request) error {
+	id, err := h.id(r)
+	if err != nil {
+		return err
+	}
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		return h.notFound(err)
+	}
+	response.NoContent(w)
+	return nil
+}
+
+// id parses the {id} path value
+func (h *handlers[T, ID]) id(r *http.Request) (ID, error) {
+	id, err := h.opts.ParseID(r.PathValue("id"))
+	if err != nil {
+		return id, errs.BadRequest("invalid " + h.opts.Name + " id")
+	}
+	return id, nil
+}
+
+// validate checks the struct tags, then Options.Validate
+func (h *handlers[T, ID]) validate(ctx context.Context, v *T) error {
+	if err := validator.StructAll(v); err != nil {
+		return validationError(err)
+	}
+	if h.opts.Validate != nil {
+		return h.opts.Validate(ctx, v)
+	}
+	return nil
+}
+
+// notFound replaces the repository's "products 42: not found" with "product not found"
+func (h *handlers[T, ID]) notFound(err error) error {
+	if errors.Is(err, errs.ErrNotFound) {
+		return errs.NotFound(h.opts.Name)
+	}
+	return err
+}
+
+func validationError(err error) error {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		return errs.Validation(verrs.Fields()).Wrap(verrs) // the cause lets i18n translate by rule
+	}
+	return err
+}
+
+// patchError gives the request.ParsePatch errors their statuses
+func patchError(err error) error {
+	switch {
+	case errors.Is(err, request.ErrUnsupportedPatch):
+		return errs.New(http.StatusUnsupportedMediaType, "", err.Error())
+	case errors.Is(err, request.ErrPatchTestFailed):
+		return errs.Conflict(err.Error())
+	case errors.Is(err, request.ErrPatchTooLarge):
+		return errs.New(http.StatusRequestEntityTooLarge, "", err.Error())
+	case errors.Is(err, request.ErrInvalidPatch):
+		return errs.BadRequest(err.Error())
+	}
+	return validationError(err)
+}
+
+// parseID converts path values to string and integer ids
+func parseID[ID comparable](s string) (ID, error) {
+	var id ID
+	v := reflect.ValueOf(&id).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		if s == "" {
+			return id, strconv.ErrSyntax
+		}
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return id, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return id, err
+		}
+		v.SetUint(n)
+	default:
+		return id, fmt.Errorf("resource: set Options.ParseID for %T ids", id)
+	}
+	return id, nil
+}
@@ -0,0 +1,95 @@
+package response
+
+import (
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SetFileHeaders sets Content-Type, Content-Disposition and Content-Length for a file response
+// disposition is "inline" or "attachment"; size < 0 leaves Content-Length unset (chunked).
+// An empty contentType is detected from the filename extension.
+// Use this when you stream the body yourself (or from another framework)
+func SetFileHeaders(h http.Header, disposition, filename, contentType string, size int64) {
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Disposition", ContentDisposition(disposition, filename))
+	h.Set("X-Content-Type-Options", "nosniff")
+	if size >= 0 {
+		h.Set("Content-Length", strconv.FormatInt(size, 10))
+	}
+}
+
+// ContentDisposition builds a Content-Disposition header value with a safe filename
+// Non-ASCII names are encoded per RFC 2231 (the filename* parameter).
+// Example:
+//
+//	w.Header().Set("Content-Disposition", response.ContentDisposition("attachment", "report.pdf"))
+func ContentDisposition(disposition, filename string) string {
+	if disposition != "inline" {
+		disposition = "attachment"
+	}
+	name := sanitizeFilename(filename)
+	if name == "" {
+		return disposition
+	}
+	if v := mime.FormatMediaType(disposition, map[string]string{"filename": name}); v != "" {
+		return v
+	}
+	return disposition
+}
+
+// File streams r to the client for inline display (e.g. images, PDFs in browser)
+// Example:
+//
+//	f, _ := os.Open("invoice.pdf")
+//	defer f.Close()
+//	stat, _ := f.Stat()
+//	response.File(w, f, "invoice.pdf", "application/pdf", stat.Size())
+func File(w http.ResponseWriter, r io.Reader, filename, contentType string, size int64) {
+	writeFile(w, r, "inline", filename, contentType, size)
+}
+
+// Attachment streams r to the client as a download ("Save as" dialog)
+// Pass size -1 when the length is unknown (e.g. generated CSV).
+// Example:
+//
+//	rc, _ := store.Get(ctx, "exports/orders.csv")
+//	defer rc.Close()
+//	response.Attachment(w, rc, "orders.csv", "text/csv", -1)
+func Attachment(w http.ResponseWriter, r io.Reader, filename, contentType string, size int64) {
+	writeFile(w, r, "attachment", filename, contentType, size)
+}
+
+func writeFile(w http.ResponseWriter, r io.Reader, disposition, filename, contentType string, size int64) {
+	SetFileHeaders(w.Header(), disposition, filename, contentType, size)
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, r); err != nil {
+		// Headers are already sent; log for server-side debugging
+		log.Printf("response file stream error: %v", err)
+	}
+}
+
+// sanitizeFilename strips directories and characters that could break the header
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+}
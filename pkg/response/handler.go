@@ -0,0 +1,64 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/i18n"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+)
+
+// HandlerFunc is an HTTP handler that returns an error instead of writing it
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handle adapts a HandlerFunc to http.HandlerFunc
+// A returned error is answered by WriteError: typed errors (errs.NotFound, errs.Validation, ...)
+// and sentinels keep their status and message, anything else is reported with the request
+// context and answered with a generic 500, so internal details never reach the client.
+// Example:
+//
+//	mux.Handle("GET /products/{id}", response.Handle(func(w http.ResponseWriter, r *http.Request) error {
+//		p, err := repo.Find(r.Context(), r.PathValue("id"))
+//		if errors.Is(err, sql.ErrNoRows) {
+//			return errs.NotFound("product")
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		Success(w, "product retrieved", p)
+//		return nil
+//	}))
+func Handle(fn HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			WriteError(w, r, err)
+		}
+	}
+}
+
+// WriteError renders err in the envelope with its status, code and safe message (see errs.From)
+// 5xx errors are sent to the error reporter with the request context and answered with the request ID.
+// Messages are translated into the request locale when i18n.Middleware is used (see i18n.LocalizeError).
+// Example:
+//
+//	if err := svc.Checkout(r.Context(), cart); err != nil {
+//		response.WriteError(w, r, err) // {"success":false,"error":"cart not found","code":"not_found"}
+//		return
+//	}
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	e := i18n.LocalizeError(r.Context(), errs.From(err))
+	errs.CheckCode(e.Code)
+	if e.Status >= http.StatusInternalServerError {
+		errs.ReportRequest(r, err)
+	}
+	resp := Response{
+		Success: false,
+		Error:   e.Message,
+		Code:    e.Code,
+		Errors:  e.Fields,
+	}
+	if e.Status >= http.StatusInternalServerError {
+		resp.RequestID = logging.RequestID(r.Context())
+	}
+	writeJSON(w, e.Status, resp)
+}
@@ -0,0 +1,245 @@
+package response
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yoockh/go-api-utils/pkg/codec"
+	"github.com/yoockh/go-api-utils/pkg/pagination"
+)
+
+// Link is a hypermedia link rendered under "_links"
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"`
+}
+
+// Links maps relation names (self, next, prev, related, ...) to links
+type Links map[string]Link
+
+// Add sets rel to href and returns l for chaining
+func (l Links) Add(rel, href string) Links {
+	l[rel] = Link{Href: href}
+	return l
+}
+
+// LinkedResponse is the standard envelope with pagination meta and collection links
+type LinkedResponse struct {
+	Response
+	Meta  interface{} `json:"meta,omitempty"`
+	Links Links       `json:"_links,omitempty"`
+}
+
+// SuccessWithLinks sends 200 OK with {success, message, data, meta, _links}
+// Example:
+//
+//	items := response.WithLinksEach(products, func(p Product) response.Links {
+//		return response.Links{"self": routes.Link("product", p.ID)}
+//	})
+//	response.SuccessWithLinks(w, "products retrieved", items, meta, response.PageLinks(r, page, perPage, total))
+func SuccessWithLinks(w http.ResponseWriter, message string, data, meta interface{}, links Links) {
+	writeJSON(w, http.StatusOK, LinkedResponse{
+		Response: Response{Success: true, Message: message, Data: data},
+		Meta:     meta,
+		Links:    links,
+	})
+}
+
+// WithLinks adds "_links" to the JSON object encoding of v
+// v must encode as a JSON object (a struct or map).
+// Example:
+//
+//	response.Success(w, "product retrieved", response.WithLinks(p, response.Links{
+//		"self":    routes.Link("product", p.ID),
+//		"reviews": routes.Link("product.reviews", p.ID),
+//	}))
+func WithLinks(v interface{}, links Links) interface{} {
+	return linked{value: v, links: links}
+}
+
+// WithLinksEach applies WithLinks to every item of a collection
+func WithLinksEach[T any](items []T, fn func(T) Links) []interface{} {
+	out := make([]interface{}, len(items))
+	for i, item := range items {
+		out[i] = WithLinks(item, fn(item))
+	}
+	return out
+}
+
+type linked struct {
+	value interface{}
+	links Links
+}
+
+func (l linked) MarshalJSON() ([]byte, error) {
+	body, err := codec.JSON().Marshal(l.value)
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) < 2 || body[0] != '{' {
+		return nil, fmt.Errorf("failed to add links: %T does not encode as a JSON object", l.value)
+	}
+	if len(l.links) == 0 {
+		return body, nil
+	}
+	links, err := codec.JSON().Marshal(l.links)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(body[:len(body)-1])
+	if len(bytes.TrimSpace(body[1:len(body)-1])) > 0 {
+		buf.WriteByte(',')
+	}
+	buf.WriteString(`"_links":`)
+	buf.Write(links)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// PageLinks builds self, first, last, prev and next links for a paginated collection
+// Links keep the request's other query parameters and set page and per_page.
+// Example:
+//
+//	links := response.PageLinks(r, page, perPage, total)
+//	// self: /products?page=2&per_page=10&sort=name, next: /products?page=3&per_page=10&sort=name ...
+func PageLinks(r *http.Request, page, perPage int, total int64) Links {
+	p := pagination.New(page, perPage)
+	page, perPage = p.Page, p.PerPage
+	last := max(pagination.TotalPages(total, perPage), 1)
+
+	href := func(p int) string {
+		q := r.URL.Query()
+		q.Set("page", strconv.Itoa(p))
+		q.Set("per_page", strconv.Itoa(perPage))
+		return r.URL.Path + "?" + q.Encode()
+	}
+	links := Links{}
+	links.Add("self", href(page)).Add("first", href(1)).Add("last", href(last))
+	if page > 1 {
+		links.Add("prev", href(min(page-1, last)))
+	}
+	if page < last {
+		links.Add("next", href(page+1))
+	}
+	return links
+}
+
+// LinkHeader formats the first, prev, next and last page links as an RFC 8288 Link header value
+// Example:
+//
+//	// </products?page=1&per_page=10>; rel="first", </products?page=3&per_page=10>; rel="next", ...
+//	w.Header().Set("Link", response.LinkHeader(r, meta))
+func LinkHeader(r *http.Request, meta pagination.Meta) string {
+	links := PageLinks(r, meta.Page, meta.PerPage, meta.Total)
+	var parts []string
+	for _, rel := range []string{"first", "prev", "next", "last"} {
+		if l, ok := links[rel]; ok {
+			parts = append(parts, "<"+l.Href+`>; rel="`+rel+`"`)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// SetPageHeaders sets the Link and X-Total-Count headers consumed by many data-grid libraries
+// Both headers are added to Access-Control-Expose-Headers so cross-origin frontends can read them.
+func SetPageHeaders(w http.ResponseWriter, r *http.Request, meta pagination.Meta) {
+	h := w.Header()
+	h.Set("Link", LinkHeader(r, meta))
+	h.Set("X-Total-Count", strconv.FormatInt(meta.Total, 10))
+	exposeHeaders(h, "Link", "X-Total-Count")
+}
+
+// PaginatedWithHeaders is Paginated that also sets the Link and X-Total-Count headers
+// Example:
+//
+//	p := pagination.FromRequest(r)
+//	response.PaginatedWithHeaders(w, r, "products retrieved", products, p.Meta(total))
+func PaginatedWithHeaders(w http.ResponseWriter, r *http.Request, message string, data interface{}, meta pagination.Meta) {
+	SetPageHeaders(w, r, meta)
+	Paginated(w, message, data, meta)
+}
+
+// exposeHeaders appends names missing from Access-Control-Expose-Headers
+func exposeHeaders(h http.Header, names ...string) {
+	exposed := h.Values("Access-Control-Expose-Headers")
+	for _, name := range names {
+		found := false
+		for _, v := range exposed {
+			for _, e := range strings.Split(v, ",") {
+				if strings.EqualFold(strings.TrimSpace(e), name) || strings.TrimSpace(e) == "*" {
+					found = true
+				}
+			}
+		}
+		if !found {
+			h.Add("Access-Control-Expose-Headers", name)
+		}
+	}
+}
+
+// Routes maps route names to path patterns so links can be built by name
+// Patterns use {param} or :param placeholders, filled in order by Reverse.
+// With Echo, use e.Reverse (see pkg-echo/response.RouteLink) instead.
+// Example:
+//
+//	routes := response.NewRoutes().
+//		Add("product", "/products/{id}").
+//		Add("product.reviews", "/products/{id}/reviews")
+//	mux.HandleFunc("GET "+routes.Pattern("product"), getProduct)
+type Routes struct {
+	mu       sync.RWMutex
+	patterns map[string]string
+}
+
+// NewRoutes creates an empty route registry
+func NewRoutes() *Routes {
+	return &Routes{patterns: map[string]string{}}
+}
+
+// Add registers pattern under name and returns rt for chaining
+func (rt *Routes) Add(name, pattern string) *Routes {
+	rt.mu.Lock()
+	rt.patterns[name] = pattern
+	rt.mu.Unlock()
+	return rt
+}
+
+// Pattern returns the pattern registered under name
+func (rt *Routes) Pattern(name string) string {
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+	return rt.patterns[name]
+}
+
+// Reverse fills the placeholders of the named route with params, in order
+// It returns "" for unknown names; missing params leave the placeholder as is.
+func (rt *Routes) Reverse(name string, params ...interface{}) string {
+	pattern := rt.Pattern(name)
+	if pattern == "" {
+		return ""
+	}
+	segments := strings.Split(pattern, "/")
+	for i, seg := range segments {
+		if len(params) == 0 {
+			break
+		}
+		if strings.HasPrefix(seg, ":") || (strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")) {
+			segments[i] = url.PathEscape(fmt.Sprint(params[0]))
+			params = params[1:]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Link returns a link to the named route
+func (rt *Routes) Link(name string, params ...interface{}) Link {
+	return Link{Href: rt.Reverse(name, params...)}
+}
@@ -0,0 +1,117 @@
+package response
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/yoockh/go-api-utils/pkg/codec"
+	"github.com/yoockh/go-api-utils/pkg/request"
+)
+
+// Renderer encodes v in one media type
+type Renderer func(w io.Writer, v interface{}) error
+
+type mediaTypeKey struct{}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{
+		"application/json": func(w io.Writer, v interface{}) error { return codec.JSON().NewEncoder(w).Encode(v) },
+		"application/xml":  func(w io.Writer, v interface{}) error { return xml.NewEncoder(w).Encode(v) },
+	}
+	mediaTypes = []string{"application/json", "application/xml"}
+)
+
+// RegisterRenderer adds (or replaces) the renderer used by Negotiate for mediaType
+// Registered types are offered after the built-in application/json and application/xml.
+// Example:
+//
+//	response.RegisterRenderer("text/csv", func(w io.Writer, v interface{}) error {
+//		return csvutil.Write(w, v)
+//	})
+func RegisterRenderer(mediaType string, r Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if _, ok := renderers[mediaType]; !ok {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	renderers[mediaType] = r
+}
+
+// MediaTypes returns the media types Negotiate can render, in preference order
+func MediaTypes() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	return append([]string(nil), mediaTypes...)
+}
+
+// WithMediaType stores the negotiated response media type in ctx (see middleware.Negotiate)
+func WithMediaType(ctx context.Context, mediaType string) context.Context {
+	return context.WithValue(ctx, mediaTypeKey{}, mediaType)
+}
+
+// MediaTypeFromContext returns the media type stored by WithMediaType, or ""
+func MediaTypeFromContext(ctx context.Context) string {
+	mt, _ := ctx.Value(mediaTypeKey{}).(string)
+	return mt
+}
+
+// Negotiate writes v in the media type negotiated for the request
+// It uses the type stored by middleware.Negotiate, otherwise the Accept header,
+// and falls back to JSON when nothing registered is acceptable.
+// Example:
+//
+//	response.Negotiate(w, r, http.StatusOK, response.Response{
+//		Success: true, Message: "products retrieved", Data: products,
+//	})
+func Negotiate(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	mt := MediaTypeFromContext(r.Context())
+	if mt == "" {
+		mt = request.PreferredType(r.Header.Get("Accept"), MediaTypes()...)
+	}
+	renderersMu.RLock()
+	render, ok := renderers[mt]
+	renderersMu.RUnlock()
+	if !ok {
+		mt, render = "application/json", renderers["application/json"]
+	}
+
+	e := getEncoder()
+	defer putEncoder(e)
+	if err := render(&e.buf, v); err != nil {
+		log.Printf("response %s encode error: %v", mt, err)
+		Error(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+	if !slices.Contains(w.Header().Values("Vary"), "Accept") {
+		w.Header().Add("Vary", "Accept") // middleware.Negotiate may have set it already
+	}
+	writeBody(w, status, mt, e.buf.Bytes())
+}
+
+// MarshalXML renders the envelope as <response>, with validation errors as
+// <errors><field name="email"><message>...</message></field></errors>
+func (r Response) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type field struct {
+		Name     string   `xml:"name,attr"`
+		Messages []string `xml:"message"`
+	}
+	out := struct {
+		Success bool        `xml:"success"`
+		Message string      `xml:"message,omitempty"`
+		Data    interface{} `xml:"data,omitempty"`
+		Error   string      `xml:"error,omitempty"`
+		Errors  []field     `xml:"errors>field,omitempty"`
+	}{Success: r.Success, Message: r.Message, Data: r.Data, Error: r.Error}
+	for name, msgs := range r.Errors {
+		out.Errors = append(out.Errors, field{Name: name, Messages: msgs})
+	}
+	sort.Slice(out.Errors, func(i, j int) bool { return out.Errors[i].Name < out.Errors[j].Name })
+	return e.EncodeElement(out, xml.StartElement{Name: xml.Name{Local: "response"}})
+}
@@ -0,0 +1,54 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/yoockh/go-api-utils/pkg/codec"
+)
+
+// maxPooledBuffer keeps the buffers of unusually large responses (exports, big lists) out
+// of the pool, so one of them doesn't pin its memory for the life of the process
+const maxPooledBuffer = 64 << 10
+
+// encoder is a pooled buffer with a JSON encoder writing into it
+type encoder struct {
+	buf   bytes.Buffer
+	codec codec.Codec
+	enc   codec.Encoder
+}
+
+var encoderPool = sync.Pool{New: func() any { return &encoder{} }}
+
+// getEncoder returns a pooled encoder using the current codec.JSON implementation
+func getEncoder() *encoder {
+	e := encoderPool.Get().(*encoder)
+	if c := codec.JSON(); e.enc == nil || e.codec != c {
+		e.codec, e.enc = c, c.NewEncoder(&e.buf)
+	}
+	return e
+}
+
+func putEncoder(e *encoder) {
+	if e.buf.Cap() > maxPooledBuffer {
+		return
+	}
+	e.buf.Reset()
+	encoderPool.Put(e)
+}
+
+// internalErrorBody is sent when a response can't be encoded; it is built once so the
+// fallback itself can't fail
+var internalErrorBody, _ = json.Marshal(Response{Error: "internal server error", Code: "internal"})
+
+// writeBody writes a complete response in one call, with Content-Type and Content-Length
+func writeBody(w http.ResponseWriter, status int, contentType string, body []byte) {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	w.Write(body)
+}
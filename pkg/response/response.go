@@ -1,9 +1,12 @@
 package response
 
 import (
-    "encoding/json"
+    "context"
+    "errors"
     "log"
     "net/http"
+
+    "github.com/yoockh/go-api-utils/pkg/errs"
 )
 
 // Response represents standard API response structure
@@ -12,19 +15,25 @@ type Response struct {
     Message string      `json:"message"`
     Data    interface{} `json:"data,omitempty"`
     Error   string      `json:"error,omitempty"`
+    Code    string      `json:"code,omitempty"` // machine-readable error code, set by WriteError
+    RequestID string    `json:"request_id,omitempty"` // set on 5xx errors so clients can quote it to support
+    Errors  map[string][]string `json:"errors,omitempty"` // field -> messages, set by ValidationError
 }
 
 // writeJSON writes JSON response and logs encode error server-side.
-// It ensures Content-Type and status are set before encoding, and logs
-// any encoding failure for server-side debugging without exposing details
-// to clients.
+// v is encoded into a pooled buffer before anything is sent, so the response goes out
+// in one write with Content-Length, and an encoding failure still becomes a clean
+// 500 instead of a truncated body behind the original status.
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
-    w.Header().Set("Content-Type", "application/json")
-    w.WriteHeader(status)
-    if err := json.NewEncoder(w).Encode(v); err != nil {
+    e := getEncoder()
+    defer putEncoder(e)
+    if err := e.enc.Encode(v); err != nil {
         // Log encode error for server-side debugging; do NOT expose details to client
         log.Printf("response encode error: %v", err)
+        writeBody(w, http.StatusInternalServerError, "application/json", internalErrorBody)
+        return
     }
+    writeBody(w, status, "application/json", e.buf.Bytes())
 }
 
 // Success sends a successful JSON response (200 OK)
@@ -40,6 +49,23 @@ func Success(w http.ResponseWriter, message string, data interface{}) {
     })
 }
 
+// Paginated sends 200 OK with {success, message, data, meta}
+// Use this for list endpoints; meta is usually pagination.Meta or pagination.CursorMeta
+// Example:
+//
+//	p := pagination.FromRequest(r)
+//	response.Paginated(w, "products retrieved", products, p.Meta(total))
+func Paginated(w http.ResponseWriter, message string, data interface{}, meta interface{}) {
+    writeJSON(w, http.StatusOK, LinkedResponse{
+        Response: Response{
+            Success: true,
+            Message: message,
+            Data:    data,
+        },
+        Meta: meta,
+    })
+}
+
 // Created sends a resource created response (201 Created)
 // Use this after successful POST/CREATE operations
 // Example:
@@ -112,9 +138,42 @@ func Forbidden(w http.ResponseWriter, message string) {
 
 // InternalServerError sends internal server error (500 Internal Server Error)
 // Use this for unexpected server errors
+// The message is sent to the error reporter (see errs.SetReporter); use Handle to report
+// the underlying error together with the request context.
 // Example:
 //
 //	response.InternalServerError(w, "Something went wrong")
 func InternalServerError(w http.ResponseWriter, message string) {
+    errs.Report(context.Background(), errors.New(message))
     Error(w, http.StatusInternalServerError, message)
 }
+
+// ValidationError sends field-level validation errors (422 Unprocessable Entity)
+// Use this with validator.ValidationErrors.Fields() to report every invalid field at once
+// Example:
+//
+//	if err := validator.StructAll(&req); err != nil {
+//	    var verrs validator.ValidationErrors
+//	    if errors.As(err, &verrs) {
+//	        response.ValidationError(w, verrs.Fields())
+//	        return
+//	    }
+//	}
+func ValidationError(w http.ResponseWriter, fields map[string][]string) {
+    writeJSON(w, http.StatusUnprocessableEntity, Response{
+        Success: false,
+        Error:   "validation failed",
+        Code:    "validation_failed",
+        Errors:  fields,
+    })
+}
+
+// PreconditionFailed sends a precondition failed error (412 Precondition Failed)
+// Use this when If-Match does not match the current ETag (see request.CheckIfMatch)
+// Example:
+//
+//	w.Header().Set("ETag", etag)
+//	response.PreconditionFailed(w, "product was modified, reload and retry")
+func PreconditionFailed(w http.ResponseWriter, message string) {
+    Error(w, http.StatusPreconditionFailed, message)
+}
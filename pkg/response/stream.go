@@ -0,0 +1,113 @@
+package response
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"net/http"
+)
+
+// streamFlushSize is how much encoded data StreamArray collects before writing and flushing
+const streamFlushSize = 32 << 10
+
+// StreamArray sends 200 with {message, data: [...], success} where data is streamed from items
+// Items are encoded one at a time and flushed to the client every 32 KiB, so exports of
+// millions of rows never build a slice in memory. success comes last: when items fail after
+// data was sent, the array is closed with "success":false, "error" and "code" instead, so
+// clients can tell a complete list from a truncated one. A failure before anything was sent
+// gets a plain 500. The error of items, or of writing to the client, is returned.
+// Example:
+//
+//	rows, err := db.QueryContext(r.Context(), `SELECT id, name, price FROM products`)
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	return response.StreamArray(w, "products retrieved", response.ScanRows(rows, func(rows *sql.Rows) (Product, error) {
+//		var p Product
+//		err := rows.Scan(&p.ID, &p.Name, &p.Price)
+//		return p, err
+//	}))
+func StreamArray[T any](w http.ResponseWriter, message string, items iter.Seq2[T, error]) error {
+	e := getEncoder()
+	defer putEncoder(e)
+	rc := http.NewResponseController(w)
+
+	msg, err := e.codec.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	e.buf.WriteString(`{"message":`)
+	e.buf.Write(msg)
+	e.buf.WriteString(`,"data":[`)
+
+	started := false
+	flush := func() error {
+		if !started {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if _, err := w.Write(e.buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write stream: %w", err)
+		}
+		e.buf.Reset()
+		if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return fmt.Errorf("failed to flush stream: %w", err)
+		}
+		return nil
+	}
+
+	n := 0
+	for item, err := range items {
+		if err == nil {
+			mark := e.buf.Len()
+			if n > 0 {
+				e.buf.WriteByte(',')
+			}
+			if err = e.enc.Encode(item); err != nil {
+				e.buf.Truncate(mark) // keep the array valid up to the last good item
+			}
+		}
+		if err != nil {
+			log.Printf("response stream error after %d items: %v", n, err)
+			if !started {
+				e.buf.Reset()
+				writeBody(w, http.StatusInternalServerError, "application/json", internalErrorBody)
+				return err
+			}
+			e.buf.WriteString(`],"success":false,"error":"internal server error","code":"internal"}`)
+			if ferr := flush(); ferr != nil {
+				return errors.Join(err, ferr)
+			}
+			return err
+		}
+		n++
+		if e.buf.Len() >= streamFlushSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	e.buf.WriteString(`],"success":true}`)
+	return flush()
+}
+
+// ScanRows adapts sql.Rows to StreamArray; scan reads the current row into a T
+// rows.Err is yielded after the last row. Closing rows stays with the caller.
+func ScanRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for rows.Next() {
+			item, err := scan(rows)
+			if !yield(item, err) || err != nil {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
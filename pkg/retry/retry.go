@@ -0,0 +1,138 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// Option configures retry behavior for Do
+type Option func(*options)
+
+// Backoff returns how long to wait before the given retry attempt (1-based)
+type Backoff func(attempt int) time.Duration
+
+type options struct {
+	attempts int
+	backoff  Backoff
+	retryIf  func(error) bool
+}
+
+const (
+	defaultAttempts = 3
+	defaultBase     = 100 * time.Millisecond
+	defaultMax      = 5 * time.Second
+)
+
+// permanentError marks an error that must not be retried
+type permanentError struct{ err error }
+
+func (p *permanentError) Error() string { return p.err.Error() }
+func (p *permanentError) Unwrap() error { return p.err }
+
+// Permanent wraps err so Do stops retrying and returns err immediately
+// Example:
+//
+//	if resp.StatusCode == http.StatusBadRequest {
+//	    return retry.Permanent(errors.New("bad request"))
+//	}
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Attempts sets the maximum number of calls to fn (including the first one)
+// Values below 1 are treated as 1.
+func Attempts(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.attempts = n
+	}
+}
+
+// ExponentialBackoff waits base * 2^(attempt-1), capped at max, with full jitter
+// Jitter spreads retries from many callers so they don't hit the server at once.
+func ExponentialBackoff(base, max time.Duration) Option {
+	return WithBackoff(func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		if d <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(d) + 1))
+	})
+}
+
+// ConstantBackoff waits the same duration between every attempt
+func ConstantBackoff(d time.Duration) Option {
+	return WithBackoff(func(int) time.Duration { return d })
+}
+
+// WithBackoff sets a custom backoff function
+func WithBackoff(b Backoff) Option {
+	return func(o *options) {
+		if b != nil {
+			o.backoff = b
+		}
+	}
+}
+
+// RetryIf only retries errors for which fn returns true
+// Errors wrapped with Permanent are never retried regardless of fn.
+func RetryIf(fn func(error) bool) Option {
+	return func(o *options) {
+		o.retryIf = fn
+	}
+}
+
+// Do calls fn until it succeeds, the attempts are exhausted, the error is not
+// retryable, or ctx is done. It returns the last error from fn (unwrapped from Permanent).
+// Example:
+//
+//	err := retry.Do(ctx, func(ctx context.Context) error {
+//	    return db.PingContext(ctx)
+//	}, retry.Attempts(5), retry.ExponentialBackoff(200*time.Millisecond, 5*time.Second))
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	o := options{attempts: defaultAttempts}
+	ExponentialBackoff(defaultBase, defaultMax)(&o)
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var err error
+	for attempt := 1; attempt <= o.attempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if o.retryIf != nil && !o.retryIf(err) {
+			return err
+		}
+		if attempt == o.attempts {
+			break
+		}
+
+		timer := time.NewTimer(o.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+	return err
+}
@@ -0,0 +1,244 @@
+package sanitize
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Func transforms a string value
+type Func func(string) string
+
+var (
+	mu    sync.RWMutex
+	funcs = map[string]Func{
+		"trim":            strings.TrimSpace,
+		"lower":           strings.ToLower,
+		"upper":           strings.ToUpper,
+		"title":           Title,
+		"strip_html":      StripHTML,
+		"collapse_spaces": CollapseSpaces,
+	}
+)
+
+// Register adds or replaces a sanitizer usable in `sanitize` tags
+// Example:
+//
+//	sanitize.Register("digits", func(s string) string {
+//		return strings.Map(func(r rune) rune {
+//			if unicode.IsDigit(r) { return r }
+//			return -1
+//		}, s)
+//	})
+func Register(name string, fn Func) {
+	mu.Lock()
+	defer mu.Unlock()
+	funcs[name] = fn
+}
+
+// Struct applies `sanitize` tags to the string fields of v (a pointer to struct), in tag order
+// Nested structs, slices and maps of structs, *string and []string fields are handled too.
+// Unknown sanitizer names are ignored. Run it after decoding and before validation.
+// Example:
+//
+//	type SignupRequest struct {
+//		Email string `json:"email" sanitize:"trim,lower" validate:"required,email"`
+//		Name  string `json:"name" sanitize:"strip_html,collapse_spaces,title"`
+//	}
+//	sanitize.Struct(&req)
+func Struct(v interface{}) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return
+	}
+	walk(rv.Elem(), 0)
+}
+
+const maxDepth = 32
+
+func walk(v reflect.Value, depth int) {
+	if depth > maxDepth {
+		return
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			fv := v.Field(i)
+			if tag := sf.Tag.Get("sanitize"); tag != "" && tag != "-" {
+				apply(fv, strings.Split(tag, ","))
+				continue
+			}
+			walk(fv, depth+1)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		// Map values aren't addressable; copy, sanitize and store back
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			walk(elem, depth+1)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
+
+// apply runs the named sanitizers on a string, *string or []string field
+func apply(v reflect.Value, names []string) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.String && v.CanSet():
+		v.SetString(String(v.String(), names...))
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < v.Len(); i++ {
+			if e := v.Index(i); e.CanSet() {
+				e.SetString(String(e.String(), names...))
+			}
+		}
+	}
+}
+
+// String applies the named sanitizers to s in order
+// Example:
+//
+//	sanitize.String("  <b>Hello</b>   WORLD ", "strip_html", "collapse_spaces", "lower") // "hello world"
+func String(s string, names ...string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, name := range names {
+		if fn, ok := funcs[strings.TrimSpace(name)]; ok {
+			s = fn(s)
+		}
+	}
+	return s
+}
+
+// CollapseSpaces trims s and replaces runs of whitespace with a single space
+func CollapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Title upper-cases the first letter of each word and lower-cases the rest
+// Example:
+//
+//	sanitize.Title("jOHN o'neil-smith") // "John O'neil-Smith"
+func Title(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	start := true
+	for _, r := range s {
+		if start {
+			b.WriteRune(unicode.ToTitle(r))
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		start = unicode.IsSpace(r) || r == '-'
+	}
+	return b.String()
+}
+
+// StripHTML removes HTML tags and comments, plus the contents of script and style elements
+// Entities like &amp; are left as-is and a "<" that doesn't start a tag is escaped as &lt;,
+// so the result can't turn back into markup ("<<b>script>" must not become "<script>").
+func StripHTML(s string) string {
+	if !strings.ContainsRune(s, '<') {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			b.WriteRune(r)
+			i += size
+			continue
+		}
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest, "-->")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+		case hasPrefixFold(rest, "<script"), hasPrefixFold(rest, "<style"):
+			name := "</script"
+			if hasPrefixFold(rest, "<style") {
+				name = "</style"
+			}
+			end := indexFold(rest, name)
+			if end < 0 {
+				return b.String()
+			}
+			gt := strings.IndexByte(rest[end:], '>')
+			if gt < 0 {
+				return b.String()
+			}
+			i += end + gt + 1
+		case !isTagStart(rest):
+			// A "<" not starting a tag (e.g. "a < b") is text
+			b.WriteString("&lt;")
+			i++
+		default:
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				// No tag can be closed from here on, the rest is text
+				b.WriteString(strings.ReplaceAll(rest, "<", "&lt;"))
+				return b.String()
+			}
+			i += end + 1
+		}
+	}
+	return b.String()
+}
+
+// hasPrefixFold is strings.HasPrefix ignoring ASCII case
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// indexFold returns the index of the first case-insensitive match of substr ("</...")
+// in s, or -1; offsets are into s itself
+func indexFold(s, substr string) int {
+	for i := 0; ; {
+		j := strings.Index(s[i:], substr[:2])
+		if j < 0 {
+			return -1
+		}
+		i += j
+		if hasPrefixFold(s[i:], substr) {
+			return i
+		}
+		i += 2
+	}
+}
+
+// isTagStart reports whether s ("<...") looks like an opening, closing or declaration tag
+func isTagStart(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	c := s[1]
+	return c == '/' || c == '!' || c == '?' || (c|0x20 >= 'a' && c|0x20 <= 'z')
+}
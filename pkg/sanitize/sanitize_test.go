@@ -0,0 +1,34 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain text", "plain text"},
+		{"a<b>c</b>d", "acd"},
+		{"x<SCRIPT>evil()</ScRiPt >y", "xy"},
+		{"<style>p{}</STYLE>ok", "ok"},
+		{"<!-- note -->text", "text"},
+		{"a < b", "a &lt; b"},
+		{"1<2", "1&lt;2"},
+		{"unclosed <b", "unclosed &lt;b"},
+		{"İ<script>x</script>z", "İz"},
+		// a stray "<" must not join with the text left by a removed tag
+		{"<<b>script>alert(1)<</b>/script>", "&lt;script>alert(1)&lt;/script>"},
+		{"<<x>img src=x onerror=alert(1)>", "&lt;img src=x onerror=alert(1)>"},
+	}
+	for _, tt := range tests {
+		got := StripHTML(tt.in)
+		if got != tt.want {
+			t.Errorf("StripHTML(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if strings.Contains(got, "<") {
+			t.Errorf("StripHTML(%q) = %q still contains markup", tt.in, got)
+		}
+	}
+}
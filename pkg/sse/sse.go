@@ -0,0 +1,315 @@
+package sse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/graceful"
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// CloseEvent is the event sent to every stream when Config.Context is done
+const CloseEvent = "close"
+
+var (
+	// ErrClosed is returned by Send on a closed stream
+	ErrClosed = errors.New("sse: stream closed")
+	// ErrSlowClient is returned by Send when the send buffer is full; the stream is closed
+	ErrSlowClient = errors.New("sse: send buffer full, client too slow")
+)
+
+// Config configures Server-Sent Event streams
+type Config struct {
+	// JWT authenticates the request like middleware.JWT; nil accepts anonymous streams. The
+	// token comes from the Authorization header or, because EventSource can't set headers,
+	// from the TokenParam query parameter.
+	JWT *middleware.JWTConfig
+	// TokenParam is the query parameter holding the token (default "token")
+	TokenParam string
+	// Context ends every stream with a CloseEvent when done, e.g. the context canceled on SIGTERM
+	Context context.Context
+	// Drain tracks the streams for graceful.Shutdown: once it drains, new requests get 503
+	// and open streams end with a CloseEvent, or are cut off at the deadline
+	Drain *graceful.Group
+	// Heartbeat is the interval of the comment lines keeping proxies from dropping idle
+	// streams (default 15s)
+	Heartbeat time.Duration
+	// Retry is the reconnection delay advertised to clients; 0 keeps the browser default
+	Retry time.Duration
+	// WriteWait bounds each write (default 10s)
+	WriteWait time.Duration
+	// SendBuffer is the number of queued events per stream (default 32)
+	SendBuffer int
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.TokenParam == "" {
+		cfg.TokenParam = "token"
+	}
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = 15 * time.Second
+	}
+	if cfg.WriteWait <= 0 {
+		cfg.WriteWait = 10 * time.Second
+	}
+	if cfg.SendBuffer <= 0 {
+		cfg.SendBuffer = 32
+	}
+	return cfg
+}
+
+type event struct {
+	name string
+	data []byte
+}
+
+// Stream is a text/event-stream response
+// A write pump goroutine writes queued events and heartbeats; Send and Close are safe for
+// concurrent use. The stream ends when the client disconnects, Close is called or
+// Config.Context is done.
+type Stream struct {
+	w   http.ResponseWriter
+	rc  *http.ResponseController
+	cfg Config
+
+	ctx     context.Context // request context with the JWT claims; canceled when the stream ends
+	cancel  context.CancelFunc
+	release func() // untracks the stream from Config.Drain
+
+	send      chan event
+	closeReq  chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// Handler starts an event stream for each request and calls fn with it
+// fn runs on the request goroutine and owns the stream: it returns when it is done sending,
+// usually after <-s.Done(), and the stream is closed then. Unauthenticated requests get 401,
+// requests while Config.Drain is draining 503.
+// Example:
+//
+//	mux.Handle("GET /orders/{id}/events", sse.Handler(sseCfg, func(s *sse.Stream) {
+//		updates := orders.Watch(s.Context(), s.UserID())
+//		for {
+//			select {
+//			case u := <-updates:
+//				s.SendJSON("status", u)
+//			case <-s.Done():
+//				return
+//			}
+//		}
+//	}))
+//
+//	// browser: new EventSource("/orders/42/events?token=" + jwt)
+func Handler(cfg Config, fn func(s *Stream)) http.Handler {
+	cfg = cfg.withDefaults()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := start(w, r, cfg)
+		if err != nil {
+			response.WriteError(w, r, err)
+			return
+		}
+		defer s.Close()
+		fn(s)
+	})
+}
+
+// New authenticates r and starts an event stream on w
+// On error nothing has been written; answer it with response.WriteError. Close the stream
+// before the handler returns.
+func New(w http.ResponseWriter, r *http.Request, cfg Config) (*Stream, error) {
+	return start(w, r, cfg.withDefaults())
+}
+
+func start(w http.ResponseWriter, r *http.Request, cfg Config) (*Stream, error) {
+	ctx := r.Context()
+	if cfg.JWT != nil {
+		var err error
+		if ctx, err = middleware.AuthenticateRequest(ctx, r, *cfg.JWT, cfg.TokenParam); err != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Stream{
+		w:        w,
+		rc:       http.NewResponseController(w),
+		cfg:      cfg,
+		ctx:      ctx,
+		cancel:   cancel,
+		send:     make(chan event, cfg.SendBuffer),
+		closeReq: make(chan struct{}),
+		done:     make(chan struct{}),
+		release:  func() {},
+	}
+	if cfg.Drain != nil {
+		release, err := cfg.Drain.Track(cancel) // canceling ends the write pump
+		if err != nil {
+			cancel()
+			w.Header().Set("Retry-After", "5")
+			return nil, errs.New(http.StatusServiceUnavailable, "", err.Error())
+		}
+		s.release = release
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // nginx would buffer the stream otherwise
+	w.WriteHeader(http.StatusOK)
+	if cfg.Retry > 0 {
+		fmt.Fprintf(w, "retry: %d\n\n", cfg.Retry.Milliseconds())
+	}
+	if err := s.rc.Flush(); err != nil {
+		cancel()
+		s.release()
+		return nil, fmt.Errorf("event stream needs a flushable response: %w", err)
+	}
+	go s.writePump()
+	return s, nil
+}
+
+// Context returns the request context carrying the JWT claims (see middleware.UserIDFromContext);
+// it is canceled when the stream ends
+func (s *Stream) Context() context.Context {
+	return s.ctx
+}
+
+// UserID returns the authenticated user ID, 0 for anonymous streams
+func (s *Stream) UserID() uint {
+	return middleware.UserIDFromContext(s.ctx)
+}
+
+// Done is closed once the stream has ended
+func (s *Stream) Done() <-chan struct{} {
+	return s.done
+}
+
+// Send queues an event for the write pump without blocking; name "" sends an unnamed
+// "message" event
+// When the send buffer is full the client is not keeping up: the stream is closed and
+// ErrSlowClient is returned, so one slow reader never stalls the sender.
+func (s *Stream) Send(name string, data []byte) error {
+	select {
+	case <-s.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case s.send <- event{name: name, data: data}:
+		return nil
+	case <-s.done:
+		return ErrClosed
+	default:
+		go s.Close()
+		return ErrSlowClient
+	}
+}
+
+// SendJSON queues v encoded as JSON in an event named name
+func (s *Stream) SendJSON(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	return s.Send(name, data)
+}
+
+// Close writes the queued events and ends the stream
+// It returns once the write pump has stopped, so the handler may return right after; each
+// write is bounded by WriteWait.
+func (s *Stream) Close() error {
+	s.closeOnce.Do(func() { close(s.closeReq) })
+	<-s.done
+	return nil
+}
+
+func (s *Stream) writePump() {
+	defer s.teardown()
+	ticker := time.NewTicker(s.cfg.Heartbeat)
+	defer ticker.Stop()
+	var shutdown, draining <-chan struct{}
+	if s.cfg.Context != nil {
+		shutdown = s.cfg.Context.Done()
+	}
+	if s.cfg.Drain != nil {
+		draining = s.cfg.Drain.Draining()
+	}
+	for {
+		select {
+		case <-s.ctx.Done(): // client gone
+			return
+		case <-s.closeReq:
+			s.flush(nil)
+			return
+		case <-shutdown:
+			s.flush(&event{name: CloseEvent, data: []byte("server shutting down")})
+			return
+		case <-draining:
+			s.flush(&event{name: CloseEvent, data: []byte("server shutting down")})
+			return
+		case e := <-s.send:
+			if s.write(e) != nil || s.flush(nil) != nil {
+				return
+			}
+		case <-ticker.C:
+			if s.writeRaw([]byte(":\n\n")) != nil || s.rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+// flush writes the queued events, then last if not nil, and flushes the response
+func (s *Stream) flush(last *event) error {
+	for len(s.send) > 0 { // only the pump receives, so this never blocks
+		if err := s.write(<-s.send); err != nil {
+			return err
+		}
+	}
+	if last != nil {
+		if err := s.write(*last); err != nil {
+			return err
+		}
+	}
+	return s.rc.Flush()
+}
+
+// write encodes e in the event stream format; every line of data gets its own data field
+func (s *Stream) write(e event) error {
+	var b bytes.Buffer
+	if e.name != "" {
+		b.WriteString("event: ")
+		b.WriteString(strings.NewReplacer("\r", "", "\n", "").Replace(e.name))
+		b.WriteByte('\n')
+	}
+	data := strings.ReplaceAll(string(e.data), "\r\n", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: ")
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\n')
+	return s.writeRaw(b.Bytes())
+}
+
+func (s *Stream) writeRaw(p []byte) error {
+	s.rc.SetWriteDeadline(time.Now().Add(s.cfg.WriteWait)) // also lifts http.Server.WriteTimeout
+	_, err := s.w.Write(p)
+	return err
+}
+
+// teardown cancels the context and marks the stream done
+func (s *Stream) teardown() {
+	close(s.done)
+	s.cancel()
+	s.release()
+}
@@ -0,0 +1,319 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/clock"
+)
+
+var (
+	ErrUploadNotFound   = errors.New("upload session not found")
+	ErrOffsetMismatch   = errors.New("upload offset mismatch")
+	ErrUploadIncomplete = errors.New("upload is incomplete")
+)
+
+// UploadSession describes an in-progress chunked upload
+type UploadSession struct {
+	ID          string    `json:"id"`
+	Key         string    `json:"key"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int64     `json:"size"`   // total expected size
+	Offset      int64     `json:"offset"` // bytes received so far
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ChunkedUploads implements a resumable upload protocol: Init, Append (repeat), Complete
+// Chunks are staged on local disk and streamed into the Storage backend on Complete.
+// Clients that lose their connection call Status to get the current offset and resume from there.
+// Example:
+//
+//	uploads, _ := storage.NewChunkedUploads(store, "./tmp/uploads", 24*time.Hour)
+//	go uploads.StartCleanup(ctx, time.Hour)
+//	mux.Handle("/uploads/", uploads.Handler("/uploads"))
+type ChunkedUploads struct {
+	store   Storage
+	dir     string
+	ttl     time.Duration
+	MaxSize int64 // maximum total file size (0 = unlimited)
+	// KeyFunc builds the storage key from the client filename (default: "uploads/<id>/<filename>")
+	KeyFunc func(id, filename string) string
+
+	locks sync.Map // upload ID -> *sync.Mutex, serializes operations per session
+}
+
+// NewChunkedUploads creates an upload manager staging chunks in stagingDir
+// Sessions not updated within ttl are removed by Cleanup.
+func NewChunkedUploads(store Storage, stagingDir string, ttl time.Duration) (*ChunkedUploads, error) {
+	if err := os.MkdirAll(stagingDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &ChunkedUploads{
+		store: store,
+		dir:   stagingDir,
+		ttl:   ttl,
+		KeyFunc: func(id, filename string) string {
+			return "uploads/" + id + "/" + path.Base(strings.ReplaceAll(filename, "\\", "/"))
+		},
+	}, nil
+}
+
+// Init starts a new upload session for a file of the given total size
+func (u *ChunkedUploads) Init(filename, contentType string, size int64) (*UploadSession, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("upload size must be positive")
+	}
+	if u.MaxSize > 0 && size > u.MaxSize {
+		return nil, ErrFileTooLarge
+	}
+	id, err := newUploadID()
+	if err != nil {
+		return nil, err
+	}
+	key, err := cleanKey(u.KeyFunc(id, filename))
+	if err != nil {
+		return nil, err
+	}
+	now := clock.Now().UTC()
+	s := &UploadSession{
+		ID: id, Key: key, Filename: filename, ContentType: contentType,
+		Size: size, CreatedAt: now, UpdatedAt: now,
+	}
+	f, err := os.Create(u.partPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	f.Close()
+	if err := u.save(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Status returns the session, including the offset the client should resume from
+func (u *ChunkedUploads) Status(id string) (*UploadSession, error) {
+	return u.load(id)
+}
+
+// Append writes a chunk starting at offset; offset must equal the current session offset
+// Returns the updated session. Data beyond the declared size is rejected.
+func (u *ChunkedUploads) Append(id string, offset int64, r io.Reader) (*UploadSession, error) {
+	defer u.lock(id)()
+	s, err := u.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if offset != s.Offset {
+		return s, ErrOffsetMismatch
+	}
+
+	f, err := os.OpenFile(u.partPath(id), os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	// Read one extra byte to detect oversize chunks
+	remaining := s.Size - s.Offset
+	n, copyErr := io.Copy(f, io.LimitReader(r, remaining+1))
+	if n > remaining {
+		_ = f.Truncate(s.Offset)
+		return s, ErrFileTooLarge
+	}
+	// Keep partial writes from interrupted connections; the client resumes from the new offset
+	s.Offset += n
+	s.UpdatedAt = clock.Now().UTC()
+	if err := u.save(s); err != nil {
+		return nil, err
+	}
+	if copyErr != nil {
+		return s, fmt.Errorf("chunk interrupted: %w", copyErr)
+	}
+	return s, nil
+}
+
+// Complete moves the assembled file into storage and removes the staged data
+func (u *ChunkedUploads) Complete(ctx context.Context, id string) (*UploadSession, error) {
+	defer u.lock(id)()
+	s, err := u.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if s.Offset != s.Size {
+		return s, ErrUploadIncomplete
+	}
+	f, err := os.Open(u.partPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open upload file: %w", err)
+	}
+	err = u.store.Put(ctx, s.Key, f, s.ContentType)
+	f.Close()
+	if err != nil {
+		return s, fmt.Errorf("failed to store upload: %w", err)
+	}
+	u.remove(id)
+	return s, nil
+}
+
+// Abort cancels an upload and deletes staged data
+func (u *ChunkedUploads) Abort(id string) error {
+	defer u.lock(id)()
+	if _, err := u.load(id); err != nil {
+		return err
+	}
+	u.remove(id)
+	return nil
+}
+
+// Cleanup removes sessions that have not been updated within the TTL
+// Each session is locked while it is checked, so an upload receiving a chunk is never
+// removed halfway. Staged files left without a session (a crash between writing the data
+// and the session file) are deleted once they are older than the TTL.
+// Returns the number of removed sessions and orphaned files.
+func (u *ChunkedUploads) Cleanup() (int, error) {
+	entries, err := os.ReadDir(u.dir)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := clock.Now().Add(-u.ttl)
+	removed := 0
+	for _, e := range entries {
+		name := e.Name()
+		if id, ok := strings.CutSuffix(name, ".json"); ok {
+			if u.removeStale(id, cutoff) {
+				removed++
+			}
+			continue
+		}
+		id, ok := strings.CutSuffix(name, ".part")
+		if !ok {
+			id, ok = strings.CutSuffix(name, ".json.tmp")
+		}
+		if !ok {
+			continue
+		}
+		if _, err := os.Stat(u.metaPath(id)); err == nil {
+			continue // the session decides
+		}
+		info, err := e.Info()
+		if err == nil && info.ModTime().Before(cutoff) && os.Remove(filepath.Join(u.dir, name)) == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// removeStale removes session id when it is unreadable or older than cutoff
+func (u *ChunkedUploads) removeStale(id string, cutoff time.Time) bool {
+	defer u.lock(id)()
+	s, err := u.load(id)
+	if errors.Is(err, ErrUploadNotFound) && validUploadID(id) {
+		return false // completed or aborted since ReadDir
+	}
+	if err != nil || s.UpdatedAt.Before(cutoff) {
+		u.remove(id)
+		return true
+	}
+	return false
+}
+
+// StartCleanup runs Cleanup every interval until ctx is done
+func (u *ChunkedUploads) StartCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := u.Cleanup(); err != nil {
+				log.Printf("chunked upload cleanup error: %v", err)
+			} else if n > 0 {
+				log.Printf("chunked upload cleanup: removed %d abandoned uploads", n)
+			}
+		}
+	}
+}
+
+func (u *ChunkedUploads) partPath(id string) string { return filepath.Join(u.dir, id+".part") }
+func (u *ChunkedUploads) metaPath(id string) string { return filepath.Join(u.dir, id+".json") }
+
+func (u *ChunkedUploads) load(id string) (*UploadSession, error) {
+	if !validUploadID(id) {
+		return nil, ErrUploadNotFound
+	}
+	data, err := os.ReadFile(u.metaPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s UploadSession
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("corrupt upload session: %w", err)
+	}
+	return &s, nil
+}
+
+func (u *ChunkedUploads) save(s *UploadSession) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := u.metaPath(s.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save upload session: %w", err)
+	}
+	return os.Rename(tmp, u.metaPath(s.ID))
+}
+
+func (u *ChunkedUploads) remove(id string) {
+	os.Remove(u.partPath(id))
+	os.Remove(u.metaPath(id))
+	u.locks.Delete(id)
+}
+
+// lock acquires the per-session mutex and returns its unlock function
+func (u *ChunkedUploads) lock(id string) func() {
+	m, _ := u.locks.LoadOrStore(id, &sync.Mutex{})
+	mu := m.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+func newUploadID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validUploadID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
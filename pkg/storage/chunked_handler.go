@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/request"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// Handler exposes the chunked upload protocol over HTTP under prefix:
+//
+//	POST   {prefix}                 {"filename","content_type","size"} -> session (201)
+//	GET    {prefix}/{id}            -> session (use "offset" to resume)
+//	PATCH  {prefix}/{id}            body = chunk, header Upload-Offset -> session
+//	POST   {prefix}/{id}/complete   -> session (file moved to storage)
+//	DELETE {prefix}/{id}            -> 204
+//
+// Wrap it with your auth middleware. Example (Echo):
+//
+//	e.Any("/uploads*", echo.WrapHandler(uploads.Handler("/uploads")))
+func (u *ChunkedUploads) Handler(prefix string) http.Handler {
+	prefix = "/" + strings.Trim(prefix, "/")
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST "+prefix, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Filename    string `json:"filename"`
+			ContentType string `json:"content_type"`
+			Size        int64  `json:"size"`
+		}
+		if err := request.ParseJSON(r, &req); err != nil || req.Filename == "" {
+			response.BadRequest(w, "filename and size are required")
+			return
+		}
+		s, err := u.Init(req.Filename, req.ContentType, req.Size)
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		response.Created(w, "upload started", s)
+	})
+
+	mux.HandleFunc("GET "+prefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s, err := u.Status(r.PathValue("id"))
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		w.Header().Set("Upload-Offset", strconv.FormatInt(s.Offset, 10))
+		response.Success(w, "upload status", s)
+	})
+
+	mux.HandleFunc("PATCH "+prefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+		if err != nil || offset < 0 {
+			response.BadRequest(w, "invalid Upload-Offset header")
+			return
+		}
+		s, err := u.Append(r.PathValue("id"), offset, r.Body)
+		if s != nil {
+			w.Header().Set("Upload-Offset", strconv.FormatInt(s.Offset, 10))
+		}
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		response.Success(w, "chunk received", s)
+	})
+
+	mux.HandleFunc("POST "+prefix+"/{id}/complete", func(w http.ResponseWriter, r *http.Request) {
+		s, err := u.Complete(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		response.Success(w, "upload completed", map[string]interface{}{
+			"key": s.Key, "url": u.store.URL(s.Key), "size": s.Size, "content_type": s.ContentType,
+		})
+	})
+
+	mux.HandleFunc("DELETE "+prefix+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if err := u.Abort(r.PathValue("id")); err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		response.NoContent(w)
+	})
+
+	return mux
+}
+
+func writeUploadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrUploadNotFound):
+		response.NotFound(w, "upload not found")
+	case errors.Is(err, ErrOffsetMismatch):
+		response.Error(w, http.StatusConflict, "upload offset mismatch")
+	case errors.Is(err, ErrFileTooLarge):
+		response.Error(w, http.StatusRequestEntityTooLarge, "file too large")
+	case errors.Is(err, ErrUploadIncomplete):
+		response.BadRequest(w, "upload is incomplete")
+	case errors.Is(err, ErrInvalidKey):
+		response.BadRequest(w, "invalid filename")
+	default:
+		response.InternalServerError(w, "upload failed")
+	}
+}
@@ -0,0 +1,320 @@
+package storage
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yoockh/go-api-utils/internal/sigv4"
+)
+
+// S3Config holds configuration for S3-compatible object storage
+type S3Config struct {
+	Bucket          string
+	Region          string
+	Endpoint        string // e.g. "http://localhost:9000" for MinIO; empty uses AWS
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool   // use endpoint/bucket/key instead of bucket.endpoint/key
+	PublicURL       string // optional public/CDN prefix returned by URL()
+}
+
+// S3 stores files in an S3-compatible bucket using the REST API directly
+// Example:
+//
+//	store, err := storage.NewS3(storage.S3Config{
+//	    Bucket: "uploads", Region: "us-east-1",
+//	    AccessKeyID: os.Getenv("S3_ACCESS_KEY_ID"), SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+//	})
+type S3 struct {
+	config   S3Config
+	endpoint *url.URL
+	signer   *sigv4.Signer
+	client   *http.Client
+}
+
+// NewS3 creates an S3 storage backend
+func NewS3(config S3Config) (*S3, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket cannot be empty")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3 credentials must be set")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint: %s", endpoint)
+	}
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+
+	return &S3{
+		config:   config,
+		endpoint: u,
+		signer: &sigv4.Signer{
+			AccessKey: config.AccessKeyID,
+			SecretKey: config.SecretAccessKey,
+			Region:    config.Region,
+			Service:   "s3",
+		},
+		client: &http.Client{},
+	}, nil
+}
+
+// objectURL builds the request URL for key ("" means the bucket itself)
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	path := "/" + key
+	if s.config.PathStyle {
+		path = "/" + s.config.Bucket + path
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+	}
+	u.Path = path
+	u.RawPath = sigv4.URIEncode(path, false)
+	return &u
+}
+
+func (s *S3) do(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	s.signer.Sign(req, sigv4.UnsignedPayload, time.Now())
+	return s.client.Do(req)
+}
+
+// Put uploads r to key. Readers without a known length are spooled to a temp
+// file first, since S3 requires Content-Length on PUT.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	body, size, cleanup, err := sizedReader(r)
+	if err != nil {
+		return fmt.Errorf("failed to prepare upload: %w", err)
+	}
+	defer cleanup()
+
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(ctx, http.MethodPut, s.objectURL(key), body, size, header)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return s3Error("upload", resp)
+	}
+	return nil
+}
+
+// Get downloads key; returns ErrNotFound if it doesn't exist
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(ctx, http.MethodGet, s.objectURL(key), nil, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, s3Error("download", resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes key; deleting a missing key is not an error
+func (s *S3) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodDelete, s.objectURL(key), nil, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return s3Error("delete", resp)
+	}
+	return nil
+}
+
+type listBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// List returns all objects whose key starts with prefix (follows pagination)
+func (s *S3) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	token := ""
+	for {
+		u := s.objectURL("")
+		q := url.Values{}
+		q.Set("list-type", "2")
+		if prefix != "" {
+			q.Set("prefix", prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = strings.ReplaceAll(q.Encode(), "+", "%20")
+
+		resp, err := s.do(ctx, http.MethodGet, u, nil, 0, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		if resp.StatusCode/100 != 2 {
+			err := s3Error("list", resp)
+			resp.Body.Close()
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode list response: %w", err)
+		}
+
+		for _, c := range result.Contents {
+			objects = append(objects, Object{Key: c.Key, Size: c.Size, LastModified: c.LastModified})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// URL returns the public URL for key (PublicURL prefix if configured)
+func (s *S3) URL(key string) string {
+	key, _ = cleanKey(key)
+	if s.config.PublicURL != "" {
+		return s.config.PublicURL + "/" + key
+	}
+	return s.objectURL(key).String()
+}
+
+// s3Error builds an error from a non-2xx S3 response (reads at most 4KB of body)
+func s3Error(op string, resp *http.Response) error {
+	var e struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("S3 %s failed (%d %s): %s", op, resp.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Errorf("S3 %s failed with status %d", op, resp.StatusCode)
+}
+
+// sizedReader returns a reader with known length, spooling to a temp file if needed
+func sizedReader(r io.Reader) (io.Reader, int64, func(), error) {
+	noop := func() {}
+	if l, ok := r.(interface{ Len() int }); ok {
+		return r, int64(l.Len()), noop, nil
+	}
+	if sk, ok := r.(io.Seeker); ok {
+		cur, err := sk.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, err := sk.Seek(0, io.SeekEnd)
+			if err == nil {
+				if _, err := sk.Seek(cur, io.SeekStart); err == nil {
+					return r, end - cur, noop, nil
+				}
+			}
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "storage-spool-*")
+	if err != nil {
+		return nil, 0, noop, err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		cleanup()
+		return nil, 0, noop, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, 0, noop, err
+	}
+	return tmp, size, cleanup, nil
+}
+
+// maxPresignExpiry is the longest validity S3 accepts for presigned URLs
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// PresignPut returns a URL the client can PUT the file to directly, bypassing the API server
+// If contentType is set, the upload must send exactly that Content-Type header or S3 rejects it.
+// Example:
+//
+//	u, err := s3store.PresignPut(ctx, "uploads/video.mp4", 15*time.Minute, "video/mp4")
+//	return response.Success(c, "upload url", map[string]string{"url": u})
+func (s *S3) PresignPut(ctx context.Context, key string, expires time.Duration, contentType string) (string, error) {
+	headers := map[string]string{}
+	if contentType != "" {
+		headers["content-type"] = contentType
+	}
+	return s.presign(http.MethodPut, key, expires, headers)
+}
+
+// PresignGet returns a time-limited download URL for key
+// Example:
+//
+//	u, err := s3store.PresignGet(ctx, "invoices/2024-01.pdf", time.Hour)
+func (s *S3) PresignGet(ctx context.Context, key string, expires time.Duration) (string, error) {
+	return s.presign(http.MethodGet, key, expires, nil)
+}
+
+func (s *S3) presign(method, key string, expires time.Duration, headers map[string]string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if expires < time.Second || expires > maxPresignExpiry {
+		return "", fmt.Errorf("presign expiry must be between 1s and %s", maxPresignExpiry)
+	}
+	return s.signer.Presign(method, s.objectURL(key), expires, headers, time.Now()).String(), nil
+}
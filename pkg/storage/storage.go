@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Object describes a stored file returned by List and RangeOpener.Open
+type Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	ETag         string    `json:"etag,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+}
+
+// Storage is a minimal file storage backend
+// Keys are slash-separated paths like "avatars/42.png".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]Object, error)
+	URL(key string) string
+}
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+// Config holds storage backend configuration
+type Config struct {
+	Driver string // "local" (default) or "s3"
+
+	// Local driver
+	LocalDir   string
+	BaseURL    string // public URL prefix for URL(); also used as CDN prefix for s3
+	SigningKey string // HMAC key for Local.SignedURL
+
+	// S3 driver (AWS S3, MinIO, Cloudflare R2, DigitalOcean Spaces, ...)
+	S3 S3Config
+}
+
+// LoadConfig reads storage configuration from environment variables
+// Variables: STORAGE_DRIVER, STORAGE_LOCAL_DIR, STORAGE_BASE_URL, STORAGE_SIGNING_KEY,
+// S3_BUCKET, S3_REGION, S3_ENDPOINT, S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY, S3_PATH_STYLE
+func LoadConfig() Config {
+	return Config{
+		Driver:     getEnv("STORAGE_DRIVER", "local"),
+		LocalDir:   getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		BaseURL:    getEnv("STORAGE_BASE_URL", ""),
+		SigningKey: getEnv("STORAGE_SIGNING_KEY", ""),
+		S3: S3Config{
+			Bucket:          getEnv("S3_BUCKET", ""),
+			Region:          getEnv("S3_REGION", "us-east-1"),
+			Endpoint:        getEnv("S3_ENDPOINT", ""),
+			AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+			PathStyle:       getEnv("S3_PATH_STYLE", "") == "1" || getEnv("S3_PATH_STYLE", "") == "true",
+			PublicURL:       getEnv("STORAGE_BASE_URL", ""),
+		},
+	}
+}
+
+// New creates a Storage backend from config
+// Example:
+//
+//	store, err := storage.New(storage.LoadConfig())
+//	err = store.Put(ctx, "avatars/42.png", file, "image/png")
+func New(cfg Config) (Storage, error) {
+	switch strings.ToLower(cfg.Driver) {
+	case "", "local":
+		l, err := NewLocal(cfg.LocalDir, cfg.BaseURL)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.SigningKey != "" {
+			l.SetSigningKey(cfg.SigningKey)
+		}
+		return l, nil
+	case "s3":
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver: %s", cfg.Driver)
+	}
+}
+
+// FromEnv is a shortcut for New(LoadConfig())
+// Example:
+//
+//	store, err := storage.FromEnv()
+func FromEnv() (Storage, error) {
+	return New(LoadConfig())
+}
+
+// cleanKey normalizes a key and rejects empty or parent-escaping keys
+func cleanKey(key string) (string, error) {
+	key = strings.TrimLeft(strings.ReplaceAll(key, "\\", "/"), "/")
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return key, nil
+}
+
+// getEnv retrieves environment variable or returns default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+var (
+	ErrFileTooLarge   = errors.New("uploaded file is too large")
+	ErrTooManyFiles   = errors.New("too many files in upload")
+	ErrFileType       = errors.New("file type not allowed")
+	ErrNotMultipart   = errors.New("request is not multipart/form-data")
+	ErrNoFileUploaded = errors.New("no file uploaded")
+)
+
+// UploadOptions controls StreamUpload behavior
+type UploadOptions struct {
+	Field        string   // only accept parts from this form field (empty accepts any file field)
+	MaxFileSize  int64    // per-file size cap in bytes (0 = unlimited)
+	MaxFiles     int      // maximum number of files (0 = unlimited)
+	AllowedTypes []string // allowed sniffed content types, e.g. "image/png" or "image/*"
+
+	// KeyFunc builds the storage key for a file; defaults to RandomKey, never the client's
+	// file name, which could overwrite other objects
+	KeyFunc func(filename string) string
+	// Progress is called after each chunk is written with the running byte count
+	Progress func(filename string, written int64)
+}
+
+// UploadedFile describes a file saved by StreamUpload
+type UploadedFile struct {
+	Field       string `json:"field"`
+	Filename    string `json:"filename"`
+	Key         string `json:"key"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// StreamUpload streams multipart file parts from r straight into store
+// Files are never fully buffered in memory; non-file form fields are skipped.
+// Example (net/http):
+//
+//	files, err := storage.StreamUpload(r, store, storage.UploadOptions{
+//	    Field: "file", MaxFileSize: 50 << 20,
+//	    KeyFunc: func(name string) string { return "docs/" + uuid.NewString() + path.Ext(name) },
+//	})
+//
+// Example (Echo):
+//
+//	files, err := storage.StreamUpload(c.Request(), store, opts)
+func StreamUpload(r *http.Request, store Storage, opts UploadOptions) ([]UploadedFile, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrNotMultipart
+	}
+	keyFunc := opts.KeyFunc
+	if keyFunc == nil {
+		keyFunc = RandomKey
+	}
+
+	var files []UploadedFile
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, fmt.Errorf("failed to read multipart body: %w", err)
+		}
+		filename := part.FileName()
+		if filename == "" || (opts.Field != "" && part.FormName() != opts.Field) {
+			part.Close()
+			continue
+		}
+		if opts.MaxFiles > 0 && len(files) >= opts.MaxFiles {
+			part.Close()
+			return files, ErrTooManyFiles
+		}
+
+		// Sniff content type from the first 512 bytes
+		head := make([]byte, 512)
+		n, err := io.ReadFull(part, head)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			part.Close()
+			return files, fmt.Errorf("failed to read file: %w", err)
+		}
+		head = head[:n]
+		contentType := http.DetectContentType(head)
+		if !typeAllowed(contentType, opts.AllowedTypes) {
+			part.Close()
+			return files, fmt.Errorf("%w: %s", ErrFileType, contentType)
+		}
+
+		key := keyFunc(filename)
+		cr := &countingReader{
+			r:        io.MultiReader(bytes.NewReader(head), part),
+			max:      opts.MaxFileSize,
+			filename: filename,
+			progress: opts.Progress,
+		}
+		// Put stores nothing when the reader fails, so there is nothing to clean up; deleting
+		// key here would remove whatever object already had that name
+		err = store.Put(r.Context(), key, cr, contentType)
+		part.Close()
+		if cr.exceeded {
+			return files, ErrFileTooLarge
+		}
+		if err != nil {
+			return files, err
+		}
+
+		files = append(files, UploadedFile{
+			Field:       part.FormName(),
+			Filename:    filename,
+			Key:         key,
+			URL:         store.URL(key),
+			ContentType: contentType,
+			Size:        cr.n,
+		})
+	}
+
+	if len(files) == 0 {
+		return nil, ErrNoFileUploaded
+	}
+	return files, nil
+}
+
+// RandomKey is the default UploadOptions.KeyFunc: a random ID plus the file's extension,
+// lowercased and limited to letters and digits ("Report.PDF" -> "3f9c...e1.pdf")
+func RandomKey(filename string) string {
+	b := make([]byte, 16)
+	rand.Read(b) // never fails since Go 1.24
+	ext := strings.ToLower(path.Ext(path.Base(strings.ReplaceAll(filename, "\\", "/"))))
+	if len(ext) > 10 || strings.ContainsFunc(ext[min(1, len(ext)):], func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	}) {
+		ext = ""
+	}
+	return hex.EncodeToString(b) + ext
+}
+
+// countingReader tracks bytes read, enforces max and reports progress
+type countingReader struct {
+	r        io.Reader
+	n        int64
+	max      int64
+	exceeded bool
+	filename string
+	progress func(filename string, written int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.max > 0 && c.n > c.max {
+		c.exceeded = true
+		return n, ErrFileTooLarge
+	}
+	if n > 0 && c.progress != nil {
+		c.progress(c.filename, c.n)
+	}
+	return n, err
+}
+
+func typeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	for _, a := range allowed {
+		if a == mediaType || (strings.HasSuffix(a, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yoockh/go-api-utils/pkg/database"
+)
+
+// ErrNoTenant is returned by WithTx and SetSearchPath when ctx carries no tenant, so queries
+// can't silently run against the shared schema
+var ErrNoTenant = errors.New("no tenant in context")
+
+// QuoteIdent quotes name as a PostgreSQL identifier, doubling embedded quotes
+// Example:
+//
+//	tenant.QuoteIdent(`acme"; DROP TABLE users; --`) // "acme""; DROP TABLE users; --"
+func QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// SetSearchPath points the search_path of the current transaction at the schema of the tenant
+// of ctx with SET LOCAL, so it is reset on commit or rollback and never leaks to the next
+// user of the pooled connection. Tenants without a schema leave it unchanged.
+// Example (GORM):
+//
+//	err := orm.WithTransaction(db.WithContext(ctx), func(tx *gorm.DB) error {
+//		if err := tenant.SetSearchPath(ctx, tx.Statement.ConnPool); err != nil {
+//			return err
+//		}
+//		return tx.Find(&orders).Error
+//	})
+func SetSearchPath(ctx context.Context, tx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}) error {
+	t, ok := FromContext(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+	if t.Schema == "" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "SET LOCAL search_path TO "+QuoteIdent(t.Schema)); err != nil {
+		return fmt.Errorf("failed to set search_path for tenant %q: %w", t.ID, err)
+	}
+	return nil
+}
+
+// WithTx is database.WithTx with the search_path set to the schema of the tenant of ctx
+// Unqualified table names in fn resolve to the tenant's schema; it fails with ErrNoTenant
+// outside a tenant context.
+// Example:
+//
+//	err := tenant.WithTx(r.Context(), db, func(tx *sql.Tx) error {
+//		_, err := tx.ExecContext(r.Context(), "INSERT INTO orders (total) VALUES ($1)", total)
+//		return err
+//	})
+func WithTx(ctx context.Context, db interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}, fn func(tx *sql.Tx) error, opts ...database.TxOption) error {
+	if _, ok := FromContext(ctx); !ok {
+		return ErrNoTenant
+	}
+	return database.WithTx(ctx, db, func(tx *sql.Tx) error {
+		if err := SetSearchPath(ctx, tx); err != nil {
+			return err
+		}
+		return fn(tx)
+	}, opts...)
+}
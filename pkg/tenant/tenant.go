@@ -0,0 +1,249 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/clock"
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/logging"
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// ErrNotFound is returned by resolvers for unknown or disabled tenants; Middleware answers 404
+var ErrNotFound = errors.New("tenant not found")
+
+// Tenant is the resolved tenant of a request
+type Tenant struct {
+	ID     string
+	Name   string
+	Schema string // PostgreSQL schema holding the tenant's tables, "" for shared tables scoped by ID
+	// Config holds tenant settings loaded by the resolver (plan, limits, feature flags, ...)
+	Config map[string]interface{}
+}
+
+// Resolver loads a tenant by ID, returning ErrNotFound for unknown tenants
+type Resolver interface {
+	Resolve(ctx context.Context, id string) (*Tenant, error)
+}
+
+// ResolverFunc adapts a function to Resolver
+// Example:
+//
+//	resolver := tenant.ResolverFunc(func(ctx context.Context, id string) (*tenant.Tenant, error) {
+//		var t tenant.Tenant
+//		err := db.QueryRowContext(ctx, `SELECT id, name, schema FROM tenants WHERE id = $1 AND active`, id).
+//			Scan(&t.ID, &t.Name, &t.Schema)
+//		if errors.Is(err, sql.ErrNoRows) {
+//			return nil, tenant.ErrNotFound
+//		}
+//		return &t, err
+//	})
+type ResolverFunc func(ctx context.Context, id string) (*Tenant, error)
+
+// Resolve calls f
+func (f ResolverFunc) Resolve(ctx context.Context, id string) (*Tenant, error) {
+	return f(ctx, id)
+}
+
+// Static resolves tenants from a fixed set, for configuration files and tests
+func Static(tenants ...*Tenant) Resolver {
+	byID := make(map[string]*Tenant, len(tenants))
+	for _, t := range tenants {
+		byID[t.ID] = t
+	}
+	return ResolverFunc(func(_ context.Context, id string) (*Tenant, error) {
+		if t, ok := byID[id]; ok {
+			return t, nil
+		}
+		return nil, ErrNotFound
+	})
+}
+
+type cacheEntry struct {
+	tenant  *Tenant
+	err     error
+	expires time.Time
+}
+
+// Cache wraps r so each tenant is loaded at most once per ttl; unknown tenants are cached
+// too, so probing random subdomains doesn't reach the database
+// Other errors are not cached.
+func Cache(r Resolver, ttl time.Duration) Resolver {
+	var mu sync.Mutex
+	entries := map[string]cacheEntry{}
+	return ResolverFunc(func(ctx context.Context, id string) (*Tenant, error) {
+		mu.Lock()
+		e, ok := entries[id]
+		mu.Unlock()
+		if ok && clock.Now().Before(e.expires) {
+			return e.tenant, e.err
+		}
+
+		t, err := r.Resolve(ctx, id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		mu.Lock()
+		now := clock.Now()
+		for k, old := range entries {
+			if now.After(old.expires) {
+				delete(entries, k)
+			}
+		}
+		entries[id] = cacheEntry{tenant: t, err: err, expires: now.Add(ttl)}
+		mu.Unlock()
+		return t, err
+	})
+}
+
+// Source extracts a tenant ID from a request, "" when it has none
+type Source func(r *http.Request) string
+
+// FromHeader reads the tenant ID from a request header such as X-Tenant-ID
+func FromHeader(name string) Source {
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(name))
+	}
+}
+
+// FromSubdomain reads the tenant ID from the label left of domain: "acme" for
+// acme.example.com with domain "example.com"
+// The bare domain, nested subdomains and names in ignore (e.g. "www", "api") yield "".
+func FromSubdomain(domain string, ignore ...string) Source {
+	suffix := "." + strings.ToLower(strings.Trim(domain, "."))
+	return func(r *http.Request) string {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		sub, ok := strings.CutSuffix(strings.ToLower(host), suffix)
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return ""
+		}
+		for _, name := range ignore {
+			if sub == name {
+				return ""
+			}
+		}
+		return sub
+	}
+}
+
+// FromClaim reads the tenant ID from a claim of the custom token stored by middleware.JWT
+// (auth.GenerateCustomToken), so JWT must run first
+func FromClaim(name string) Source {
+	return func(r *http.Request) string {
+		switch v := middleware.TokenDataFromContext(r.Context())[name].(type) {
+		case string:
+			return v
+		case float64: // numeric IDs decoded from JSON
+			return fmt.Sprint(int64(v))
+		case nil:
+			return ""
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+}
+
+// Config configures Middleware
+type Config struct {
+	Resolver Resolver // required
+	// Sources are tried in order; the first non-empty ID wins (default FromHeader("X-Tenant-ID"))
+	// Put FromClaim first when tokens carry the tenant, so a header can't override it.
+	Sources []Source
+	// Optional lets requests without a tenant ID through without a tenant (public pages,
+	// the sign-up endpoint); unknown IDs still get 404
+	Optional bool
+}
+
+// Middleware resolves the tenant of each request and stores it in the request context
+// Requests without a tenant ID get 400 and unknown tenants 404. The request logger gets
+// tenant_id and error reports get a "tenant" tag. Read the tenant with FromContext or ID.
+// Example:
+//
+//	resolve := tenant.Middleware(tenant.Config{
+//		Resolver: tenant.Cache(resolver, time.Minute),
+//		Sources:  []tenant.Source{tenant.FromClaim("tenant_id"), tenant.FromSubdomain("example.com", "www")},
+//	})
+//	mux.Handle("/api/", middleware.JWT(jwtConfig)(resolve(api)))
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	if cfg.Resolver == nil {
+		panic("tenant resolver cannot be nil")
+	}
+	if len(cfg.Sources) == 0 {
+		cfg.Sources = []Source{FromHeader("X-Tenant-ID")}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := ""
+			for _, src := range cfg.Sources {
+				if id = src(r); id != "" {
+					break
+				}
+			}
+			if id == "" {
+				if cfg.Optional {
+					next.ServeHTTP(w, r)
+					return
+				}
+				response.WriteError(w, r, errs.BadRequest("missing tenant"))
+				return
+			}
+
+			t, err := cfg.Resolver.Resolve(r.Context(), id)
+			if errors.Is(err, ErrNotFound) || (err == nil && t == nil) {
+				response.WriteError(w, r, errs.NotFound("tenant"))
+				return
+			}
+			if err != nil {
+				response.WriteError(w, r, fmt.Errorf("failed to resolve tenant %q: %w", id, err))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), t)))
+		})
+	}
+}
+
+type ctxKey struct{}
+
+// WithTenant returns ctx carrying t, for jobs and consumers working on behalf of a tenant
+// The logger in ctx gets tenant_id and the error-reporting scope a "tenant" tag.
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	ctx = logging.With(ctx, "tenant_id", t.ID)
+	errs.SetTag(ctx, "tenant", t.ID)
+	return context.WithValue(ctx, ctxKey{}, t)
+}
+
+// FromContext returns the tenant stored by Middleware or WithTenant
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(ctxKey{}).(*Tenant)
+	return t, ok
+}
+
+// ID returns the tenant ID of ctx, "" without a tenant
+func ID(ctx context.Context) string {
+	if t, ok := FromContext(ctx); ok {
+		return t.ID
+	}
+	return ""
+}
+
+// Schema returns the database schema of the tenant of ctx, "" without a tenant or schema
+func Schema(ctx context.Context) string {
+	if t, ok := FromContext(ctx); ok {
+		return t.Schema
+	}
+	return ""
+}
+
+// Current returns the tenant of r; ok is false when Middleware let it through without one
+func Current(r *http.Request) (*Tenant, bool) { return FromContext(r.Context()) }
@@ -0,0 +1,53 @@
+package tracing
+
+import "strings"
+
+// SanitizeSQL replaces string and numeric literals with ? and collapses whitespace
+// Bind placeholders ($1, ?) are kept, so parameterized queries are unchanged apart from spacing.
+// Example:
+//
+//	tracing.SanitizeSQL("SELECT * FROM users WHERE email = 'a@b.c' AND id > 10")
+//	// SELECT * FROM users WHERE email = ? AND id > ?
+func SanitizeSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\n' || c == '\t' || c == '\r':
+			space = true
+			continue
+		case c == '\'':
+			// skip to closing quote, treating '' as an escaped quote
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			c = '?'
+		case isDigit(c) && (i == 0 || !isIdentChar(query[i-1])):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			c = '?'
+		}
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// isIdentChar reports characters that make a following digit part of a name or placeholder ($1, t1)
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || isDigit(c) || (c|0x20 >= 'a' && c|0x20 <= 'z')
+}
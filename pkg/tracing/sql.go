@@ -0,0 +1,205 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DB wraps *sql.DB and emits a client span for every Exec/Query call
+// Spans carry the sanitized statement and affected or returned row count, and are children of
+// the request span when called with the request context. Query spans end when the rows are
+// closed, so they cover reading the results.
+// Example:
+//
+//	db := tracing.WrapDB(sqlDB, "main")
+//	rows, err := db.QueryContext(r.Context(), "SELECT id, name FROM products WHERE id = $1", id)
+type DB struct {
+	*sql.DB
+	name string
+}
+
+// WrapDB wraps db; name is recorded as db.namespace
+func WrapDB(db *sql.DB, name string) *DB {
+	return &DB{DB: db, name: name}
+}
+
+// ExecContext runs query inside a span and records rows affected
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := StartQuerySpan(ctx, db.name, query)
+	defer span.End()
+	res, err := db.DB.ExecContext(ctx, query, args...)
+	endExec(span, res, err)
+	return res, err
+}
+
+// QueryContext runs query inside a span that ends on rows.Close
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	ctx, span := StartQuerySpan(ctx, db.name, query)
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	return newRows(span, rows, err)
+}
+
+// QueryRowContext runs query inside a span that ends on row.Scan
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	rows, err := db.QueryContext(ctx, query, args...)
+	return &Row{rows: rows, err: err}
+}
+
+// Exec, Query and QueryRow use context.Background, so their spans start a new trace
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *DB) Query(query string, args ...any) (*Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+func (db *DB) QueryRow(query string, args ...any) *Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// BeginTx starts a transaction whose statements are traced as well
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, name: db.name}, nil
+}
+
+// Tx wraps *sql.Tx with the same tracing as DB
+type Tx struct {
+	*sql.Tx
+	name string
+}
+
+// ExecContext runs query inside a span and records rows affected
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := StartQuerySpan(ctx, tx.name, query)
+	defer span.End()
+	res, err := tx.Tx.ExecContext(ctx, query, args...)
+	endExec(span, res, err)
+	return res, err
+}
+
+// QueryContext runs query inside a span that ends on rows.Close
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	ctx, span := StartQuerySpan(ctx, tx.name, query)
+	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	return newRows(span, rows, err)
+}
+
+// QueryRowContext runs query inside a span that ends on row.Scan
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *Row {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	return &Row{rows: rows, err: err}
+}
+
+// Rows wraps *sql.Rows and ends the query span on Close with the number of rows read
+// Always Close the rows (defer rows.Close()), otherwise the span is never exported.
+type Rows struct {
+	*sql.Rows
+	span trace.Span
+	n    int64
+	once sync.Once
+}
+
+func newRows(span trace.Span, rows *sql.Rows, err error) (*Rows, error) {
+	if err != nil {
+		RecordError(span, err)
+		span.End()
+		return nil, err
+	}
+	return &Rows{Rows: rows, span: span}, nil
+}
+
+// Next advances to the next row and counts it
+func (r *Rows) Next() bool {
+	if !r.Rows.Next() {
+		return false
+	}
+	r.n++
+	return true
+}
+
+// Close closes the rows and ends the span, recording any iteration error
+func (r *Rows) Close() error {
+	err := r.Rows.Close()
+	r.once.Do(func() {
+		r.span.SetAttributes(attribute.Int64("db.response.returned_rows", r.n))
+		if iterErr := r.Rows.Err(); iterErr != nil {
+			RecordError(r.span, iterErr)
+		} else {
+			RecordError(r.span, err)
+		}
+		r.span.End()
+	})
+	return err
+}
+
+// Row is the result of QueryRowContext, like *sql.Row
+type Row struct {
+	rows *Rows
+	err  error
+}
+
+// Scan copies the first row into dest and closes the rows; sql.ErrNoRows when there is none
+func (r *Row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	return r.rows.Close()
+}
+
+// Err returns the error of running the query, if any
+func (r *Row) Err() error {
+	return r.err
+}
+
+// StartQuerySpan starts a client span for a database statement with the sanitized query
+// Used by the GORM plugin; call span.End when the statement finishes.
+func StartQuerySpan(ctx context.Context, dbName, query string) (context.Context, trace.Span) {
+	stmt := SanitizeSQL(query)
+	return Tracer().Start(ctx, operationName(stmt),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.namespace", dbName),
+			attribute.String("db.query.text", stmt),
+		),
+	)
+}
+
+func endExec(span trace.Span, res sql.Result, err error) {
+	if err != nil {
+		RecordError(span, err)
+		return
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", n))
+	}
+}
+
+// operationName returns the leading SQL keyword (SELECT, INSERT, ...) used as span name
+func operationName(stmt string) string {
+	op, _, _ := strings.Cut(strings.TrimLeft(stmt, "( "), " ")
+	if op == "" {
+		return "db.query"
+	}
+	return strings.ToUpper(op)
+}
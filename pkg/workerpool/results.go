@@ -0,0 +1,72 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+// ResultPool is a Pool that collects task results in submission order
+// Example:
+//
+//	rp := workerpool.NewResultPool[Product](ctx, 4)
+//	for _, id := range ids {
+//	    id := id
+//	    rp.Submit(func(ctx context.Context) (Product, error) { return repo.FindByID(ctx, id) })
+//	}
+//	products, err := rp.Wait()
+type ResultPool[R any] struct {
+	pool    *Pool
+	mu      sync.Mutex
+	results []R
+}
+
+// NewResultPool creates a ResultPool with the given concurrency
+func NewResultPool[R any](ctx context.Context, size int, opts ...Option) *ResultPool[R] {
+	return &ResultPool[R]{pool: New(ctx, size, opts...)}
+}
+
+// Submit schedules fn; its result is stored at the index of this call
+// Failed tasks leave the zero value of R in their slot.
+func (rp *ResultPool[R]) Submit(fn func(ctx context.Context) (R, error)) {
+	rp.mu.Lock()
+	idx := len(rp.results)
+	var zero R
+	rp.results = append(rp.results, zero)
+	rp.mu.Unlock()
+
+	rp.pool.Submit(func(ctx context.Context) error {
+		res, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		rp.mu.Lock()
+		rp.results[idx] = res
+		rp.mu.Unlock()
+		return nil
+	})
+}
+
+// Wait blocks until all tasks finish and returns results plus joined errors
+func (rp *ResultPool[R]) Wait() ([]R, error) {
+	err := rp.pool.Wait()
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	return rp.results, err
+}
+
+// Map runs fn over items with bounded concurrency and returns results in input order
+// Use this for parallel fan-out queries in handlers
+// Example:
+//
+//	stats, err := workerpool.Map(c.Request().Context(), 3, []string{"users", "orders", "products"},
+//	    func(ctx context.Context, table string) (int64, error) { return countRows(ctx, table) })
+func Map[T, R any](ctx context.Context, size int, items []T, fn func(ctx context.Context, item T) (R, error), opts ...Option) ([]R, error) {
+	rp := NewResultPool[R](ctx, size, opts...)
+	for _, item := range items {
+		item := item
+		rp.Submit(func(ctx context.Context) (R, error) {
+			return fn(ctx, item)
+		})
+	}
+	return rp.Wait()
+}
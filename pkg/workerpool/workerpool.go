@@ -0,0 +1,128 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// PanicError is returned in place of a task error when the task panicked
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// Option configures a Pool
+type Option func(*Pool)
+
+// WithTaskTimeout gives every task its own context with the given timeout
+func WithTaskTimeout(d time.Duration) Option {
+	return func(p *Pool) {
+		p.taskTimeout = d
+	}
+}
+
+// WithFailFast cancels the pool context (and so all running tasks) on the first error
+func WithFailFast() Option {
+	return func(p *Pool) {
+		p.failFast = true
+	}
+}
+
+// Pool runs submitted tasks on at most size goroutines at a time
+// Submit blocks while the pool is full, so memory stays bounded for large batches.
+// Example:
+//
+//	pool := workerpool.New(ctx, 4)
+//	for _, id := range ids {
+//	    id := id
+//	    pool.Submit(func(ctx context.Context) error { return sendEmail(ctx, id) })
+//	}
+//	err := pool.Wait()
+type Pool struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	sem         chan struct{}
+	wg          sync.WaitGroup
+	taskTimeout time.Duration
+	failFast    bool
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// New creates a Pool bound to ctx with the given concurrency (minimum 1)
+func New(ctx context.Context, size int, opts ...Option) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Pool{ctx: ctx, cancel: cancel, sem: make(chan struct{}, size)}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Submit schedules fn, blocking until a worker slot is free
+// If the pool context is already done, fn is not run and the context error is recorded.
+func (p *Pool) Submit(fn func(ctx context.Context) error) {
+	select {
+	case <-p.ctx.Done():
+		p.record(p.ctx.Err())
+		return
+	case p.sem <- struct{}{}:
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+		if err := p.run(fn); err != nil {
+			p.record(err)
+		}
+	}()
+}
+
+// Wait blocks until all submitted tasks finish and returns their joined errors
+func (p *Pool) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errors.Join(p.errs...)
+}
+
+// run executes fn with a per-task context and converts panics into PanicError
+func (p *Pool) run(fn func(ctx context.Context) error) (err error) {
+	ctx := p.ctx
+	if p.taskTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.taskTimeout)
+		defer cancel()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return fn(ctx)
+}
+
+func (p *Pool) record(err error) {
+	p.mu.Lock()
+	p.errs = append(p.errs, err)
+	p.mu.Unlock()
+	if p.failFast {
+		p.cancel()
+	}
+}
@@ -0,0 +1,453 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+)
+
+// MessageType is the type of a data message
+type MessageType int
+
+// Data message types
+const (
+	TextMessage   MessageType = 1
+	BinaryMessage MessageType = 2
+)
+
+// Close codes (RFC 6455 section 7.4.1)
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001 // server shutting down or client navigating away
+	CloseProtocolError   = 1002
+	CloseUnsupportedData = 1003
+	CloseNoStatus        = 1005 // received close frame without a code
+	CloseAbnormal        = 1006 // connection dropped without a close frame
+	CloseInvalidPayload  = 1007 // text message that is not UTF-8
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseInternalError   = 1011
+	CloseTryAgainLater   = 1013
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+var (
+	// ErrClosed is returned by Send and reads on a closed connection
+	ErrClosed = errors.New("ws: connection closed")
+	// ErrSlowClient is returned by Send when the send buffer is full; the connection is closed
+	ErrSlowClient = errors.New("ws: send buffer full, client too slow")
+	// ErrReadLimit is returned when a message exceeds Config.ReadLimit
+	ErrReadLimit = errors.New("ws: message exceeds read limit")
+)
+
+// CloseError is returned by reads after a close frame; Code is CloseAbnormal when the
+// connection dropped without one
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("ws: closed with code %d", e.Code)
+	}
+	return fmt.Sprintf("ws: closed with code %d: %s", e.Code, e.Reason)
+}
+
+// IsClose reports whether err is a CloseError with one of the codes (any code when none are given)
+// Example:
+//
+//	if err := c.ReadJSON(&msg); err != nil {
+//		if !ws.IsClose(err, ws.CloseNormal, ws.CloseGoingAway) {
+//			logging.FromContext(c.Context()).Warn("websocket read failed", "error", err)
+//		}
+//		return
+//	}
+func IsClose(err error, codes ...int) bool {
+	var ce *CloseError
+	if !errors.As(err, &ce) {
+		return false
+	}
+	if len(codes) == 0 {
+		return true
+	}
+	for _, code := range codes {
+		if ce.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+type outgoing struct {
+	op   byte
+	data []byte
+}
+
+// Conn is a server-side WebSocket connection
+// Reads happen on the goroutine calling ReadMessage / ReadJSON (the read pump); a write pump
+// goroutine sends queued messages and pings. Send and Close are safe for concurrent use.
+type Conn struct {
+	nc          net.Conn
+	br          *bufio.Reader
+	cfg         Config
+	subprotocol string
+
+	ctx     context.Context // request context with the JWT claims; canceled on close
+	cancel  context.CancelFunc
+	release func() // untracks the connection from Config.Drain
+
+	send      chan outgoing
+	closeReq  chan *CloseError
+	closeOnce sync.Once
+	done      chan struct{}
+	wmu       sync.Mutex // serializes frame writes
+	rmu       sync.Mutex // serializes reads
+}
+
+func newConn(ctx context.Context, nc net.Conn, br *bufio.Reader, cfg Config, subprotocol string, release func()) *Conn {
+	ctx, cancel := context.WithCancel(ctx)
+	c := &Conn{
+		nc:          nc,
+		br:          br,
+		cfg:         cfg,
+		subprotocol: subprotocol,
+		ctx:         ctx,
+		cancel:      cancel,
+		release:     release,
+		send:        make(chan outgoing, cfg.SendBuffer),
+		closeReq:    make(chan *CloseError, 1),
+		done:        make(chan struct{}),
+	}
+	nc.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	go c.writePump()
+	return c
+}
+
+// Context returns the request context carrying the JWT claims (see middleware.UserIDFromContext);
+// it is canceled when the connection closes
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// UserID returns the authenticated user ID, 0 for anonymous connections
+func (c *Conn) UserID() uint {
+	return middleware.UserIDFromContext(c.ctx)
+}
+
+// Subprotocol returns the negotiated subprotocol, "" if none
+func (c *Conn) Subprotocol() string {
+	return c.subprotocol
+}
+
+// RemoteAddr returns the client's network address
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.nc.RemoteAddr()
+}
+
+// Done is closed once the connection is closed
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Send queues a message for the write pump without blocking
+// When the send buffer is full the client is not keeping up: the connection is closed with
+// CloseTryAgainLater and ErrSlowClient is returned, so one slow reader never stalls the sender.
+func (c *Conn) Send(typ MessageType, data []byte) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case c.send <- outgoing{op: byte(typ), data: data}:
+		return nil
+	case <-c.done:
+		return ErrClosed
+	default:
+		go c.Close(CloseTryAgainLater, "client too slow")
+		return ErrSlowClient
+	}
+}
+
+// SendJSON queues v encoded as a JSON text message
+func (c *Conn) SendJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode websocket message: %w", err)
+	}
+	return c.Send(TextMessage, data)
+}
+
+// ReadMessage returns the next data message, answering pings and extending the read
+// deadline on every frame. A close frame from the client is answered and returned as
+// *CloseError; any read error closes the connection.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	c.rmu.Lock()
+	defer c.rmu.Unlock()
+
+	var (
+		typ     MessageType
+		message []byte
+		started bool
+	)
+	for {
+		fin, op, payload, err := c.readFrame(int64(len(message)))
+		if err != nil {
+			return 0, nil, c.readFailed(err)
+		}
+		c.nc.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+
+		switch op {
+		case opPing:
+			c.writeFrame(opPong, payload)
+			continue
+		case opPong:
+			continue
+		case opClose:
+			ce := parseClose(payload)
+			reply := ce.Code
+			if reply == CloseNoStatus {
+				reply = CloseNormal
+			}
+			c.Close(reply, "")
+			return 0, nil, ce
+		case opText, opBinary:
+			if started {
+				return 0, nil, c.protocolError("new message inside a fragmented message")
+			}
+			typ, started = MessageType(op), true
+		case opContinuation:
+			if !started {
+				return 0, nil, c.protocolError("continuation frame without a message")
+			}
+		default:
+			return 0, nil, c.protocolError(fmt.Sprintf("unknown opcode %d", op))
+		}
+
+		message = append(message, payload...)
+		if !fin {
+			continue
+		}
+		if typ == TextMessage && !utf8.Valid(message) {
+			c.Close(CloseInvalidPayload, "invalid UTF-8")
+			return 0, nil, &CloseError{Code: CloseInvalidPayload, Reason: "invalid UTF-8"}
+		}
+		return typ, message, nil
+	}
+}
+
+// ReadJSON reads the next message and decodes it into v
+// A message that is not valid JSON for v is returned as an error without closing the connection.
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid websocket message: %w", err)
+	}
+	return nil
+}
+
+// Close sends the queued messages, a close frame with code and reason, and closes the
+// connection. It waits at most Config.WriteWait and is a no-op on a closed connection.
+func (c *Conn) Close(code int, reason string) error {
+	select {
+	case c.closeReq <- &CloseError{Code: code, Reason: reason}:
+	default: // a close is already in progress
+	}
+	select {
+	case <-c.done:
+	case <-time.After(c.cfg.WriteWait):
+		c.teardown()
+	}
+	return nil
+}
+
+func (c *Conn) writePump() {
+	ticker := time.NewTicker(c.cfg.PingInterval)
+	defer ticker.Stop()
+	var shutdown, draining <-chan struct{}
+	if c.cfg.Context != nil {
+		shutdown = c.cfg.Context.Done()
+	}
+	if c.cfg.Drain != nil {
+		draining = c.cfg.Drain.Draining()
+	}
+	for {
+		select {
+		case ce := <-c.closeReq:
+			c.flushAndClose(ce)
+			return
+		case <-shutdown:
+			c.flushAndClose(&CloseError{Code: CloseGoingAway, Reason: "server shutting down"})
+			return
+		case <-draining:
+			c.flushAndClose(&CloseError{Code: CloseGoingAway, Reason: "server shutting down"})
+			return
+		case <-c.done:
+			return
+		case m := <-c.send:
+			if err := c.writeFrame(m.op, m.data); err != nil {
+				c.teardown()
+				return
+			}
+		case <-ticker.C:
+			if err := c.writeFrame(opPing, nil); err != nil {
+				c.teardown()
+				return
+			}
+		}
+	}
+}
+
+// flushAndClose writes the queued messages and the close frame, then closes the connection
+func (c *Conn) flushAndClose(ce *CloseError) {
+	defer c.teardown()
+	for len(c.send) > 0 { // only the pump receives, so this never blocks
+		m := <-c.send
+		if err := c.writeFrame(m.op, m.data); err != nil {
+			return
+		}
+	}
+	payload := make([]byte, 2, 2+len(ce.Reason))
+	binary.BigEndian.PutUint16(payload, uint16(ce.Code))
+	payload = append(payload, ce.Reason[:min(len(ce.Reason), 123)]...)
+	c.writeFrame(opClose, payload)
+}
+
+// teardown closes the network connection and cancels the context; it runs once
+// done is closed first so a read interrupted by nc.Close reports ErrClosed.
+func (c *Conn) teardown() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.cancel()
+		c.nc.Close()
+		c.release()
+	})
+}
+
+// readFailed closes the connection after a failed read and returns the error for the caller
+func (c *Conn) readFailed(err error) error {
+	var ce *CloseError
+	switch {
+	case errors.As(err, &ce):
+		return ce
+	case errors.Is(err, ErrReadLimit):
+		c.Close(CloseMessageTooBig, "message too big")
+		return err
+	}
+	select {
+	case <-c.done:
+		return ErrClosed // closed by the server, e.g. on shutdown
+	default:
+	}
+	c.teardown()
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return &CloseError{Code: CloseAbnormal}
+	}
+	return err
+}
+
+func (c *Conn) protocolError(reason string) error {
+	c.Close(CloseProtocolError, reason)
+	return &CloseError{Code: CloseProtocolError, Reason: reason}
+}
+
+// readFrame reads one frame; read is the size of the message read so far
+func (c *Conn) readFrame(read int64) (fin bool, op byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, op = head[0]&0x80 != 0, head[0]&0x0f
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, c.protocolError("reserved bits set")
+	}
+	if head[1]&0x80 == 0 {
+		return false, 0, nil, c.protocolError("client frames must be masked")
+	}
+
+	length := int64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]) & (1<<63 - 1))
+	}
+	if op >= opClose && (length > 125 || !fin) {
+		return false, 0, nil, c.protocolError("invalid control frame")
+	}
+	if op < opClose && read+length > c.cfg.ReadLimit {
+		return false, 0, nil, ErrReadLimit
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame writes one unmasked, unfragmented frame
+func (c *Conn) writeFrame(op byte, data []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	head := make([]byte, 2, 10+len(data))
+	head[0] = 0x80 | op
+	switch n := len(data); {
+	case n <= 125:
+		head[1] = byte(n)
+	case n <= 0xffff:
+		head[1] = 126
+		head = binary.BigEndian.AppendUint16(head, uint16(n))
+	default:
+		head[1] = 127
+		head = binary.BigEndian.AppendUint64(head, uint64(n))
+	}
+	c.nc.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+	_, err := c.nc.Write(append(head, data...))
+	return err
+}
+
+func parseClose(payload []byte) *CloseError {
+	if len(payload) < 2 {
+		return &CloseError{Code: CloseNoStatus}
+	}
+	return &CloseError{Code: int(binary.BigEndian.Uint16(payload)), Reason: string(payload[2:])}
+}
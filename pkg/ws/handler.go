@@ -0,0 +1,225 @@
+package ws
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/yoockh/go-api-utils/pkg/errs"
+	"github.com/yoockh/go-api-utils/pkg/graceful"
+	"github.com/yoockh/go-api-utils/pkg/middleware"
+	"github.com/yoockh/go-api-utils/pkg/response"
+)
+
+// acceptGUID is appended to Sec-WebSocket-Key to compute Sec-WebSocket-Accept (RFC 6455)
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Config configures the WebSocket handshake and connections
+type Config struct {
+	// JWT authenticates the handshake with the same tokens as middleware.JWT; nil accepts
+	// anonymous connections. The token comes from the Authorization header or, because
+	// browsers can't set headers on WebSocket requests, from the TokenParam query parameter.
+	JWT *middleware.JWTConfig
+	// TokenParam is the query parameter holding the token (default "token")
+	TokenParam string
+	// CheckOrigin accepts or rejects the Origin of the handshake; the default accepts requests
+	// without Origin (non-browser clients) and same-host origins
+	CheckOrigin func(r *http.Request) bool
+	// Subprotocols lists the supported subprotocols in order of preference
+	Subprotocols []string
+	// Context closes every connection with CloseGoingAway when done, e.g. the context
+	// canceled on SIGTERM
+	Context context.Context
+	// Drain tracks the connections for graceful.Shutdown: once it drains, new handshakes get
+	// 503 and open connections are closed with CloseGoingAway, or force-closed at the deadline
+	Drain *graceful.Group
+	// ReadLimit caps the size of an incoming message (default 1 MiB)
+	ReadLimit int64
+	// PingInterval between pings sent by the write pump (default 30s)
+	PingInterval time.Duration
+	// PongWait is how long a connection may stay silent before it is dropped (default 60s);
+	// every frame from the client, pongs included, restarts it
+	PongWait time.Duration
+	// WriteWait bounds each write and Close (default 10s)
+	WriteWait time.Duration
+	// SendBuffer is the number of queued outgoing messages per connection (default 32)
+	SendBuffer int
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.TokenParam == "" {
+		cfg.TokenParam = "token"
+	}
+	if cfg.CheckOrigin == nil {
+		cfg.CheckOrigin = SameOrigin
+	}
+	if cfg.ReadLimit <= 0 {
+		cfg.ReadLimit = 1 << 20
+	}
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = 30 * time.Second
+	}
+	if cfg.PongWait <= 0 {
+		cfg.PongWait = 60 * time.Second
+	}
+	if cfg.PongWait <= cfg.PingInterval {
+		cfg.PongWait = cfg.PingInterval * 2
+	}
+	if cfg.WriteWait <= 0 {
+		cfg.WriteWait = 10 * time.Second
+	}
+	if cfg.SendBuffer <= 0 {
+		cfg.SendBuffer = 32
+	}
+	return cfg
+}
+
+// SameOrigin accepts handshakes without an Origin header and those whose Origin host is the request host
+func SameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// Handler upgrades requests to WebSocket connections and calls fn with each one
+// fn runs on the request goroutine and is the read pump: it reads messages until an error.
+// The connection is closed with CloseNormal when fn returns. Handshake failures are answered
+// in the standard error envelope: 401 for missing or invalid tokens, 403 for rejected
+// origins, 400 or 426 for malformed handshakes, 503 while Config.Drain is draining.
+// Example:
+//
+//	wsCfg := ws.Config{JWT: &middleware.JWTConfig{SecretKey: secret}, Context: ctx}
+//	mux.Handle("GET /ws", ws.Handler(wsCfg, func(c *ws.Conn) {
+//		for {
+//			var msg struct{ OrderID int64 `json:"order_id"` }
+//			if err := c.ReadJSON(&msg); err != nil {
+//				return
+//			}
+//			status, err := orders.Status(c.Context(), c.UserID(), msg.OrderID)
+//			if err != nil {
+//				c.SendJSON(map[string]string{"error": "order not found"})
+//				continue
+//			}
+//			c.SendJSON(status)
+//		}
+//	}))
+//
+//	// browser: new WebSocket("wss://api.example.com/ws?token=" + jwt)
+func Handler(cfg Config, fn func(c *Conn)) http.Handler {
+	cfg = cfg.withDefaults()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrade(w, r, cfg)
+		if err != nil {
+			response.WriteError(w, r, err)
+			return
+		}
+		defer c.Close(CloseNormal, "")
+		fn(c)
+	})
+}
+
+// Upgrade authenticates the handshake and switches r to the WebSocket protocol
+// On error nothing has been written; answer it with response.WriteError. Handler is
+// simpler for most uses; Upgrade suits handlers that decide per request.
+func Upgrade(w http.ResponseWriter, r *http.Request, cfg Config) (*Conn, error) {
+	return upgrade(w, r, cfg.withDefaults())
+}
+
+func upgrade(w http.ResponseWriter, r *http.Request, cfg Config) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		return nil, errs.New(http.StatusMethodNotAllowed, "", "websocket handshake must use GET")
+	}
+	if !headerHas(r.Header, "Connection", "upgrade") || !headerHas(r.Header, "Upgrade", "websocket") {
+		return nil, errs.BadRequest("not a websocket handshake")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, errs.New(http.StatusUpgradeRequired, "upgrade_required", "unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if nonce, err := base64.StdEncoding.DecodeString(key); err != nil || len(nonce) != 16 {
+		return nil, errs.BadRequest("invalid Sec-WebSocket-Key")
+	}
+	if !cfg.CheckOrigin(r) {
+		return nil, errs.Forbidden("origin not allowed")
+	}
+
+	ctx := context.WithoutCancel(r.Context()) // the connection outlives the handshake request
+	if cfg.JWT != nil {
+		var err error
+		if ctx, err = middleware.AuthenticateRequest(ctx, r, *cfg.JWT, cfg.TokenParam); err != nil {
+			return nil, err
+		}
+	}
+	subprotocol := negotiate(r.Header, cfg.Subprotocols)
+
+	release := func() {}
+	var tracked atomic.Pointer[Conn]
+	if cfg.Drain != nil {
+		var err error
+		release, err = cfg.Drain.Track(func() {
+			if c := tracked.Load(); c != nil {
+				c.teardown()
+			}
+		})
+		if err != nil {
+			w.Header().Set("Retry-After", "5")
+			return nil, errs.New(http.StatusServiceUnavailable, "", err.Error())
+		}
+	}
+
+	nc, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("websocket upgrade needs an HTTP/1.1 connection: %w", err)
+	}
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n"
+	if subprotocol != "" {
+		handshake += "Sec-WebSocket-Protocol: " + subprotocol + "\r\n"
+	}
+	nc.SetDeadline(time.Now().Add(cfg.WriteWait))
+	if _, err := nc.Write([]byte(handshake + "\r\n")); err != nil {
+		nc.Close()
+		release()
+		return nil, fmt.Errorf("failed to write websocket handshake: %w", err)
+	}
+	nc.SetDeadline(time.Time{})
+	c := newConn(ctx, nc, brw.Reader, cfg, subprotocol, release)
+	tracked.Store(c)
+	return c, nil
+}
+
+// negotiate picks the first supported subprotocol offered by the client
+func negotiate(h http.Header, supported []string) string {
+	for _, want := range supported {
+		if headerHas(h, "Sec-WebSocket-Protocol", want) {
+			return want
+		}
+	}
+	return ""
+}
+
+// headerHas reports whether the comma-separated header contains token (case-insensitive)
+func headerHas(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, part := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}